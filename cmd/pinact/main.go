@@ -9,7 +9,9 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/logrus-error/logerr"
 	"github.com/suzuki-shunsuke/pinact/pkg/cli"
+	"github.com/suzuki-shunsuke/pinact/pkg/i18n"
 	"github.com/suzuki-shunsuke/pinact/pkg/log"
+	"github.com/suzuki-shunsuke/pinact/pkg/trace"
 )
 
 var (
@@ -23,6 +25,7 @@ type HasExitCode interface {
 }
 
 func main() {
+	i18n.SetUp()
 	logE := log.New(version)
 	if err := core(logE); err != nil {
 		logerr.WithError(logE, err).Fatal("pinact failed")
@@ -43,5 +46,14 @@ func core(logE *logrus.Entry) error {
 	}
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
-	return runner.Run(ctx, os.Args...) //nolint:wrapcheck
+	tracer := trace.New(version)
+	ctx, span := trace.Start(trace.WithTracer(ctx, tracer), "pinact")
+	err := runner.Run(ctx, os.Args...)
+	span.SetError(err)
+	span.End()
+	// Spans are exported even if the run is canceled.
+	if err := tracer.Flush(context.WithoutCancel(ctx)); err != nil {
+		logerr.WithError(logE, err).Warn("export traces")
+	}
+	return err //nolint:wrapcheck
 }
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newCacheCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "Manage the cache of GitHub API responses",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "warm",
+				Usage: "Prefetch tags, releases, and commit hashes of actions",
+				Description: `Prefetch tags, releases, and commit hashes of actions used in target files and save them to the cache file.
+Subsequent runs with the cache file hardly call GitHub API.
+This is also useful to ship a warm cache in a Docker image.
+
+$ pinact --cache-file .pinact-cache.json cache warm
+$ pinact --cache-file .pinact-cache.json run
+
+You can also pass workflow file paths as arguments.
+
+$ pinact --cache-file .pinact-cache.json cache warm .github/workflows/test.yaml
+`,
+				Action: r.cacheWarmAction,
+			},
+		},
+	}
+}
+
+func (r *Runner) cacheWarmAction(c *cli.Context) error {
+	ctrl, err := r.newController(c, &run.InputNew{
+		RecordFilePath: c.String("record"),
+		ReplayFilePath: c.String("replay"),
+		GitHubAPIURL:   c.String("github-api-url"),
+	})
+	if err != nil {
+		return err
+	}
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
+	}
+	return ctrl.WarmCache(c.Context, r.LogE, &run.ParamWarmCache{ //nolint:wrapcheck
+		WorkflowFilePaths: c.Args().Slice(),
+		ConfigFilePath:    c.String("config"),
+		PWD:               pwd,
+		Now:               time.Now(),
+	})
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+)
+
+// getParamCheckRun returns a commit where a check run is created from environment variables of GitHub Actions.
+// In pull_request events, GITHUB_SHA is a merge commit, so the head commit of the pull request is used instead.
+func getParamCheckRun() (*run.ParamCheckRun, error) {
+	owner, repo, ok := strings.Cut(os.Getenv("GITHUB_REPOSITORY"), "/")
+	if !ok {
+		return nil, errors.New("GITHUB_REPOSITORY is required to create a check run. Please run pinact on GitHub Actions")
+	}
+	sha, err := getPullRequestHeadSHA(os.Getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		return nil, err
+	}
+	if sha == "" {
+		sha = os.Getenv("GITHUB_SHA")
+	}
+	if sha == "" {
+		return nil, errors.New("GITHUB_SHA is required to create a check run. Please run pinact on GitHub Actions")
+	}
+	return &run.ParamCheckRun{
+		Owner:   owner,
+		Repo:    repo,
+		HeadSHA: sha,
+	}, nil
+}
+
+type pullRequestEventPayload struct {
+	PullRequest *struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+}
+
+func getPullRequestHeadSHA(eventPath string) (string, error) {
+	if eventPath == "" {
+		return "", nil
+	}
+	b, err := os.ReadFile(eventPath)
+	if err != nil {
+		return "", fmt.Errorf("read GITHUB_EVENT_PATH: %w", err)
+	}
+	payload := &pullRequestEventPayload{}
+	if err := json.Unmarshal(b, payload); err != nil {
+		return "", fmt.Errorf("parse GITHUB_EVENT_PATH as JSON: %w", err)
+	}
+	if payload.PullRequest == nil {
+		return "", nil
+	}
+	return payload.PullRequest.Head.SHA, nil
+}
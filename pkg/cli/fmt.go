@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newFmtCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "fmt",
+		Usage: "Normalize version annotations",
+		Description: `Normalize version annotations to the canonical form without calling GitHub API.
+The separator is normalized to " # ", "tag=" is removed, and the prefix "v" is added.
+
+e.g.
+
+uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683  #  tag=4.2.2
+=> uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2
+
+$ pinact fmt
+
+You can also pass workflow file paths as arguments.
+
+$ pinact fmt .github/workflows/test.yaml
+`,
+		Action: r.fmtAction,
+	}
+}
+
+func (r *Runner) fmtAction(c *cli.Context) error {
+	ctrl, err := run.New(c.Context, &run.InputNew{})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
+	}
+	return ctrl.Fmt(r.LogE, &run.ParamFmt{ //nolint:wrapcheck
+		WorkflowFilePaths: c.Args().Slice(),
+		ConfigFilePath:    c.String("config"),
+		PWD:               pwd,
+		Now:               time.Now(),
+		Stderr:            r.Stderr,
+	})
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newGenerateWorkflowCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "generate-workflow",
+		Usage: "Generate a GitHub Actions workflow running pinact",
+		Description: `Generate a GitHub Actions workflow running pinact.
+Actions in the generated workflow are pinned by pinact itself.
+
+--type selects the workflow.
+
+- update: Update actions and create pull requests by pinact pr periodically
+- check: Check pull requests and post suggestions as reviews by reviewdog
+
+If --type isn't set, you're asked.
+
+$ pinact generate-workflow --type update --schedule "0 0 * * 1" --group-by major
+
+By default, the workflow is generated to .github/workflows/pinact.yml.
+You can also pass the file path.
+
+$ pinact generate-workflow --type check .github/workflows/pinact-check.yml
+`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "type",
+				Usage: "Workflow type. Either update or check",
+			},
+			&cli.StringFlag{
+				Name:  "schedule",
+				Usage: "Cron expression of the update workflow. The default value is '0 0 * * 1'",
+			},
+			&cli.StringFlag{
+				Name:  "group-by",
+				Usage: "--group-by of pinact pr in the update workflow. One of all, action, and major",
+				Value: run.GroupByAll,
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite the workflow file if it already exists",
+			},
+		},
+		Action: r.generateWorkflowAction,
+	}
+}
+
+func (r *Runner) generateWorkflowAction(c *cli.Context) error {
+	ctrl, err := r.newController(c, &run.InputNew{
+		RecordFilePath: c.String("record"),
+		ReplayFilePath: c.String("replay"),
+		GitHubAPIURL:   c.String("github-api-url"),
+	})
+	if err != nil {
+		return err
+	}
+	defer ctrl.SaveCache(r.LogE)
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
+	}
+	return ctrl.GenerateWorkflow(c.Context, r.LogE, &run.ParamGenerateWorkflow{ //nolint:wrapcheck
+		Type:           c.String("type"),
+		OutputFilePath: c.Args().First(),
+		Schedule:       c.String("schedule"),
+		GroupBy:        c.String("group-by"),
+		Force:          c.Bool("force"),
+		ConfigFilePath: c.String("config"),
+		PWD:            pwd,
+		Now:            time.Now(),
+		Stdin:          r.Stdin,
+		Stderr:         r.Stderr,
+	})
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"os"
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newHookCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "hook",
+		Usage: "Run pinact as a hook of the pre-commit framework",
+		Description: `Run pinact as a hook of the pre-commit framework (https://pre-commit.com).
+
+Only files passed as arguments are processed.
+If any file is modified, the command fails so that the commit is aborted.
+If no file is passed, the command does nothing.
+
+If the environment variable PRE_COMMIT_OFFLINE is 1, pinact doesn't call GitHub API and doesn't modify files.
+Instead, the command fails if actions aren't pinned.
+
+$ pinact hook .github/workflows/test.yaml
+`,
+		Action: r.hookAction,
+	}
+}
+
+func (r *Runner) hookAction(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return nil
+	}
+	ctrl, err := r.newController(c, &run.InputNew{
+		GitHubAPIURL: c.String("github-api-url"),
+		Version:      r.getVersionInfo().Version,
+	})
+	if err != nil {
+		return err
+	}
+	defer ctrl.SaveCache(r.LogE)
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
+	}
+	return ctrl.Run(c.Context, r.LogE, &run.ParamRun{ //nolint:wrapcheck
+		WorkflowFilePaths: c.Args().Slice(),
+		ConfigFilePath:    c.String("config"),
+		PWD:               pwd,
+		Now:               time.Now(),
+		// pre-commit passes only staged files, so the confirmation isn't needed.
+		AssumeYes:    true,
+		Stdin:        r.Stdin,
+		Stderr:       r.Stderr,
+		Offline:      os.Getenv("PRE_COMMIT_OFFLINE") == "1",
+		FailOnChange: true,
+		// pre-commit runs hooks in parallel with different files, so the lock isn't needed.
+		NoLock: true,
+		// Processes in parallel would overwrite the state file shared in the working directory.
+		NoState: true,
+	})
+}
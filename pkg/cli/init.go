@@ -2,7 +2,6 @@ package cli
 
 import (
 	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
-	"github.com/suzuki-shunsuke/pinact/pkg/log"
 	"github.com/urfave/cli/v2"
 )
 
@@ -25,8 +24,10 @@ $ pinact init .github/pinact.yaml
 }
 
 func (r *Runner) initAction(c *cli.Context) error {
-	ctrl := run.New(c.Context, &run.InputNew{})
-	log.SetLevel(c.String("log-level"), r.LogE)
+	ctrl, err := run.New(c.Context, &run.InputNew{})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
 	configFilePath := c.Args().First()
 	if configFilePath == "" {
 		configFilePath = c.String("config")
@@ -34,5 +35,9 @@ func (r *Runner) initAction(c *cli.Context) error {
 	if configFilePath == "" {
 		configFilePath = ".pinact.yaml"
 	}
-	return ctrl.Init(configFilePath) //nolint:wrapcheck
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
+	}
+	return ctrl.Init(configFilePath, pwd) //nolint:wrapcheck
 }
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newListCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List actions used in target files",
+		Description: `List actions used in target files.
+Files aren't changed.
+
+$ pinact list
+
+If --with-dates is set, dates of pinned commits are also output.
+This calls GitHub API per action, so it's disabled by default.
+This is useful to find stale pins at a glance.
+
+$ pinact list --with-dates
+
+If --check-updates is set, the latest version of each action and whether an update is available are also output.
+This calls GitHub API per action, so it's disabled by default.
+This turns pinact list into a quick status report.
+
+$ pinact list --check-updates
+
+If --used-by is set, the reverse index from actions to lines using them is output.
+This is useful for inventory and impact analysis such as which workflows use actions/upload-artifact v3.
+The format is Markdown by default. You can change it to JSON by --format json.
+
+$ pinact list --used-by
+$ pinact list --used-by --format json
+
+--line-template formats each action with a Go template instead of the table.
+Fields are File, Line, Name, Version, Tag, Date, LatestVersion, and UpdateAvailable.
+Functions ToUpper, ToLower, replace, trimPrefix, trunc, shortSHA, padRight, and padLeft are available.
+
+$ pinact list --line-template '{{padRight 30 .Name}} {{shortSHA .Version}} {{.Tag}}'
+
+You can also pass workflow file paths as arguments.
+
+$ pinact list .github/workflows/test.yaml
+`,
+		Action: r.listAction,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "with-dates",
+				Usage: "Output dates of pinned commits. This calls GitHub API",
+			},
+			&cli.BoolFlag{
+				Name:  "check-updates",
+				Usage: "Output the latest versions of actions and whether updates are available. This calls GitHub API",
+			},
+			&cli.BoolFlag{
+				Name:  "used-by",
+				Usage: "Output the reverse index from actions to lines using them",
+			},
+			&cli.StringFlag{
+				Name:  "line-template",
+				Usage: "A Go template to output each action",
+			},
+			&cli.StringFlag{
+				Name:  "sort",
+				Usage: "The order of actions. file or action. The default value is file",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "The format of --used-by. json or markdown. The default value is markdown",
+			},
+		},
+	}
+}
+
+func (r *Runner) listAction(c *cli.Context) error {
+	ctrl, err := r.newController(c, &run.InputNew{
+		RecordFilePath: c.String("record"),
+		ReplayFilePath: c.String("replay"),
+		GitHubAPIURL:   c.String("github-api-url"),
+	})
+	if err != nil {
+		return err
+	}
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
+	}
+	return ctrl.List(c.Context, r.LogE, &run.ParamList{ //nolint:wrapcheck
+		WorkflowFilePaths: c.Args().Slice(),
+		ConfigFilePath:    c.String("config"),
+		PWD:               pwd,
+		Now:               time.Now(),
+		WithDates:         c.Bool("with-dates"),
+		CheckUpdates:      c.Bool("check-updates"),
+		UsedBy:            c.Bool("used-by"),
+		LineTemplate:      c.String("line-template"),
+		Sort:              c.String("sort"),
+		Format:            c.String("format"),
+		Stdout:            r.Stdout,
+		Stderr:            r.Stderr,
+	})
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/suzuki-shunsuke/pinact/pkg/lsp"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newLSPCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "lsp",
+		Usage: "Run a language server",
+		Description: `Run a language server speaking the Language Server Protocol over the standard input and output.
+The server reports unpinned and outdated actions as diagnostics and provides code actions to fix them.
+
+$ pinact lsp
+
+Files aren't modified by the server. Editors apply the code actions.
+`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "verify",
+				Aliases: []string{"v"},
+				Usage:   "Verify if pairs of commit SHA and version are correct",
+			},
+			&cli.BoolFlag{
+				Name:    "update",
+				Aliases: []string{"u"},
+				Usage:   "Report outdated actions",
+			},
+		},
+		Action: r.lspAction,
+	}
+}
+
+func (r *Runner) lspAction(c *cli.Context) error {
+	ctrl, err := r.newController(c, &run.InputNew{
+		Update:         c.Bool("update"),
+		RecordFilePath: c.String("record"),
+		ReplayFilePath: c.String("replay"),
+		GitHubAPIURL:   c.String("github-api-url"),
+	})
+	if err != nil {
+		return err
+	}
+	defer ctrl.SaveCache(r.LogE)
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
+	}
+	linter, err := ctrl.NewLinter(r.LogE, &run.ParamLint{
+		ConfigFilePath: c.String("config"),
+		PWD:            pwd,
+		IsVerify:       c.Bool("verify"),
+		Now:            time.Now(),
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	return lsp.New(linter, r.Stdin, r.Stdout).Serve(c.Context, r.LogE) //nolint:wrapcheck
+}
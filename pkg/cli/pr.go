@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newPRCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "pr",
+		Usage: "Create pull requests updating actions",
+		Description: `Update actions to latest versions and create pull requests.
+Commits are created via GitHub API, so the local repository isn't modified.
+The local files must be same with the base branch.
+
+$ pinact pr --repo suzuki-shunsuke/pinact
+
+Updates are grouped into pull requests by --group-by.
+
+- all: All updates are grouped into one pull request
+- action: A pull request is created per action
+- major: Major updates are created per action, and minor and patch updates are grouped into one pull request
+
+If a pull request of the group already exists, its branch is updated.
+`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "repo",
+				Usage:   "Repository where pull requests are created. The format is <owner>/<repo>",
+				EnvVars: []string{"GITHUB_REPOSITORY"},
+			},
+			&cli.StringFlag{
+				Name:  "base",
+				Usage: "Base branch of pull requests. The default value is the default branch",
+			},
+			&cli.StringFlag{
+				Name:  "group-by",
+				Usage: "How updates are grouped into pull requests. One of all, action, and major",
+				Value: run.GroupByAll,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Output pull requests which would be created without creating them",
+			},
+		},
+		Action: r.prAction,
+	}
+}
+
+func (r *Runner) prAction(c *cli.Context) error {
+	owner, repo, ok := strings.Cut(c.String("repo"), "/")
+	if !ok {
+		return errors.New("--repo is required. The format is <owner>/<repo>")
+	}
+	ctrl, err := r.newController(c, &run.InputNew{
+		Update:         true,
+		RecordFilePath: c.String("record"),
+		ReplayFilePath: c.String("replay"),
+		GitHubAPIURL:   c.String("github-api-url"),
+	})
+	if err != nil {
+		return err
+	}
+	defer ctrl.SaveCache(r.LogE)
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
+	}
+	return ctrl.CreatePullRequests(c.Context, r.LogE, &run.ParamPR{ //nolint:wrapcheck
+		WorkflowFilePaths: c.Args().Slice(),
+		ConfigFilePath:    c.String("config"),
+		PWD:               pwd,
+		Owner:             owner,
+		Repo:              repo,
+		Base:              c.String("base"),
+		GroupBy:           c.String("group-by"),
+		DryRun:            c.Bool("dry-run"),
+		Now:               time.Now(),
+	})
+}
@@ -1,11 +1,10 @@
 package cli
 
 import (
-	"fmt"
-	"os"
+	"errors"
+	"time"
 
 	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
-	"github.com/suzuki-shunsuke/pinact/pkg/log"
 	"github.com/urfave/cli/v2"
 )
 
@@ -30,29 +29,257 @@ $ pinact run .github/actions/foo/action.yaml .github/actions/bar/action.yaml
 				Aliases: []string{"v"},
 				Usage:   "Verify if pairs of commit SHA and version are correct",
 			},
+			&cli.StringFlag{
+				Name:  "verify-only",
+				Usage: "Verify only actions whose names match the regular expression such as ^(suzuki-shunsuke|int128)/. This implies --verify and reduces API calls",
+			},
 			&cli.BoolFlag{
 				Name:    "update",
 				Aliases: []string{"u"},
 				Usage:   "Update actions to latest versions",
 			},
+			&cli.BoolFlag{
+				Name:  "check-duplicate-versions",
+				Usage: "Warn if the same action is used at multiple versions in a job",
+			},
+			&cli.BoolFlag{
+				Name:  "check-annotation-consistency",
+				Usage: "Warn if the same commit hash is annotated with different tags across files",
+			},
+			&cli.BoolFlag{
+				Name:  "fix-annotation-consistency",
+				Usage: "Normalize version annotations of the same commit hash to the tag verified by GitHub API. This implies --check-annotation-consistency",
+			},
+			&cli.BoolFlag{
+				Name:  "check-vulnerabilities",
+				Usage: "Warn if actions have known vulnerabilities by OSV.dev",
+			},
+			&cli.BoolFlag{
+				Name:  "check-transitive",
+				Usage: "Report actions used by called reusable workflows transitively and warn if they aren't pinned",
+			},
+			&cli.BoolFlag{
+				Name:  "verify-attestations",
+				Usage: "Warn if pinned commits of actions don't have SLSA provenance by GitHub artifact attestations",
+			},
+			&cli.BoolFlag{
+				Name:  "why",
+				Usage: "Output which configuration rule makes pinact ignore an action",
+			},
+			&cli.BoolFlag{
+				Name:  "skip-archived",
+				Usage: "Don't update actions whose repositories are archived. This is used with --update",
+			},
+			&cli.BoolFlag{
+				Name:  "security-only",
+				Usage: "Update only actions whose current versions are compromised or have known vulnerabilities by OSV.dev. This is used with --update",
+			},
+			&cli.BoolFlag{
+				Name:  "annotate-only",
+				Usage: "Only add or repair version annotations of actions pinned to commit hashes. Commit hashes aren't changed",
+			},
+			&cli.StringSliceFlag{
+				Name:  "job",
+				Usage: "Process only the given jobs. This option can be set multiple times",
+			},
+			&cli.StringFlag{
+				Name:  "step-name",
+				Usage: "Process only steps whose names match the regular expression",
+			},
+			&cli.StringFlag{
+				Name:  "include-file",
+				Usage: "Process only files whose paths match the regular expression. Paths are relative to the current directory",
+			},
+			&cli.StringFlag{
+				Name:  "exclude-file",
+				Usage: "Don't process files whose paths match the regular expression. Paths are relative to the current directory",
+			},
+			&cli.StringSliceFlag{
+				Name:  "include",
+				Usage: "A regular expression of action names that pinact processes. This is appended to includes of the configuration. This option can be set multiple times",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude",
+				Usage: "A regular expression of action names that pinact ignores. This is appended to excludes of the configuration. This option can be set multiple times",
+			},
+			&cli.StringSliceFlag{
+				Name:  "include-owner",
+				Usage: "Process only actions of the given repository owners. Owners are compared exactly and case-insensitively. This option can be set multiple times",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude-owner",
+				Usage: "Ignore actions of the given repository owners. Owners are compared exactly and case-insensitively. This option can be set multiple times",
+			},
+			&cli.BoolFlag{
+				Name:  "report-unused-rules",
+				Usage: "Warn if rules of the configuration file never match anything. This is useful to prune stale rules",
+			},
+			&cli.StringFlag{
+				Name:  "max-pin-age",
+				Usage: "Warn if actions are pinned to commits older than the duration. The unit d (days) is available in addition to h, m, and s. e.g. 365d",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Don't update actions whose current versions were released within the duration. The unit d (days) is available in addition to h, m, and s. e.g. 30d",
+			},
+			&cli.BoolFlag{
+				Name:    "assume-yes",
+				Aliases: []string{"y"},
+				Usage:   "Modify files without confirmation even if the number of changed files exceeds confirm_threshold",
+				EnvVars: []string{"PINACT_ASSUME_YES"},
+			},
+			&cli.BoolFlag{
+				Name:  "resume",
+				Usage: "Continue the previous run which failed midway. Files processed by the previous run are skipped",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format. If this is json, findings and their fixes are output as JSON instead of modifying files. This is useful for editor integrations. If this is rdjson, findings are output in reviewdog Diagnostic Format. checkstyle and junit output findings in XML for CI systems such as Jenkins",
+			},
+			&cli.StringFlag{
+				Name:  "sort",
+				Usage: "The order of findings in outputs such as --format, --report-sarif, and --report-html. file, action, or severity. The default value is file",
+			},
+			&cli.BoolFlag{
+				Name:  "fix",
+				Usage: "Modify files even if --format is set, so that findings are output and fixed in one run",
+			},
+			&cli.BoolFlag{
+				Name:  "check-run",
+				Usage: "Create a GitHub Check Run with annotations of findings. This is available on GitHub Actions and requires the permission checks:write",
+			},
+			&cli.StringFlag{
+				Name:    "stats-file",
+				Usage:   "Append the summary of the run to the file as JSON Lines. You can show the trend by pinact stats",
+				EnvVars: []string{"PINACT_STATS_FILE"},
+			},
+			&cli.StringFlag{
+				Name:  "report-html",
+				Usage: "Write a standalone HTML report of findings, stats, and updates of actions to the file",
+			},
+			&cli.StringFlag{
+				Name:  "report-sarif",
+				Usage: "Write findings to the file in SARIF for GitHub code scanning. Files are still modified",
+			},
+			&cli.StringFlag{
+				Name:  "baseline",
+				Usage: "A baseline file of accepted findings. Findings in the baseline are neither reported nor fail the run",
+			},
+			&cli.StringFlag{
+				Name:  "write-baseline",
+				Usage: "Write fingerprints of findings to the file as a baseline. The findings don't fail the run",
+			},
+			&cli.StringFlag{
+				Name:    "github-output",
+				Usage:   "Write results of the run such as changed, changed_files, would_change, and findings to the file in the format of GITHUB_OUTPUT. On GitHub Actions, this is enabled by default. If this is empty, results aren't written",
+				EnvVars: []string{"GITHUB_OUTPUT"},
+			},
+			&cli.BoolFlag{
+				Name:    "no-lock",
+				Usage:   "Don't create a lock file guarding against simultaneous pinact processes in the same repository",
+				EnvVars: []string{"PINACT_NO_LOCK"},
+			},
+			&cli.BoolFlag{
+				Name:  "estimate",
+				Usage: "Output the approximate number of API calls needed by the run without calling API. Files aren't modified",
+			},
+			&cli.StringFlag{
+				Name:    "notification-webhook-url",
+				Usage:   "Post a summary of the run to the webhook URL such as a Slack incoming webhook",
+				EnvVars: []string{"PINACT_NOTIFICATION_WEBHOOK_URL"},
+			},
 		},
 	}
 }
 
 func (r *Runner) runAction(c *cli.Context) error {
-	ctrl := run.New(c.Context, &run.InputNew{
-		Update: c.Bool("update"),
+	if c.Bool("annotate-only") && c.Bool("update") {
+		return errors.New("--annotate-only and --update can't be used together")
+	}
+	ctrl, err := r.newController(c, &run.InputNew{
+		Update:         c.Bool("update"),
+		RecordFilePath: c.String("record"),
+		ReplayFilePath: c.String("replay"),
+		GitHubAPIURL:   c.String("github-api-url"),
+		Version:        r.getVersionInfo().Version,
 	})
-	log.SetLevel(c.String("log-level"), r.LogE)
-	pwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("get the current directory: %w", err)
+		return err
+	}
+	defer ctrl.SaveCache(r.LogE)
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
 	}
 	param := &run.ParamRun{
-		WorkflowFilePaths: c.Args().Slice(),
-		ConfigFilePath:    c.String("config"),
-		PWD:               pwd,
-		IsVerify:          c.Bool("verify"),
+		WorkflowFilePaths:          c.Args().Slice(),
+		ConfigFilePath:             c.String("config"),
+		PWD:                        pwd,
+		IsVerify:                   c.Bool("verify"),
+		VerifyOnly:                 c.String("verify-only"),
+		CheckDuplicateVersions:     c.Bool("check-duplicate-versions"),
+		CheckAnnotationConsistency: c.Bool("check-annotation-consistency"),
+		FixAnnotationConsistency:   c.Bool("fix-annotation-consistency"),
+		CheckVulnerabilities:       c.Bool("check-vulnerabilities"),
+		CheckTransitive:            c.Bool("check-transitive"),
+		VerifyAttestations:         c.Bool("verify-attestations"),
+		Why:                        c.Bool("why"),
+		SkipArchived:               c.Bool("skip-archived"),
+		SecurityOnly:               c.Bool("security-only"),
+		AnnotateOnly:               c.Bool("annotate-only"),
+		Jobs:                       c.StringSlice("job"),
+		StepName:                   c.String("step-name"),
+		IncludeFile:                c.String("include-file"),
+		ExcludeFile:                c.String("exclude-file"),
+		Includes:                   c.StringSlice("include"),
+		Excludes:                   c.StringSlice("exclude"),
+		IncludeOwners:              c.StringSlice("include-owner"),
+		ExcludeOwners:              c.StringSlice("exclude-owner"),
+		ReportUnusedRules:          c.Bool("report-unused-rules"),
+		MaxPinAge:                  c.String("max-pin-age"),
+		Since:                      c.String("since"),
+		Now:                        time.Now(),
+		StatsFilePath:              c.String("stats-file"),
+		AssumeYes:                  c.Bool("assume-yes"),
+		Resume:                     c.Bool("resume"),
+		Format:                     c.String("format"),
+		Sort:                       c.String("sort"),
+		Fix:                        c.Bool("fix"),
+		ReportHTMLFilePath:         c.String("report-html"),
+		ReportSARIFFilePath:        c.String("report-sarif"),
+		BaselineFilePath:           c.String("baseline"),
+		WriteBaselineFilePath:      c.String("write-baseline"),
+		NotificationWebhookURL:     c.String("notification-webhook-url"),
+		GitHubOutputFilePath:       c.String("github-output"),
+		Estimate:                   c.Bool("estimate"),
+		NoLock:                     c.Bool("no-lock"),
+		Stdout:                     r.Stdout,
+		Stdin:                      terminalReader(r.Stdin),
+		Stderr:                     r.Stderr,
+		TokenFromStdin:             c.String("github-token-file") == "-",
+	}
+	resolveRunPaths(param)
+	if c.Bool("check-run") {
+		checkRun, err := getParamCheckRun()
+		if err != nil {
+			return err
+		}
+		param.CheckRun = checkRun
 	}
 	return ctrl.Run(c.Context, r.LogE, param) //nolint:wrapcheck
 }
+
+// resolveRunPaths resolves relative paths of input and output files against param.PWD so that they respect -C.
+// Target files and the configuration file are resolved by the controller.
+func resolveRunPaths(param *run.ParamRun) {
+	for _, p := range []*string{
+		&param.ReportHTMLFilePath,
+		&param.ReportSARIFFilePath,
+		&param.BaselineFilePath,
+		&param.WriteBaselineFilePath,
+		&param.StatsFilePath,
+		&param.GitHubOutputFilePath,
+	} {
+		*p = resolvePath(param.PWD, *p)
+	}
+}
@@ -2,10 +2,18 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/suzuki-shunsuke/pinact/pkg/keyring"
+	"github.com/suzuki-shunsuke/pinact/pkg/log"
 	"github.com/urfave/cli/v2"
 )
 
@@ -33,12 +41,29 @@ func (r *Runner) Run(ctx context.Context, args ...string) error {
 		Usage:    "Pin GitHub Actions versions. https://github.com/suzuki-shunsuke/pinact",
 		Version:  r.LDFlags.Version + " (" + r.LDFlags.Commit + ")",
 		Compiled: compiledDate,
+		Reader:   r.Stdin,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:    "log-level",
 				Usage:   "log level",
 				EnvVars: []string{"PINACT_LOG_LEVEL"},
 			},
+			&cli.BoolFlag{
+				Name:    "quiet",
+				Aliases: []string{"q"},
+				Usage:   "Suppress informational logs. Warnings and errors are still output",
+				EnvVars: []string{"PINACT_QUIET"},
+			},
+			&cli.StringFlag{
+				Name:    "log-file",
+				Usage:   "Output structured logs as JSON to the file in addition to the standard error output",
+				EnvVars: []string{"PINACT_LOG_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    "log-file-level",
+				Usage:   "log level of --log-file. This is independent of --log-level and --quiet",
+				EnvVars: []string{"PINACT_LOG_FILE_LEVEL"},
+			},
 			&cli.StringFlag{
 				Name: "config",
 				Aliases: []string{
@@ -47,14 +72,217 @@ func (r *Runner) Run(ctx context.Context, args ...string) error {
 				Usage:   "configuration file path",
 				EnvVars: []string{"PINACT_CONFIG"},
 			},
+			&cli.StringFlag{
+				Name:    "chdir",
+				Aliases: []string{"C"},
+				Usage:   "Run as if pinact was started in the given directory instead of the current working directory",
+			},
+			&cli.StringFlag{
+				Name:    "github-api-url",
+				Usage:   "GitHub API base URL. This is useful to access github.com via a proxy. The default value is https://api.github.com/",
+				EnvVars: []string{"PINACT_GITHUB_API_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "github-token-file",
+				Usage:   "Read a GitHub access token from the file instead of the environment variable GITHUB_TOKEN. If this is -, the token is read from the standard input",
+				EnvVars: []string{"PINACT_GITHUB_TOKEN_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    "cache-file",
+				Usage:   "Cache GitHub API responses in the file. You can prefetch them by pinact cache warm",
+				EnvVars: []string{"PINACT_CACHE_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    "cache-checksum",
+				Usage:   "The expected SHA256 checksum of the cache file of --cache-file. If this is set, the cache file is verified by it instead of the checksum file next to the cache file",
+				EnvVars: []string{"PINACT_CACHE_CHECKSUM"},
+			},
+			&cli.StringFlag{
+				Name:    "record",
+				Usage:   "Record interactions with GitHub API to the file. This is useful to reproduce bugs",
+				EnvVars: []string{"PINACT_RECORD"},
+			},
+			&cli.StringFlag{
+				Name:    "replay",
+				Usage:   "Replay interactions with GitHub API recorded by --record instead of calling GitHub API",
+				EnvVars: []string{"PINACT_REPLAY"},
+			},
 		},
 		EnableBashCompletion: true,
+		Before:               r.setLog,
 		Commands: []*cli.Command{
 			r.newVersionCommand(),
 			r.newRunCommand(),
 			r.newInitCommand(),
+			r.newWorkspaceCommand(),
+			r.newStatsCommand(),
+			r.newHookCommand(),
+			r.newFmtCommand(),
+			r.newUnpinCommand(),
+			r.newListCommand(),
+			r.newCacheCommand(),
+			r.newLSPCommand(),
+			r.newServeCommand(),
+			r.newPRCommand(),
+			r.newTokenCommand(),
+			r.newGenerateWorkflowCommand(),
 		},
 	}
 
 	return app.RunContext(ctx, args) //nolint:wrapcheck
 }
+
+// getPWD returns the effective working directory.
+// If --chdir (-C) is set, it's used instead of the current working directory.
+func getPWD(c *cli.Context) (string, error) {
+	if dir := c.String("chdir"); dir != "" {
+		pwd, err := filepath.Abs(dir)
+		if err != nil {
+			return "", fmt.Errorf("get the absolute path of --chdir: %w", err)
+		}
+		return pwd, nil
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("get the current directory: %w", err)
+	}
+	return pwd, nil
+}
+
+// resolvePath resolves a relative path against pwd.
+// An empty path and - meaning the standard input are returned as is.
+func resolvePath(pwd, p string) string {
+	if p == "" || p == "-" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(pwd, p)
+}
+
+// setLog configures logs by global options.
+func (r *Runner) setLog(c *cli.Context) error {
+	log.SetLevel(c.String("log-level"), r.LogE)
+	if c.Bool("quiet") {
+		log.SetQuiet()
+	}
+	logFilePath := c.String("log-file")
+	if logFilePath == "" {
+		return nil
+	}
+	f, err := os.OpenFile(logFilePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644) //nolint:mnd,gosec
+	if err != nil {
+		return fmt.Errorf("open the log file: %w", err)
+	}
+	// The log file isn't closed explicitly because errors are logged until pinact exits.
+	if err := log.AddFileOutput(f, c.String("log-file-level")); err != nil {
+		return err //nolint:wrapcheck
+	}
+	return nil
+}
+
+// newController creates a controller and loads the cache file if --cache-file is set.
+func (r *Runner) newController(c *cli.Context, input *run.InputNew) (*run.Controller, error) {
+	token, _, err := getGitHubToken(c)
+	if err != nil {
+		return nil, err
+	}
+	input.GitHubToken = token
+	ctrl, err := run.New(c.Context, input)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	if checksum := c.String("cache-checksum"); checksum != "" && c.String("cache-file") == "" {
+		return nil, errors.New("--cache-checksum requires --cache-file")
+	}
+	if cacheFilePath := c.String("cache-file"); cacheFilePath != "" {
+		if err := ctrl.LoadCache(r.LogE, cacheFilePath, c.String("cache-checksum")); err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+	}
+	return ctrl, nil
+}
+
+const (
+	tokenSourceFile    = "--github-token-file"
+	tokenSourceStdin   = "standard input"
+	tokenSourceEnv     = "GITHUB_TOKEN"
+	tokenSourceKeyring = "keyring"
+)
+
+// getGitHubToken returns a GitHub access token and its source.
+// The token is searched in the following order.
+//
+// 1. The file of --github-token-file
+// 2. The environment variable GITHUB_TOKEN
+// 3. The keyring if PINACT_KEYRING_ENABLED is true. The token of --github-api-url is used
+//
+// If no token is found, it returns empty strings.
+func getGitHubToken(c *cli.Context) (string, string, error) {
+	if p := c.String("github-token-file"); p != "" {
+		// A relative path is resolved against the directory of -C.
+		pwd, err := getPWD(c)
+		if err != nil {
+			return "", "", err
+		}
+		token, err := readGitHubToken(c, resolvePath(pwd, p))
+		if err != nil {
+			return "", "", err
+		}
+		if p == "-" {
+			return token, tokenSourceStdin, nil
+		}
+		return token, tokenSourceFile, nil
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token, tokenSourceEnv, nil
+	}
+	if !keyringEnabled() {
+		return "", "", nil
+	}
+	token, err := keyring.Get(c.Context, c.String("github-api-url"))
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return "", "", nil
+		}
+		return "", "", err //nolint:wrapcheck
+	}
+	return token, tokenSourceKeyring, nil
+}
+
+// terminalReader returns the standard input if it's a terminal, otherwise nil.
+// Prompts are shown only if the standard input is a terminal because nobody can answer them in CI.
+func terminalReader(stdin io.Reader) io.Reader {
+	f, ok := stdin.(*os.File)
+	if !ok {
+		return nil
+	}
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	return f
+}
+
+func keyringEnabled() bool {
+	return os.Getenv("PINACT_KEYRING_ENABLED") == "true"
+}
+
+// readGitHubToken reads a GitHub access token from the file of --github-token-file.
+// This is useful to mount secrets as files in containers.
+// If the file path is -, the token is read from the standard input.
+func readGitHubToken(c *cli.Context, p string) (string, error) {
+	var b []byte
+	var err error
+	if p == "-" {
+		b, err = io.ReadAll(c.App.Reader)
+	} else {
+		b, err = os.ReadFile(p)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read a GitHub access token: %w", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", errors.New("the GitHub access token is empty")
+	}
+	return token, nil
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+// newContext returns a context with global options for tests.
+func newContext(t *testing.T, app *cli.App, args ...string) *cli.Context {
+	t.Helper()
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.String("chdir", "", "")
+	set.String("github-token-file", "", "")
+	set.String("github-api-url", "", "")
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(app, set, nil)
+}
+
+func Test_getPWD(t *testing.T) {
+	t.Parallel()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []struct {
+		name string
+		args []string
+		exp  string
+	}{
+		{
+			name: "current directory",
+			exp:  wd,
+		},
+		{
+			name: "relative path is relative to the current directory",
+			args: []string{"-chdir", "foo"},
+			exp:  filepath.Join(wd, "foo"),
+		},
+		{
+			name: "absolute path",
+			args: []string{"-chdir", "/repo"},
+			exp:  "/repo",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			pwd, err := getPWD(newContext(t, &cli.App{}, d.args...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pwd != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, pwd)
+			}
+		})
+	}
+}
+
+func Test_readGitHubToken(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	tokenFilePath := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFilePath, []byte("github_pat_file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFilePath := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFilePath, []byte(" \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data := []struct {
+		name  string
+		path  string
+		stdin string
+		exp   string
+		isErr bool
+	}{
+		{
+			name: "file",
+			path: tokenFilePath,
+			exp:  "github_pat_file",
+		},
+		{
+			name:  "stdin",
+			path:  "-",
+			stdin: "  github_pat_stdin\n",
+			exp:   "github_pat_stdin",
+		},
+		{
+			name:  "empty file",
+			path:  emptyFilePath,
+			isErr: true,
+		},
+		{
+			name:  "empty stdin",
+			path:  "-",
+			isErr: true,
+		},
+		{
+			name:  "file not found",
+			path:  filepath.Join(dir, "not-found"),
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			c := newContext(t, &cli.App{Reader: strings.NewReader(d.stdin)})
+			token, err := readGitHubToken(c, d.path)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if token != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, token)
+			}
+		})
+	}
+}
+
+func Test_getGitHubToken(t *testing.T) { //nolint:paralleltest
+	dir := t.TempDir()
+	tokenFilePath := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFilePath, []byte("github_pat_file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data := []struct {
+		name   string
+		args   []string
+		env    string
+		token  string
+		source string
+	}{
+		{
+			name:   "--github-token-file takes precedence over GITHUB_TOKEN",
+			args:   []string{"-github-token-file", tokenFilePath},
+			env:    "github_pat_env",
+			token:  "github_pat_file",
+			source: tokenSourceFile,
+		},
+		{
+			name:   "relative --github-token-file is relative to -C",
+			args:   []string{"-chdir", dir, "-github-token-file", "token"},
+			token:  "github_pat_file",
+			source: tokenSourceFile,
+		},
+		{
+			name:   "GITHUB_TOKEN",
+			env:    "github_pat_env",
+			token:  "github_pat_env",
+			source: tokenSourceEnv,
+		},
+		{
+			name: "no token",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", d.env)
+			t.Setenv("PINACT_KEYRING_ENABLED", "")
+			token, source, err := getGitHubToken(newContext(t, &cli.App{}, d.args...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token != d.token || source != d.source {
+				t.Fatalf("wanted (%s, %s), got (%s, %s)", d.token, d.source, token, source)
+			}
+		})
+	}
+}
+
+func Test_resolveRunPaths(t *testing.T) {
+	t.Parallel()
+	param := &run.ParamRun{
+		PWD:                 "/repo",
+		ReportSARIFFilePath: "pinact.sarif",
+		BaselineFilePath:    "/etc/pinact/baseline.json",
+		StatsFilePath:       "",
+	}
+	resolveRunPaths(param)
+	if param.ReportSARIFFilePath != "/repo/pinact.sarif" {
+		t.Fatalf("a relative path must be resolved against -C: %s", param.ReportSARIFFilePath)
+	}
+	if param.BaselineFilePath != "/etc/pinact/baseline.json" {
+		t.Fatalf("an absolute path must be kept: %s", param.BaselineFilePath)
+	}
+	if param.StatsFilePath != "" {
+		t.Fatalf("an empty path must be kept: %s", param.StatsFilePath)
+	}
+}
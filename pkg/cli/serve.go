@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 30 * time.Second
+)
+
+func (r *Runner) newServeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Run a webhook server reviewing pull requests",
+		Description: `Run a webhook server which receives GitHub pull_request events.
+pinact checks workflow files changed in pull requests and posts suggestions as reviews.
+Files aren't modified.
+
+$ export GITHUB_TOKEN=xxx # requires pull-requests:write and contents:read permissions
+$ export PINACT_WEBHOOK_SECRET=xxx
+$ pinact serve --addr :8080
+
+PINACT_WEBHOOK_SECRET is required to validate signatures of webhooks.
+If you really run the server without validating them, for instance behind a trusted proxy, pass --insecure.
+
+The configuration file is read from the head commit of each pull request.
+`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "addr",
+				Usage: "TCP address to listen on",
+				Value: ":8080",
+			},
+			&cli.BoolFlag{
+				Name:    "verify",
+				Aliases: []string{"v"},
+				Usage:   "Verify if pairs of commit SHA and version are correct",
+			},
+			&cli.BoolFlag{
+				Name:    "update",
+				Aliases: []string{"u"},
+				Usage:   "Suggest updating actions to latest versions",
+			},
+			&cli.BoolFlag{
+				Name:  "insecure",
+				Usage: "Run the server without PINACT_WEBHOOK_SECRET. Signatures of webhooks aren't validated",
+			},
+			&cli.IntFlag{
+				Name:  "max-concurrent-reviews",
+				Usage: "The number of pull requests reviewed concurrently. Events exceeding it are rejected with 503",
+				Value: 4, //nolint:mnd
+			},
+			&cli.BoolFlag{
+				Name:  "check-run",
+				Usage: "Create check runs with annotations instead of reviews. This requires the permission checks:write instead of pull-requests:write",
+			},
+		},
+		Action: r.serveAction,
+	}
+}
+
+func (r *Runner) serveAction(c *cli.Context) error {
+	secret := os.Getenv("PINACT_WEBHOOK_SECRET")
+	if secret == "" {
+		if !c.Bool("insecure") {
+			return errors.New("PINACT_WEBHOOK_SECRET is required. Pass --insecure to run the server without validating signatures of webhooks")
+		}
+		r.LogE.Warn("PINACT_WEBHOOK_SECRET isn't set, so signatures of webhooks aren't validated")
+	}
+	token, _, err := getGitHubToken(c)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", run.NewWebhookHandler(r.LogE, &run.InputNewWebhookHandler{
+		InputNew: &run.InputNew{
+			Update:       c.Bool("update"),
+			GitHubAPIURL: c.String("github-api-url"),
+			GitHubToken:  token,
+		},
+		Secret:               secret,
+		IsVerify:             c.Bool("verify"),
+		CheckRun:             c.Bool("check-run"),
+		MaxConcurrentReviews: c.Int("max-concurrent-reviews"),
+	}))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{
+		Addr:              c.String("addr"),
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	go func() {
+		<-c.Context.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			r.LogE.WithError(err).Warn("shut down the server")
+		}
+	}()
+	r.LogE.WithField("addr", server.Addr).Info("start a webhook server")
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("run a webhook server: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newStatsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "stats",
+		Usage: "Show the trend of pinning",
+		Description: `Show the trend of pinning recorded by pinact run's --stats-file option.
+
+$ pinact run --stats-file .pinact-stats.jsonl
+$ pinact stats --stats-file .pinact-stats.jsonl
+`,
+		Action: r.statsAction,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "stats-file",
+				Usage:    "stats file path",
+				EnvVars:  []string{"PINACT_STATS_FILE"},
+				Required: true,
+			},
+		},
+	}
+}
+
+func (r *Runner) statsAction(c *cli.Context) error {
+	ctrl, err := run.New(c.Context, &run.InputNew{})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	return ctrl.ShowStats(r.Stdout, c.String("stats-file")) //nolint:wrapcheck
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/keyring"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newTokenCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "token",
+		Usage: "Manage a GitHub access token in the keyring",
+		Description: `Manage a GitHub access token in the keyring of the OS.
+The token in the keyring is used if the environment variable PINACT_KEYRING_ENABLED is true and neither --github-token-file nor GITHUB_TOKEN is set.
+macOS (security) and Linux (secret-tool of libsecret) are supported.
+
+$ gh auth token | pinact token set
+$ export PINACT_KEYRING_ENABLED=true
+$ pinact run
+
+Tokens are stored per GitHub API URL of --github-api-url, so you can store tokens of GitHub Enterprise Servers besides the token of github.com.
+
+$ gh auth token --hostname ghes.example.com | pinact --github-api-url https://ghes.example.com/api/v3/ token set
+$ pinact --github-api-url https://ghes.example.com/api/v3/ run
+`,
+		Subcommands: []*cli.Command{
+			{
+				Name:  "set",
+				Usage: "Store a GitHub access token in the keyring",
+				Description: `Store a GitHub access token in the keyring.
+The token is read from the standard input.
+If a token is already stored, it's overwritten.
+The token is stored for the GitHub API URL of --github-api-url.
+
+$ pinact token set
+$ gh auth token | pinact token set
+`,
+				Action: r.tokenSetAction,
+			},
+			{
+				Name:    "remove",
+				Aliases: []string{"rm"},
+				Usage:   "Remove a GitHub access token from the keyring",
+				Action:  r.tokenRemoveAction,
+			},
+			{
+				Name:  "show-source",
+				Usage: "Show where a GitHub access token is read from",
+				Description: `Show where a GitHub access token is read from.
+The token itself isn't output.
+
+$ pinact token show-source
+GITHUB_TOKEN
+`,
+				Action: r.tokenShowSourceAction,
+			},
+		},
+	}
+}
+
+func (r *Runner) tokenSetAction(c *cli.Context) error {
+	fmt.Fprint(r.Stderr, "Enter a GitHub access token: ")
+	scanner := bufio.NewScanner(r.Stdin)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("read a token from the standard input: %w", err)
+		}
+	}
+	fmt.Fprintln(r.Stderr)
+	token := strings.TrimSpace(scanner.Text())
+	if token == "" {
+		return errors.New("the token is empty")
+	}
+	if err := keyring.Set(c.Context, c.String("github-api-url"), token); err != nil {
+		return err //nolint:wrapcheck
+	}
+	r.LogE.Info("the token is stored in the keyring")
+	return nil
+}
+
+func (r *Runner) tokenRemoveAction(c *cli.Context) error {
+	if err := keyring.Delete(c.Context, c.String("github-api-url")); err != nil {
+		return err //nolint:wrapcheck
+	}
+	r.LogE.Info("the token is removed from the keyring")
+	return nil
+}
+
+func (r *Runner) tokenShowSourceAction(c *cli.Context) error {
+	_, source, err := getGitHubToken(c)
+	if err != nil {
+		return err
+	}
+	if source == "" {
+		source = "none"
+	}
+	fmt.Fprintln(r.Stdout, source)
+	return nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newUnpinCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "unpin",
+		Usage: "Unpin actions pinned to commit hashes",
+		Description: `Convert actions pinned to commit hashes back to versions of their annotations without calling GitHub API.
+This is useful for repositories abandoning pinning and debugging.
+Actions without version annotations aren't changed.
+
+e.g.
+
+uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2
+=> uses: actions/checkout@v4.2.2
+
+$ pinact unpin
+
+If --major is set, actions are unpinned to major versions.
+
+uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2
+=> uses: actions/checkout@v4
+
+You can also pass workflow file paths as arguments.
+
+$ pinact unpin .github/workflows/test.yaml
+`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "major",
+				Usage: "Unpin actions to major versions such as v4 instead of version annotations such as v4.2.2",
+			},
+		},
+		Action: r.unpinAction,
+	}
+}
+
+func (r *Runner) unpinAction(c *cli.Context) error {
+	ctrl, err := run.New(c.Context, &run.InputNew{})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
+	}
+	return ctrl.Unpin(r.LogE, &run.ParamUnpin{ //nolint:wrapcheck
+		WorkflowFilePaths: c.Args().Slice(),
+		ConfigFilePath:    c.String("config"),
+		PWD:               pwd,
+		Now:               time.Now(),
+		Major:             c.Bool("major"),
+		Stderr:            r.Stderr,
+	})
+}
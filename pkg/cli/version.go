@@ -1,18 +1,101 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/offline"
+	"github.com/suzuki-shunsuke/pinact/pkg/trace"
 	"github.com/urfave/cli/v2"
 )
 
 func (r *Runner) newVersionCommand() *cli.Command {
 	return &cli.Command{
-		Name:   "version",
-		Usage:  "Show version",
+		Name:  "version",
+		Usage: "Show version",
+		Description: `Show version.
+
+If --json is set, build information and enabled integrations are output as JSON.
+This is useful to embed in reports and to debug.
+
+$ pinact version --json
+`,
 		Action: r.versionAction,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output build information and enabled integrations as JSON",
+			},
+		},
 	}
 }
 
+type versionInfo struct {
+	Version      string        `json:"version"`
+	Commit       string        `json:"commit"`
+	Date         string        `json:"date"`
+	GoVersion    string        `json:"go_version"`
+	OS           string        `json:"os"`
+	Arch         string        `json:"arch"`
+	Integrations *integrations `json:"integrations"`
+}
+
+// integrations are integrations enabled by environment variables.
+type integrations struct {
+	Tracing bool `json:"tracing"`
+	Keyring bool `json:"keyring"`
+	Offline bool `json:"offline"`
+}
+
+// getVersionInfo returns build information.
+// If pinact is built without ldflags such as go install, the version and the commit are got from the build information embedded by Go.
+func (r *Runner) getVersionInfo() *versionInfo {
+	info := &versionInfo{
+		Version:   r.LDFlags.Version,
+		Commit:    r.LDFlags.Commit,
+		Date:      r.LDFlags.Date,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Integrations: &integrations{
+			Tracing: trace.Enabled(),
+			Keyring: keyringEnabled(),
+			Offline: offline.Enabled(),
+		},
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "" {
+		info.Version = bi.Main.Version
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = setting.Value
+			}
+		}
+	}
+	return info
+}
+
 func (r *Runner) versionAction(c *cli.Context) error {
-	cli.ShowVersion(c)
+	if !c.Bool("json") {
+		cli.ShowVersion(c)
+		return nil
+	}
+	encoder := json.NewEncoder(r.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r.getVersionInfo()); err != nil {
+		return fmt.Errorf("output version information as JSON: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRunner_versionAction(t *testing.T) {
+	t.Parallel()
+	stdout := &bytes.Buffer{}
+	runner := &Runner{
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+		LDFlags: &LDFlags{
+			Version: "v3.0.0",
+			Commit:  "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+			Date:    "2024-06-01T00:00:00Z",
+		},
+		LogE: logrus.NewEntry(logrus.New()),
+	}
+	if err := runner.Run(context.Background(), "pinact", "version", "--json"); err != nil {
+		t.Fatal(err)
+	}
+	info := map[string]any{}
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(info))
+	for k := range info {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if diff := cmp.Diff([]string{"arch", "commit", "date", "go_version", "integrations", "os", "version"}, keys); diff != "" {
+		t.Fatal(diff)
+	}
+	exp := map[string]any{
+		"version":    "v3.0.0",
+		"commit":     "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+		"date":       "2024-06-01T00:00:00Z",
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+	}
+	for k, v := range exp {
+		if info[k] != v {
+			t.Fatalf("%s: wanted %v, got %v", k, v, info[k])
+		}
+	}
+	integrations, ok := info["integrations"].(map[string]any)
+	if !ok {
+		t.Fatalf("integrations must be an object: %v", info["integrations"])
+	}
+	for _, k := range []string{"tracing", "keyring", "offline"} {
+		if _, ok := integrations[k].(bool); !ok {
+			t.Fatalf("integrations.%s must be a boolean: %v", k, integrations[k])
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+	"github.com/urfave/cli/v2"
+)
+
+func (r *Runner) newWorkspaceCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "workspace",
+		Usage: "Process multiple repositories",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "run",
+				Usage: "Pin GitHub Actions versions of repositories defined in a manifest",
+				Description: `Pin GitHub Actions versions of repositories defined in a manifest.
+Repositories are processed with a shared API cache.
+
+$ pinact workspace run --manifest repos.yaml
+
+repos.yaml
+
+repositories:
+  - path: foo # A local path. A relative path is relative to the manifest file
+  - path: bar
+    config: .github/pinact.yaml # A configuration file path relative to the repository. This is optional
+`,
+				Action: r.workspaceRunAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "manifest",
+						Aliases:  []string{"m"},
+						Usage:    "manifest file path",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:    "verify",
+						Aliases: []string{"v"},
+						Usage:   "Verify if pairs of commit SHA and version are correct",
+					},
+					&cli.BoolFlag{
+						Name:    "update",
+						Aliases: []string{"u"},
+						Usage:   "Update actions to latest versions",
+					},
+					&cli.BoolFlag{
+						Name:  "check-duplicate-versions",
+						Usage: "Warn if the same action is used at multiple versions in a job",
+					},
+					&cli.BoolFlag{
+						Name:    "assume-yes",
+						Aliases: []string{"y"},
+						Usage:   "Modify files without confirmation even if the number of changed files exceeds confirm_threshold",
+						EnvVars: []string{"PINACT_ASSUME_YES"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *Runner) workspaceRunAction(c *cli.Context) error {
+	ctrl, err := r.newController(c, &run.InputNew{
+		Update:         c.Bool("update"),
+		RecordFilePath: c.String("record"),
+		ReplayFilePath: c.String("replay"),
+		GitHubAPIURL:   c.String("github-api-url"),
+		Version:        r.getVersionInfo().Version,
+	})
+	if err != nil {
+		return err
+	}
+	defer ctrl.SaveCache(r.LogE)
+	pwd, err := getPWD(c)
+	if err != nil {
+		return err
+	}
+	return ctrl.RunWorkspace(c.Context, r.LogE, &run.ParamRunWorkspace{ //nolint:wrapcheck
+		ManifestFilePath:       c.String("manifest"),
+		PWD:                    pwd,
+		IsVerify:               c.Bool("verify"),
+		CheckDuplicateVersions: c.Bool("check-duplicate-versions"),
+		Now:                    time.Now(),
+		AssumeYes:              c.Bool("assume-yes"),
+		Stdin:                  terminalReader(r.Stdin),
+		Stderr:                 r.Stderr,
+		TokenFromStdin:         c.String("github-token-file") == "-",
+	})
+}
@@ -0,0 +1,58 @@
+package run
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// initActionFilters compiles includes and excludes.
+// It's called again after --include and --exclude are appended to them.
+func (c *Config) initActionFilters() error {
+	includes, err := compilePatterns(c.Includes)
+	if err != nil {
+		return fmt.Errorf("parse includes as a regular expression: %w", err)
+	}
+	excludes, err := compilePatterns(c.Excludes)
+	if err != nil {
+		return fmt.Errorf("parse excludes as a regular expression: %w", err)
+	}
+	c.includes = includes
+	c.excludes = excludes
+	return nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	ps := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		p, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+		ps[i] = p
+	}
+	return ps, nil
+}
+
+// isActionExcluded returns the rule excluding the action by the action name.
+// If the action isn't excluded, it returns an empty string.
+func (c *Config) isActionExcluded(name string) string {
+	if len(c.includes) != 0 && !matchPatterns(c.includes, name) {
+		return "includes"
+	}
+	for i, p := range c.excludes {
+		if p.MatchString(name) {
+			c.useRule(fmt.Sprintf("excludes[%d]", i))
+			return "excludes"
+		}
+	}
+	return ""
+}
+
+func matchPatterns(patterns []*regexp.Regexp, s string) bool {
+	for _, p := range patterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
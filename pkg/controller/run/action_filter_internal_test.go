@@ -0,0 +1,69 @@
+package run
+
+import "testing"
+
+func TestConfig_isActionExcluded(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		cfg    *Config
+		action string
+		exp    string
+	}{
+		{
+			name:   "no filter",
+			cfg:    &Config{},
+			action: "actions/checkout",
+		},
+		{
+			name:   "included",
+			cfg:    &Config{Includes: []string{"^actions/"}},
+			action: "actions/checkout",
+		},
+		{
+			name:   "not included",
+			cfg:    &Config{Includes: []string{"^actions/"}},
+			action: "suzuki-shunsuke/foo",
+			exp:    "includes",
+		},
+		{
+			name:   "excluded",
+			cfg:    &Config{Includes: []string{"^actions/"}, Excludes: []string{"^actions/cache$"}},
+			action: "actions/cache",
+			exp:    "excludes",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if err := d.cfg.Init(); err != nil {
+				t.Fatal(err)
+			}
+			if got := d.cfg.isActionExcluded(d.action); got != d.exp {
+				t.Fatalf("wanted %q, got %q", d.exp, got)
+			}
+		})
+	}
+}
+
+func TestConfig_initActionFilters(t *testing.T) {
+	t.Parallel()
+	cfg := &Config{Excludes: []string{"^actions/cache$"}}
+	if err := cfg.Init(); err != nil {
+		t.Fatal(err)
+	}
+	// --exclude extends excludes of the configuration
+	cfg.Excludes = append(cfg.Excludes, "^suzuki-shunsuke/")
+	if err := cfg.initActionFilters(); err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range []string{"actions/cache", "suzuki-shunsuke/foo"} {
+		if cfg.isActionExcluded(action) == "" {
+			t.Fatalf("%s should be excluded", action)
+		}
+	}
+	cfg.Includes = append(cfg.Includes, "(")
+	if err := cfg.initActionFilters(); err == nil {
+		t.Fatal("an invalid regular expression should be rejected")
+	}
+}
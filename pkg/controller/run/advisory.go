@@ -0,0 +1,136 @@
+package run
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/offline"
+)
+
+// bundledAdvisories is a list of known compromised actions bundled with pinact.
+//
+//go:embed advisories.json
+var bundledAdvisories []byte
+
+// AdvisoryList is a list of compromised actions.
+// The format is same as advisories.json so that users can host their own advisory feed.
+type AdvisoryList struct {
+	Advisories []*Advisory `json:"advisories"`
+}
+
+type Advisory struct {
+	// Action is an action name such as tj-actions/changed-files.
+	Action string `json:"action"`
+	// SHAs are compromised commit hashes.
+	SHAs []string `json:"shas,omitempty"`
+	// Tags are compromised tags. They're matched only if actions aren't pinned by commit hashes.
+	Tags        []string `json:"tags,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// match returns true if the action is compromised.
+func (a *Advisory) match(action *Action) bool {
+	if !strings.EqualFold(a.Action, action.Name) {
+		return false
+	}
+	if getVersionType(action.Version) == FullCommitSHA {
+		return slices.Contains(a.SHAs, action.Version)
+	}
+	return slices.Contains(a.Tags, action.Version)
+}
+
+// loadAdvisories returns bundled advisories and advisories fetched from the URL.
+// If the URL is empty, only bundled advisories are returned.
+// If the checksum isn't empty, the fetched feed is verified by the SHA256 checksum.
+func loadAdvisories(ctx context.Context, advisoryURL, checksum string) ([]*Advisory, error) {
+	list := &AdvisoryList{}
+	if err := json.Unmarshal(bundledAdvisories, list); err != nil {
+		return nil, fmt.Errorf("decode bundled advisories as JSON: %w", err)
+	}
+	if advisoryURL == "" {
+		return list.Advisories, nil
+	}
+	fetched, err := fetchAdvisories(ctx, advisoryURL, checksum)
+	if err != nil {
+		return nil, err
+	}
+	return append(list.Advisories, fetched.Advisories...), nil
+}
+
+func fetchAdvisories(ctx context.Context, u, checksum string) (*AdvisoryList, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create a request to get advisories: %w", err)
+	}
+	resp, err := offline.HTTPClient(http.DefaultClient).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get advisories: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get advisories: status code %d", resp.StatusCode)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read advisories: %w", err)
+	}
+	if checksum != "" {
+		// Fail closed so that a tampered feed doesn't flow into runs.
+		if err := verifyChecksum(b, checksum); err != nil {
+			return nil, logerr.WithFields(fmt.Errorf("verify advisories: %w", err), logrus.Fields{ //nolint:wrapcheck
+				"advisory_url": u,
+			})
+		}
+	}
+	list := &AdvisoryList{}
+	if err := json.Unmarshal(b, list); err != nil {
+		return nil, fmt.Errorf("decode advisories as JSON: %w", err)
+	}
+	return list, nil
+}
+
+// checkAdvisories outputs a critical finding for each compromised action and returns the number of them.
+// Ignore rules don't apply because compromised actions must not be overlooked.
+func (c *Controller) checkAdvisories(logE *logrus.Entry, workflowFilePath string, lines []string, advisories []*Advisory) int {
+	found := 0
+	for i, line := range lines {
+		action := parseAction(line)
+		if action == nil {
+			continue
+		}
+		for _, advisory := range advisories {
+			if !advisory.match(action) {
+				continue
+			}
+			found++
+			logE.WithFields(logrus.Fields{
+				"action":      action.Name,
+				"version":     action.Version,
+				"line_number": i + 1,
+				"finding":     "compromised-action",
+				"severity":    "critical",
+				"advisory":    advisory.URL,
+				"help_docs":   "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/006.md",
+			}).Error("the action is compromised. " + advisory.Description)
+			c.reporter.OnFinding(&Finding{
+				Kind:    FindingKindCompromisedAction,
+				File:    workflowFilePath,
+				Line:    i + 1,
+				Before:  line,
+				After:   line,
+				Message: advisory.Description,
+			})
+			break
+		}
+	}
+	return found
+}
@@ -0,0 +1,35 @@
+package run
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_checkAdvisories(t *testing.T) {
+	t.Parallel()
+	advisories, err := loadAdvisories(context.Background(), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	advisories = append(advisories, &Advisory{
+		Action: "suzuki-shunsuke/compromised-action",
+		Tags:   []string{"v1"},
+	})
+	lines := []string{
+		"jobs:",
+		"  test:",
+		"    steps:",
+		"      - uses: tj-actions/changed-files@0e58ed8671d6b60d0890c21b07f8835ace038e67 # v45.0.7",
+		"      - uses: tj-actions/changed-files@ed68ef82c095e0d48ec87eccea555d944a631a4c # v46.0.0",
+		"      - uses: suzuki-shunsuke/compromised-action@v1",
+		"      - uses: suzuki-shunsuke/compromised-action@v2",
+		"      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+	}
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	if n := ctrl.checkAdvisories(logrus.NewEntry(logrus.New()), "test.yaml", lines, advisories); n != 2 {
+		t.Fatalf("wanted 2, got %d", n)
+	}
+}
@@ -0,0 +1,132 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// allowFilePaths are paths of allow files searched in order.
+// An allow file is separated from the configuration file so that security teams can review exceptions with CODEOWNERS.
+var allowFilePaths = []string{"pinact.allow", ".github/pinact.allow"}
+
+// AllowFile grants exceptions for specific unpinned actions.
+//
+//	exceptions:
+//	  - name: suzuki-shunsuke/foo
+//	    version: main
+//	    expires: 2025-12-31
+//	    reason: The action doesn't publish tags
+type AllowFile struct {
+	Exceptions []*AllowException `yaml:"exceptions"`
+}
+
+type AllowException struct {
+	// Name is a glob pattern of action names like ignore_actions[].name.
+	Name string `yaml:"name"`
+	// Version is a glob pattern of versions. If this is empty, all versions are allowed.
+	Version string `yaml:"version"`
+	// Expires is the last date when the exception is valid. The format is YYYY-MM-DD.
+	Expires string `yaml:"expires"`
+	Reason  string `yaml:"reason"`
+	expires time.Time
+}
+
+func (e *AllowException) init() error {
+	if e.Name == "" {
+		return errors.New("exceptions[].name is required")
+	}
+	if _, err := path.Match(e.Name, ""); err != nil {
+		return fmt.Errorf("parse exceptions[].name as a glob pattern: %w", err)
+	}
+	if _, err := path.Match(e.Version, ""); err != nil {
+		return fmt.Errorf("parse exceptions[].version as a glob pattern: %w", err)
+	}
+	if e.Expires == "" {
+		return fmt.Errorf("exceptions[].expires is required: %s", e.Name)
+	}
+	t, err := time.Parse(time.DateOnly, e.Expires)
+	if err != nil {
+		return fmt.Errorf("parse exceptions[].expires as a date: %w", err)
+	}
+	e.expires = t
+	return nil
+}
+
+func (e *AllowException) match(action *Action) bool {
+	if f, err := path.Match(e.Name, action.Name); err != nil || !f {
+		return false
+	}
+	if e.Version == "" {
+		return true
+	}
+	f, err := path.Match(e.Version, action.Version)
+	return err == nil && f
+}
+
+// expired returns true if the exception has expired.
+// The exception is valid until the end of the date of expires.
+func (e *AllowException) expired(now time.Time) bool {
+	return !now.Before(e.expires.AddDate(0, 0, 1))
+}
+
+// readAllowFile reads an allow file in pwd.
+// If no allow file exists, it returns nil.
+func (c *Controller) readAllowFile(pwd string) (*AllowFile, error) {
+	for _, p := range allowFilePaths {
+		p = filepath.Join(pwd, p)
+		f, err := afero.Exists(c.fs, p)
+		if err != nil {
+			return nil, fmt.Errorf("check if %s exists: %w", p, err)
+		}
+		if !f {
+			continue
+		}
+		b, err := afero.ReadFile(c.fs, p)
+		if err != nil {
+			return nil, fmt.Errorf("read an allow file: %w", err)
+		}
+		allow := &AllowFile{}
+		if err := yaml.Unmarshal(b, allow); err != nil {
+			return nil, fmt.Errorf("decode an allow file as YAML: %w", err)
+		}
+		for _, e := range allow.Exceptions {
+			if err := e.init(); err != nil {
+				return nil, fmt.Errorf("initialize an allow file %s: %w", p, err)
+			}
+		}
+		return allow, nil
+	}
+	return nil, nil //nolint:nilnil
+}
+
+// isAllowed returns true if an exception of the allow file allows the unpinned action.
+// Expired exceptions are ignored with warnings so that exceptions are reviewed again.
+func (c *Config) isAllowed(logE *logrus.Entry, action *Action) bool {
+	if c.allow == nil {
+		return false
+	}
+	for _, e := range c.allow.Exceptions {
+		if !e.match(action) {
+			continue
+		}
+		logE := logE.WithFields(logrus.Fields{
+			"exception_name": e.Name,
+			"expires":        e.Expires,
+			"reason":         e.Reason,
+		})
+		if e.expired(c.Now) {
+			logE.Warn("the exception of the allow file has expired")
+			continue
+		}
+		logE.Info("the unpinned action is allowed by the allow file")
+		return true
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestController_readAllowFile(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name       string
+		files      map[string]string
+		exceptions int
+		isErr      bool
+	}{
+		{
+			name: "no allow file",
+		},
+		{
+			name: "allow file",
+			files: map[string]string{
+				"/src/.github/pinact.allow": `exceptions:
+  - name: suzuki-shunsuke/*
+    version: main
+    expires: 2025-12-31
+    reason: The actions don't publish tags
+`,
+			},
+			exceptions: 1,
+		},
+		{
+			name: "expires is required",
+			files: map[string]string{
+				"/src/pinact.allow": `exceptions:
+  - name: suzuki-shunsuke/foo
+`,
+			},
+			isErr: true,
+		},
+		{
+			name: "invalid date",
+			files: map[string]string{
+				"/src/pinact.allow": `exceptions:
+  - name: suzuki-shunsuke/foo
+    expires: 12/31/2025
+`,
+			},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			for name, content := range d.files {
+				if err := afero.WriteFile(fs, name, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			ctrl := NewController(nil, fs)
+			allow, err := ctrl.readAllowFile("/src")
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			n := 0
+			if allow != nil {
+				n = len(allow.Exceptions)
+			}
+			if n != d.exceptions {
+				t.Fatalf("wanted %d exceptions, got %d", d.exceptions, n)
+			}
+		})
+	}
+}
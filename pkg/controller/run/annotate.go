@@ -0,0 +1,108 @@
+package run
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+// annotateSHA adds a version annotation to an action pinned to a commit hash without the annotation.
+// e.g. @<commit hash> => @<commit hash> # v1.2.3
+// The version is resolved from tags pointing to the commit hash.
+func (c *Controller) annotateSHA(ctx context.Context, logE *logrus.Entry, line string, cfg *Config, action *Action) (string, error) {
+	if c.shouldUpdate(ctx, logE, cfg, action) {
+		return c.updateToLatest(ctx, logE, line, cfg, action), nil
+	}
+	v, err := c.getVersionFromSHA(ctx, action, action.Version)
+	if err != nil {
+		c.logResolveError(logE, cfg, action, err, "get a version from the commit hash")
+		return line, nil
+	}
+	if v == "" {
+		logE.WithField("help_docs", "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/008.md").Warn("the version annotation is missing, but no tag points to the commit hash")
+		return line, nil
+	}
+	return c.patchVersion(ctx, logE, cfg, action, action.Version, v), nil
+}
+
+// annotateOnly adds or repairs the version annotation of an action pinned to a commit hash without changing the commit hash.
+// Actions not pinned to commit hashes aren't changed.
+// e.g. @<commit hash> => @<commit hash> # v1.2.3, @<commit hash> # v1.0.0 => @<commit hash> # v1.2.3
+// If the annotation is a semver tag pointing to the commit hash, the line isn't changed.
+func (c *Controller) annotateOnly(ctx context.Context, logE *logrus.Entry, line string, cfg *Config, action *Action) (string, error) {
+	if getVersionType(action.Version) != FullCommitSHA || getPinnedBranch(action) != "" {
+		return line, nil
+	}
+	if getVersionType(action.Tag) == Semver {
+		sha, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, action.Tag, "")
+		if err == nil && sha == action.Version {
+			return line, nil
+		}
+	}
+	v, err := c.getVersionFromSHA(ctx, action, action.Version)
+	if err != nil {
+		c.logResolveError(logE, cfg, action, err, "get a version from the commit hash")
+		return line, nil
+	}
+	if v == "" {
+		logE.WithField("help_docs", "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/008.md").Warn("no tag points to the commit hash, so the version annotation isn't changed")
+		return line, nil
+	}
+	if v == action.Tag {
+		return line, nil
+	}
+	return cfg.patchVersion(action, action.Version, v), nil
+}
+
+// updateToLatest updates an action to the latest version.
+// If it fails to get the latest version, the line isn't changed.
+// If the repository is a fork, only the latest version is taken from the parent and the action is kept.
+func (c *Controller) updateToLatest(ctx context.Context, logE *logrus.Entry, line string, cfg *Config, action *Action) string {
+	upstream := c.forkUpstream(ctx, logE, cfg, action)
+	lv, err := c.getLatestVersion(ctx, logE, cfg, upstream.RepoOwner, upstream.RepoName)
+	if err != nil {
+		c.logResolveError(logE, cfg, upstream, err, "get the latest version")
+		return line
+	}
+	sha, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, lv, "")
+	if err != nil {
+		c.logResolveError(logE, cfg, action, err, "get a reference")
+		return line
+	}
+	return c.patchVersion(ctx, logE, cfg, action, sha, lv)
+}
+
+// getVersionFromSHA returns a tag pointing to the commit hash.
+// Semver tags such as v1.2.3 are preferred to short tags such as v1, and other tags are ignored.
+func (c *Controller) getVersionFromSHA(ctx context.Context, action *Action, sha string) (string, error) {
+	opts := &github.ListOptions{
+		PerPage: 100, //nolint:mnd
+	}
+	shortTag := ""
+	for range maxTagPages {
+		tags, resp, err := c.repositoriesService.ListTags(ctx, action.RepoOwner, action.RepoName, opts)
+		if err != nil {
+			return "", fmt.Errorf("list tags: %w", err)
+		}
+		for _, tag := range tags {
+			if tag.GetCommit().GetSHA() != sha {
+				continue
+			}
+			switch getVersionType(tag.GetName()) {
+			case Semver:
+				return tag.GetName(), nil
+			case Shortsemver:
+				if shortTag == "" {
+					shortTag = tag.GetName()
+				}
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return shortTag, nil
+}
@@ -0,0 +1,174 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+// annotationIndex records version annotations of commit hashes across files.
+// It detects the same commit hash annotated with different tags such as @abc # v4.1.0 and @abc # v4.1.1.
+type annotationIndex struct {
+	// repo/sha -> annotations
+	annotations map[string][]*annotationUsage
+	keys        []string
+}
+
+type annotationUsage struct {
+	File string
+	// Line is a 0-based line number.
+	Line   int
+	Text   string
+	Action *Action
+}
+
+type inconsistentAnnotation struct {
+	Usages []*annotationUsage
+	Tags   []string
+}
+
+func newAnnotationIndex() *annotationIndex {
+	return &annotationIndex{
+		annotations: map[string][]*annotationUsage{},
+	}
+}
+
+// add records version annotations of actions pinned by commit hashes.
+func (idx *annotationIndex) add(c *Controller, file string, lines []string) {
+	for i, line := range lines {
+		action := parseAction(line)
+		if action == nil || action.Tag == "" || getVersionType(action.Version) != FullCommitSHA || !c.parseActionName(action) {
+			continue
+		}
+		key := action.RepoOwner + "/" + action.RepoName + "/" + action.Version
+		if _, ok := idx.annotations[key]; !ok {
+			idx.keys = append(idx.keys, key)
+		}
+		idx.annotations[key] = append(idx.annotations[key], &annotationUsage{
+			File:   file,
+			Line:   i,
+			Text:   line,
+			Action: action,
+		})
+	}
+}
+
+// find returns commit hashes annotated with different tags in the order of appearance.
+func (idx *annotationIndex) find() []*inconsistentAnnotation {
+	list := []*inconsistentAnnotation{}
+	for _, key := range idx.keys {
+		usages := idx.annotations[key]
+		tags := []string{}
+		for _, usage := range usages {
+			if !slices.Contains(tags, usage.Action.Tag) {
+				tags = append(tags, usage.Action.Tag)
+			}
+		}
+		if len(tags) < 2 { //nolint:mnd
+			continue
+		}
+		list = append(list, &inconsistentAnnotation{
+			Usages: usages,
+			Tags:   tags,
+		})
+	}
+	return list
+}
+
+// checkAnnotationConsistency warns of commit hashes annotated with different tags across files.
+// If fix is true, annotations are normalized to the tag verified by GitHub API.
+// Fixed lines are applied to changes, and changes of files which weren't changed are appended.
+func (c *Controller) checkAnnotationConsistency(ctx context.Context, logE *logrus.Entry, cfg *Config, changes []*workflowChange, fix bool, stats *Stats) []*workflowChange {
+	for _, inconsistency := range cfg.annotations.find() {
+		first := inconsistency.Usages[0].Action
+		logE := logE.WithFields(logrus.Fields{
+			"action":    first.Name,
+			"sha":       first.Version,
+			"tags":      strings.Join(inconsistency.Tags, ", "),
+			"help_docs": "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/012.md",
+		})
+		logE.Warn("the same commit hash is annotated with different tags")
+		tag := ""
+		if fix {
+			tag = c.getVerifiedTag(ctx, logE, first, inconsistency.Tags)
+		}
+		for _, usage := range inconsistency.Usages {
+			before, after := c.fixAnnotation(logE, usage, tag, &changes, stats)
+			c.reporter.OnFinding(&Finding{
+				Kind:    FindingKindInconsistentAnnotation,
+				File:    usage.File,
+				Line:    usage.Line + 1,
+				Before:  before,
+				After:   after,
+				Message: fmt.Sprintf("%s is annotated with different tags: %s", first.Version, strings.Join(inconsistency.Tags, ", ")),
+			})
+		}
+	}
+	return changes
+}
+
+// getVerifiedTag returns the only tag pointing to the commit hash.
+// If no tag or multiple tags point to the commit hash, it returns an empty string because pinact can't decide the tag.
+func (c *Controller) getVerifiedTag(ctx context.Context, logE *logrus.Entry, action *Action, tags []string) string {
+	verified := []string{}
+	for _, tag := range tags {
+		sha, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, tag, "")
+		if err != nil {
+			logerr.WithError(logE, err).WithField("tag", tag).Warn("get a commit hash")
+			continue
+		}
+		if sha == action.Version {
+			verified = append(verified, tag)
+		}
+	}
+	if len(verified) != 1 {
+		logE.WithField("verified_tags", strings.Join(verified, ", ")).Warn("annotations can't be normalized because the tag isn't decided")
+		return ""
+	}
+	return verified[0]
+}
+
+// fixAnnotation replaces the annotation of the usage with the tag and returns the line before and after the fix.
+// If tag is empty or the annotation is already the tag, the line isn't changed.
+func (c *Controller) fixAnnotation(logE *logrus.Entry, usage *annotationUsage, tag string, changes *[]*workflowChange, stats *Stats) (string, string) {
+	before := usage.Text
+	if tag == "" || usage.Action.Tag == tag {
+		return before, before
+	}
+	var change *workflowChange
+	if idx := slices.IndexFunc(*changes, func(change *workflowChange) bool {
+		return change.Path == usage.File
+	}); idx != -1 {
+		change = (*changes)[idx]
+	} else {
+		lines, err := c.readWorkflow(usage.File)
+		if err != nil {
+			logerr.WithError(logE, err).WithField("workflow_file", usage.File).Warn("read a workflow")
+			return before, before
+		}
+		change = &workflowChange{
+			Path:  usage.File,
+			Lines: lines,
+		}
+	}
+	if usage.Line >= len(change.Lines) || change.Lines[usage.Line] != before {
+		return before, before
+	}
+	if !slices.Contains(*changes, change) {
+		*changes = append(*changes, change)
+		stats.ChangedFiles++
+	}
+	after := patchLine(usage.Action, usage.Action.Version, tag)
+	change.Lines[usage.Line] = after
+	stats.ChangedLines++
+	logE.WithFields(logrus.Fields{
+		"workflow_file": usage.File,
+		"line_number":   usage.Line + 1,
+		"tag":           tag,
+	}).Info("normalize the annotation")
+	return before, after
+}
@@ -0,0 +1,88 @@
+package run
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_checkAnnotationConsistency(t *testing.T) {
+	t.Parallel()
+	files := map[string][]string{
+		"test.yaml": {
+			"      - uses: actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11 # v4.1.1",
+			"      - uses: actions/setup-go@0c52d547c9bc32b1aa3301fd7a9cb496313a4491 # v5.0.0",
+		},
+		"release.yaml": {
+			"      - uses: actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11 # v4.1.0",
+			"      - uses: actions/setup-go@0c52d547c9bc32b1aa3301fd7a9cb496313a4491 # v5.0.0",
+		},
+	}
+	data := []struct {
+		name     string
+		fix      bool
+		exp      map[string][]string
+		findings int
+	}{
+		{
+			name:     "check",
+			exp:      map[string][]string{},
+			findings: 2,
+		},
+		{
+			name: "fix",
+			fix:  true,
+			exp: map[string][]string{
+				"release.yaml": {
+					"      - uses: actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11 # v4.1.1",
+					"      - uses: actions/setup-go@0c52d547c9bc32b1aa3301fd7a9cb496313a4491 # v5.0.0",
+				},
+			},
+			findings: 2,
+		},
+	}
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				commits: map[string]*GetCommitSHA1Result{
+					"actions/checkout/v4.1.1": {SHA: "b4ffde65f46336ab88eb53be808477a3936bae11"},
+					"actions/checkout/v4.1.0": {SHA: "8ade135a41bc03ea155e62e844d188df1ea18608"},
+				},
+			}, afero.NewMemMapFs())
+			reporter := &testReporter{}
+			ctrl.SetReporter(reporter)
+			cfg := &Config{annotations: newAnnotationIndex()}
+			for _, name := range []string{"test.yaml", "release.yaml"} {
+				p := filepath.Join(dir, name)
+				content := ""
+				for _, line := range files[name] {
+					content += line + "\n"
+				}
+				if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				cfg.annotations.add(ctrl, p, files[name])
+			}
+			stats := &Stats{}
+			changes := ctrl.checkAnnotationConsistency(context.Background(), logE, cfg, nil, d.fix, stats)
+			got := map[string][]string{}
+			for _, change := range changes {
+				got[filepath.Base(change.Path)] = change.Lines
+			}
+			if diff := cmp.Diff(d.exp, got); diff != "" {
+				t.Fatal(diff)
+			}
+			if len(reporter.findings) != d.findings {
+				t.Fatalf("wanted %d findings, got %d", d.findings, len(reporter.findings))
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Replacement is an alternative of an action whose repository is archived.
+type Replacement struct {
+	Action      string `json:"action" jsonschema:"description=An archived action such as actions/create-release. Sub directories of the repository are also matched"`
+	Replacement string `json:"replacement" jsonschema:"description=An alternative of the action suggested in the deprecated-action finding. e.g. softprops/action-gh-release"`
+}
+
+func (r *Replacement) validate() error {
+	if r.Action == "" {
+		return errors.New("replacements[].action is required")
+	}
+	if r.Replacement == "" {
+		return errors.New("replacements[].replacement is required")
+	}
+	return nil
+}
+
+// archivedAction is an action found to be archived while a line is parsed.
+type archivedAction struct {
+	name        string
+	replacement string
+}
+
+// getReplacement returns the replacement of the action configured by replacements.
+// It returns an empty string if no rule matches.
+func (c *Config) getReplacement(action *Action) string {
+	repo := action.RepoOwner + "/" + action.RepoName
+	for _, r := range c.Replacements {
+		if r.Action == action.Name || r.Action == repo {
+			return r.Replacement
+		}
+	}
+	return ""
+}
+
+// isArchived returns true if the action's repository is archived.
+// Archived actions aren't maintained, so updating them to the last release doesn't make sense.
+// The action is recorded so that the deprecated-action finding is reported with the line number.
+func (c *Controller) isArchived(ctx context.Context, logE *logrus.Entry, cfg *Config, action *Action) bool {
+	repo, _, err := c.repositoriesService.Get(ctx, action.RepoOwner, action.RepoName)
+	if err != nil {
+		c.logResolveError(logE, cfg, action, err, "get a repository")
+		return false
+	}
+	if !repo.GetArchived() {
+		return false
+	}
+	replacement := cfg.getReplacement(action)
+	fields := logrus.Fields{
+		"finding":   FindingKindDeprecatedAction,
+		"help_docs": "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/004.md",
+	}
+	if replacement != "" {
+		fields["replacement"] = replacement
+	}
+	logE.WithFields(fields).Warn("the action's repository is archived. The action isn't updated")
+	cfg.archivedActions = append(cfg.archivedActions, &archivedAction{
+		name:        action.Name,
+		replacement: replacement,
+	})
+	return true
+}
+
+// reportArchivedActions reports actions recorded by isArchived while the line was parsed.
+// line is a 0-based line number.
+func (c *Controller) reportArchivedActions(file string, i int, line string, cfg *Config) {
+	reported := map[string]struct{}{}
+	for _, a := range cfg.archivedActions {
+		if _, ok := reported[a.name]; ok {
+			// shouldUpdate may be called more than once for a line.
+			continue
+		}
+		reported[a.name] = struct{}{}
+		msg := fmt.Sprintf("the repository of the action %s is archived", a.name)
+		if a.replacement != "" {
+			msg += ". Please consider replacing it with " + a.replacement
+		}
+		c.reporter.OnFinding(&Finding{
+			Kind:        FindingKindDeprecatedAction,
+			File:        file,
+			Line:        i + 1,
+			Before:      line,
+			After:       line,
+			Message:     msg,
+			Replacement: a.replacement,
+		})
+	}
+	cfg.archivedActions = nil
+}
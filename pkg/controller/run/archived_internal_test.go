@@ -0,0 +1,111 @@
+package run
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+func TestController_runWorkflow_archived(t *testing.T) {
+	t.Parallel()
+	workflowFilePath := filepath.Join(t.TempDir(), "test.yaml")
+	content := `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2
+      - uses: suzuki-shunsuke/archived-action@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0
+      - uses: suzuki-shunsuke/archived-action/sub@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0
+      - uses: suzuki-shunsuke/another-archived-action@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0
+`
+	if err := os.WriteFile(workflowFilePath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := NewController(&RepositoriesServiceImpl{
+		repos: map[string]*GetRepositoryResult{
+			"actions/checkout": {
+				Repository: &github.Repository{},
+			},
+			"suzuki-shunsuke/archived-action": {
+				Repository: &github.Repository{
+					Archived: util.BoolP(true),
+				},
+			},
+			"suzuki-shunsuke/another-archived-action": {
+				Repository: &github.Repository{
+					Archived: util.BoolP(true),
+				},
+			},
+		},
+		releases: map[string]*ListReleasesResult{
+			"actions/checkout/0": {
+				Releases: []*github.RepositoryRelease{
+					{
+						TagName: util.StrP("v3.5.2"),
+					},
+				},
+				Response: &github.Response{},
+			},
+		},
+		commits: map[string]*GetCommitSHA1Result{
+			"actions/checkout/v3.5.2": {
+				SHA: "8e5e7e5ab8b370d6c329ec480221332ada57f0ab",
+			},
+		},
+	}, afero.NewMemMapFs())
+	ctrl.update = true
+	reporter := &testReporter{}
+	ctrl.SetReporter(reporter)
+	cfg := &Config{
+		SkipArchived: true,
+		Replacements: []*Replacement{
+			{
+				Action:      "suzuki-shunsuke/archived-action",
+				Replacement: "suzuki-shunsuke/new-action",
+			},
+		},
+	}
+	if err := cfg.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctrl.runWorkflow(context.Background(), logrus.NewEntry(logrus.New()), workflowFilePath, cfg, &Stats{}); err != nil {
+		t.Fatal(err)
+	}
+	exps := []*Finding{
+		{
+			Kind:        FindingKindDeprecatedAction,
+			File:        workflowFilePath,
+			Line:        5,
+			Before:      "      - uses: suzuki-shunsuke/archived-action@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0",
+			After:       "      - uses: suzuki-shunsuke/archived-action@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0",
+			Message:     "the repository of the action suzuki-shunsuke/archived-action is archived. Please consider replacing it with suzuki-shunsuke/new-action",
+			Replacement: "suzuki-shunsuke/new-action",
+		},
+		{
+			Kind:        FindingKindDeprecatedAction,
+			File:        workflowFilePath,
+			Line:        6,
+			Before:      "      - uses: suzuki-shunsuke/archived-action/sub@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0",
+			After:       "      - uses: suzuki-shunsuke/archived-action/sub@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0",
+			Message:     "the repository of the action suzuki-shunsuke/archived-action/sub is archived. Please consider replacing it with suzuki-shunsuke/new-action",
+			Replacement: "suzuki-shunsuke/new-action",
+		},
+		{
+			Kind:    FindingKindDeprecatedAction,
+			File:    workflowFilePath,
+			Line:    7,
+			Before:  "      - uses: suzuki-shunsuke/another-archived-action@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0",
+			After:   "      - uses: suzuki-shunsuke/another-archived-action@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0",
+			Message: "the repository of the action suzuki-shunsuke/another-archived-action is archived",
+		},
+	}
+	if diff := cmp.Diff(exps, reporter.findings); diff != "" {
+		t.Fatal(diff)
+	}
+}
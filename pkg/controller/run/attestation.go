@@ -0,0 +1,112 @@
+package run
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+// slsaProvenancePrefix is the prefix of predicate types of SLSA provenance.
+// e.g. https://slsa.dev/provenance/v1
+const slsaProvenancePrefix = "https://slsa.dev/provenance/"
+
+type attestationBundle struct {
+	DSSEEnvelope *dsseEnvelope `json:"dsseEnvelope"`
+}
+
+type dsseEnvelope struct {
+	Payload string `json:"payload"`
+}
+
+type inTotoStatement struct {
+	PredicateType string `json:"predicateType"`
+}
+
+// getPredicateType returns the predicate type of the in-toto statement in a Sigstore bundle.
+func getPredicateType(bundle json.RawMessage) (string, error) {
+	b := &attestationBundle{}
+	if err := json.Unmarshal(bundle, b); err != nil {
+		return "", fmt.Errorf("decode a Sigstore bundle as JSON: %w", err)
+	}
+	if b.DSSEEnvelope == nil {
+		return "", nil
+	}
+	payload, err := base64.StdEncoding.DecodeString(b.DSSEEnvelope.Payload)
+	if err != nil {
+		return "", fmt.Errorf("decode a DSSE payload as base64: %w", err)
+	}
+	statement := &inTotoStatement{}
+	if err := json.Unmarshal(payload, statement); err != nil {
+		return "", fmt.Errorf("decode an in-toto statement as JSON: %w", err)
+	}
+	return statement.PredicateType, nil
+}
+
+// hasProvenance returns true if any attestation of the commit is SLSA provenance.
+// The subject digest of the commit is sha1:<commit hash>.
+func (c *Controller) hasProvenance(ctx context.Context, logE *logrus.Entry, action *Action) (bool, error) {
+	res, _, err := c.repositoriesService.ListAttestations(ctx, action.RepoOwner, action.RepoName, "sha1:"+action.Version, &github.ListOptions{
+		PerPage: 30, //nolint:mnd
+	})
+	if err != nil {
+		if github.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("list attestations: %w", err)
+	}
+	for _, attestation := range res.Attestations {
+		predicateType, err := getPredicateType(attestation.Bundle)
+		if err != nil {
+			logerr.WithError(logE, err).Debug("get the predicate type of an attestation")
+			continue
+		}
+		if strings.HasPrefix(predicateType, slsaProvenancePrefix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkAttestations outputs findings of actions whose pinned commits don't have SLSA provenance.
+// Only the predicate types of attestations are checked. Signatures of attestations aren't verified.
+func (c *Controller) checkAttestations(ctx context.Context, logE *logrus.Entry, workflowFilePath string, lines []string, cfg *Config) {
+	for i, line := range lines {
+		action := parseAction(line)
+		if action == nil || getVersionType(action.Version) != FullCommitSHA || !c.parseActionName(action) {
+			continue
+		}
+		logE := logE.WithFields(logrus.Fields{
+			"action":      action.Name,
+			"line_number": i + 1,
+		})
+		if isIgnoredInline(logE, line, cfg) {
+			continue
+		}
+		ok, err := c.hasProvenance(ctx, logE, action)
+		if err != nil {
+			logerr.WithError(logE, err).Warn("verify attestations")
+			continue
+		}
+		if ok {
+			continue
+		}
+		logE.WithFields(logrus.Fields{
+			"sha":       action.Version,
+			"help_docs": "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/010.md",
+		}).Warn("the pinned commit doesn't have SLSA provenance")
+		c.reporter.OnFinding(&Finding{
+			Kind:    FindingKindMissingProvenance,
+			File:    workflowFilePath,
+			Line:    i + 1,
+			Before:  line,
+			After:   line,
+			Message: "the pinned commit " + action.Version + " doesn't have SLSA provenance",
+		})
+	}
+}
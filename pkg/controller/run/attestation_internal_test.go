@@ -0,0 +1,59 @@
+package run
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+func newTestBundle(predicateType string) json.RawMessage {
+	payload := base64.StdEncoding.EncodeToString([]byte(`{"predicateType":"` + predicateType + `"}`))
+	return json.RawMessage(`{"dsseEnvelope":{"payload":"` + payload + `"}}`)
+}
+
+func TestController_checkAttestations(t *testing.T) {
+	t.Parallel()
+	ctrl := NewController(&RepositoriesServiceImpl{
+		attestations: map[string]*ListAttestationsResult{
+			"suzuki-shunsuke/foo/sha1:ee0669bd1cc54295c223e0bb666b733df41de1c5": {
+				Attestations: &github.AttestationsResponse{
+					Attestations: []*github.Attestation{
+						{Bundle: newTestBundle("https://spdx.dev/Document/v2.3")},
+						{Bundle: newTestBundle("https://slsa.dev/provenance/v1")},
+					},
+				},
+			},
+			"suzuki-shunsuke/bar/sha1:ee0669bd1cc54295c223e0bb666b733df41de1c5": {
+				Attestations: &github.AttestationsResponse{
+					Attestations: []*github.Attestation{
+						{Bundle: newTestBundle("https://spdx.dev/Document/v2.3")},
+					},
+				},
+			},
+		},
+	}, afero.NewMemMapFs())
+	reporter := &testReporter{}
+	ctrl.SetReporter(reporter)
+	lines := []string{
+		"      - uses: suzuki-shunsuke/foo@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v1.0.0",
+		"      - uses: suzuki-shunsuke/bar@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v1.0.0",
+		"      - uses: suzuki-shunsuke/bar@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v1.0.0 # pinact:ignore",
+		"      - uses: actions/checkout@v4",
+	}
+	ctrl.checkAttestations(context.Background(), logrus.NewEntry(logrus.New()), "test.yaml", lines, &Config{})
+	if len(reporter.findings) != 1 {
+		t.Fatalf("wanted 1 finding, got %d", len(reporter.findings))
+	}
+	finding := reporter.findings[0]
+	if finding.Kind != FindingKindMissingProvenance {
+		t.Fatalf("wanted %s, got %s", FindingKindMissingProvenance, finding.Kind)
+	}
+	if finding.Line != 2 {
+		t.Fatalf("wanted 2, got %d", finding.Line)
+	}
+}
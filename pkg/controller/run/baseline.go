@@ -0,0 +1,123 @@
+package run
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// sarifFingerprintKey is the key of partialFingerprints in SARIF results.
+const sarifFingerprintKey = "pinactFingerprint/v1"
+
+// baselineFile is a file of accepted findings written by --write-baseline.
+// Findings in the baseline don't fail the run, while new findings do.
+type baselineFile struct {
+	Fingerprints []string `json:"fingerprints"`
+}
+
+// getFindingFingerprint returns a fingerprint of the finding.
+// Line numbers aren't included so that the finding is tracked across line moves.
+// The message of changed lines isn't included either because it depends on the latest version.
+func getFindingFingerprint(pwd string, finding *Finding) string {
+	h := sha256.Sum256([]byte(strings.Join([]string{
+		finding.Kind,
+		getRelPath(pwd, finding.File),
+		strings.TrimSpace(finding.Before),
+		finding.Message,
+	}, "\x00")))
+	return hex.EncodeToString(h[:])
+}
+
+// baselineFilter is a reporter suppressing findings in the baseline.
+type baselineFilter struct {
+	next         Reporter
+	pwd          string
+	fingerprints map[string]struct{}
+	// suppressedFailures is the number of suppressed findings whose severity is error.
+	// They're excluded from the check failures.
+	suppressedFailures int
+}
+
+func newBaselineFilter(next Reporter, pwd string, baseline *baselineFile) *baselineFilter {
+	fingerprints := make(map[string]struct{}, len(baseline.Fingerprints))
+	for _, fp := range baseline.Fingerprints {
+		fingerprints[fp] = struct{}{}
+	}
+	return &baselineFilter{
+		next:         next,
+		pwd:          pwd,
+		fingerprints: fingerprints,
+	}
+}
+
+func (r *baselineFilter) OnFileStart(path string) {
+	r.next.OnFileStart(path)
+}
+
+func (r *baselineFilter) OnFinding(finding *Finding) {
+	if _, ok := r.fingerprints[getFindingFingerprint(r.pwd, finding)]; ok {
+		if finding.Severity == SeverityError {
+			r.suppressedFailures++
+		}
+		return
+	}
+	r.next.OnFinding(finding)
+}
+
+func (r *baselineFilter) OnFileEnd(path string, changed bool) {
+	r.next.OnFileEnd(path, changed)
+}
+
+// countFailures returns the number of findings whose severity is error.
+func countFailures(findings []*Finding) int {
+	n := 0
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *Controller) readBaseline(baselineFilePath string) (*baselineFile, error) {
+	b, err := afero.ReadFile(c.fs, baselineFilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("the baseline file isn't found. Please create it by --write-baseline: %w", err)
+		}
+		return nil, fmt.Errorf("read a baseline file: %w", err)
+	}
+	baseline := &baselineFile{}
+	if err := json.Unmarshal(b, baseline); err != nil {
+		return nil, fmt.Errorf("decode a baseline file as JSON: %w", err)
+	}
+	return baseline, nil
+}
+
+// writeBaseline writes fingerprints of findings to the file.
+// Fingerprints are sorted and deduplicated so that the file is stable.
+func (c *Controller) writeBaseline(baselineFilePath, pwd string, findings []*Finding) error {
+	baseline := &baselineFile{
+		Fingerprints: make([]string, 0, len(findings)),
+	}
+	for _, finding := range findings {
+		baseline.Fingerprints = append(baseline.Fingerprints, getFindingFingerprint(pwd, finding))
+	}
+	slices.Sort(baseline.Fingerprints)
+	baseline.Fingerprints = slices.Compact(baseline.Fingerprints)
+	b, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode a baseline as JSON: %w", err)
+	}
+	if err := afero.WriteFile(c.fs, baselineFilePath, append(b, '\n'), filePermission); err != nil {
+		return fmt.Errorf("write a baseline file: %w", err)
+	}
+	return nil
+}
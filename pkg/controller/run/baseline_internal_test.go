@@ -0,0 +1,75 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func Test_getFindingFingerprint(t *testing.T) {
+	t.Parallel()
+	finding := &Finding{
+		Kind:   FindingKindChanged,
+		File:   "/home/foo/repo/.github/workflows/test.yaml",
+		Line:   4,
+		Before: "      - uses: actions/checkout@v2",
+		After:  "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+	}
+	fp := getFindingFingerprint("/home/foo/repo", finding)
+	moved := *finding
+	moved.Line = 10
+	moved.Before = "  - uses: actions/checkout@v2"
+	moved.After = "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2"
+	if got := getFindingFingerprint("/home/foo/repo", &moved); got != fp {
+		t.Fatal("the fingerprint must not depend on the line number, the indentation, and the new version")
+	}
+	other := *finding
+	other.Before = "      - uses: actions/cache@v2"
+	if got := getFindingFingerprint("/home/foo/repo", &other); got == fp {
+		t.Fatal("the fingerprint must depend on the line")
+	}
+}
+
+func TestController_baseline(t *testing.T) {
+	t.Parallel()
+	pwd := "/home/foo/repo"
+	accepted := &Finding{
+		Kind:     FindingKindChanged,
+		File:     "/home/foo/repo/.github/workflows/test.yaml",
+		Line:     4,
+		Before:   "      - uses: actions/checkout@v2",
+		Severity: SeverityError,
+	}
+	added := &Finding{
+		Kind:     FindingKindChanged,
+		File:     "/home/foo/repo/.github/workflows/test.yaml",
+		Line:     5,
+		Before:   "      - uses: actions/cache@v2",
+		Severity: SeverityError,
+	}
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	if err := ctrl.writeBaseline("baseline.json", pwd, []*Finding{accepted, accepted}); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := ctrl.readBaseline("baseline.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(baseline.Fingerprints) != 1 {
+		t.Fatalf("fingerprints must be deduplicated: %v", baseline.Fingerprints)
+	}
+	reporter := &testReporter{}
+	filter := newBaselineFilter(reporter, pwd, baseline)
+	filter.OnFinding(accepted)
+	filter.OnFinding(added)
+	if diff := cmp.Diff([]*Finding{added}, reporter.findings); diff != "" {
+		t.Fatal(diff)
+	}
+	if filter.suppressedFailures != 1 {
+		t.Fatalf("wanted 1 suppressed failure, got %d", filter.suppressedFailures)
+	}
+	if _, err := ctrl.readBaseline("not-found.json"); err == nil {
+		t.Fatal("error must be returned if the baseline file isn't found")
+	}
+}
@@ -0,0 +1,102 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// branchAnnotationPattern matches the annotation of an action pinned to a branch.
+// The annotation consists of the branch and the resolution date.
+// e.g. " # main (2024-06-01)"
+var branchAnnotationPattern = regexp.MustCompile(`^[ \t]+#[ \t]+([^ \t#()]+) \(\d{4}-\d{2}-\d{2}\)`)
+
+// getPinnedBranch returns the branch of an action pinned to a branch.
+// e.g. main of "@<commit hash> # main (2024-06-01)"
+// If the action isn't pinned to a branch, it returns an empty string.
+func getPinnedBranch(action *Action) string {
+	if getVersionType(action.Version) != FullCommitSHA || action.Tag != "" {
+		return ""
+	}
+	m := branchAnnotationPattern.FindStringSubmatch(action.Suffix)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// patchBranch patches a line with a commit hash and the annotation of the branch with the resolution date.
+// A branch isn't a version, so the date tells when the commit hash was resolved.
+// e.g. @main => @<commit hash> # main (2024-06-01)
+func (c *Config) patchBranch(action *Action, sha, branch string) string {
+	a := *action
+	a.VersionTagSeparator = ""
+	if getPinnedBranch(action) != "" {
+		a.Suffix = action.Suffix[len(branchAnnotationPattern.FindString(action.Suffix)):]
+	}
+	a.Suffix = " (" + c.Now.Format(time.DateOnly) + ")" + a.Suffix
+	return patchLine(&a, sha, branch)
+}
+
+// pinBranch pins an action referenced by a branch such as main if pin_branches is enabled.
+func (c *Controller) pinBranch(ctx context.Context, logE *logrus.Entry, line string, cfg *Config, action *Action) string {
+	if !cfg.PinBranches {
+		return line
+	}
+	sha, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, action.Version, "")
+	if err != nil {
+		c.logResolveError(logE, cfg, action, err, "get a reference")
+		return line
+	}
+	return cfg.patchBranch(action, sha, action.Version)
+}
+
+// updateBranch updates the commit hash of an action pinned to a branch if the branch has moved.
+// Branches move frequently, so the commit hash is updated only if --update is set.
+// e.g. @<old commit hash> # main (2024-06-01) => @<new commit hash> # main (2024-07-01)
+func (c *Controller) updateBranch(ctx context.Context, logE *logrus.Entry, line string, cfg *Config, action *Action, branch string) string {
+	if !c.shouldUpdate(ctx, logE, cfg, action) {
+		return line
+	}
+	sha, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, branch, "")
+	if err != nil {
+		c.logResolveError(logE, cfg, action, err, "get a reference")
+		return line
+	}
+	if sha == action.Version {
+		return line
+	}
+	return cfg.patchBranch(action, sha, branch)
+}
+
+// checkBranchPins reports actions pinned to branches.
+// Branches aren't releases, so such actions are reported with the dedicated finding kind instead of missing version annotations.
+func (c *Controller) checkBranchPins(logE *logrus.Entry, file string, lines []string) {
+	for i, line := range lines {
+		action := parseAction(line)
+		if action == nil {
+			continue
+		}
+		branch := getPinnedBranch(action)
+		if branch == "" {
+			continue
+		}
+		msg := fmt.Sprintf("the action is pinned to the branch %s. Please consider using a release", branch)
+		logE.WithFields(logrus.Fields{
+			"action":      action.Name,
+			"branch":      branch,
+			"line_number": i + 1,
+		}).Info(msg)
+		c.reporter.OnFinding(&Finding{
+			Kind:    FindingKindBranchPin,
+			File:    file,
+			Line:    i + 1,
+			Before:  line,
+			After:   line,
+			Message: msg,
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_parseLine_pinBranches(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		line   string
+		update bool
+		cfg    *Config
+		exp    string
+	}{
+		{
+			name: "pin a branch",
+			line: "  uses: suzuki-shunsuke/foo@main # comment",
+			cfg:  &Config{PinBranches: true},
+			exp:  "  uses: suzuki-shunsuke/foo@ee0669bd1cc54295c223e0bb666b733df41de1c5 # main (2024-06-01) # comment",
+		},
+		{
+			name: "disabled",
+			line: "  uses: suzuki-shunsuke/foo@main",
+			cfg:  &Config{},
+			exp:  "  uses: suzuki-shunsuke/foo@main",
+		},
+		{
+			name: "the branch isn't updated without --update",
+			line: "  uses: suzuki-shunsuke/foo@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # main (2024-05-01)",
+			cfg:  &Config{},
+			exp:  "  uses: suzuki-shunsuke/foo@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # main (2024-05-01)",
+		},
+		{
+			name:   "the branch has moved",
+			line:   "  uses: suzuki-shunsuke/foo@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # main (2024-05-01) # comment",
+			update: true,
+			cfg:    &Config{},
+			exp:    "  uses: suzuki-shunsuke/foo@ee0669bd1cc54295c223e0bb666b733df41de1c5 # main (2024-06-01) # comment",
+		},
+		{
+			name:   "the branch hasn't moved",
+			line:   "  uses: suzuki-shunsuke/foo@ee0669bd1cc54295c223e0bb666b733df41de1c5 # main (2024-05-01)",
+			update: true,
+			cfg:    &Config{},
+			exp:    "  uses: suzuki-shunsuke/foo@ee0669bd1cc54295c223e0bb666b733df41de1c5 # main (2024-05-01)",
+		},
+	}
+	ctx := context.Background()
+	logE := logrus.NewEntry(logrus.New())
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				commits: map[string]*GetCommitSHA1Result{
+					"suzuki-shunsuke/foo/main": {
+						SHA: "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+					},
+				},
+			}, afero.NewMemMapFs())
+			ctrl.update = d.update
+			d.cfg.Now = now
+			line, err := ctrl.parseLine(ctx, logE, d.line, d.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line != d.exp {
+				t.Fatalf(`wanted %s, got %s`, d.exp, line)
+			}
+		})
+	}
+}
+
+func TestController_checkBranchPins(t *testing.T) {
+	t.Parallel()
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	reporter := &testReporter{}
+	ctrl.SetReporter(reporter)
+	ctrl.checkBranchPins(logrus.NewEntry(logrus.New()), "test.yaml", []string{
+		"  - uses: suzuki-shunsuke/foo@ee0669bd1cc54295c223e0bb666b733df41de1c5 # main (2024-06-01)",
+		"  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		"  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # pinned for reasons",
+	})
+	if len(reporter.findings) != 1 {
+		t.Fatalf("wanted 1 finding, got %d", len(reporter.findings))
+	}
+	if f := reporter.findings[0]; f.Kind != FindingKindBranchPin || f.Line != 1 {
+		t.Fatalf("unexpected finding: %+v", f)
+	}
+}
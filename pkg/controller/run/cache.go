@@ -0,0 +1,274 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+// apiCache is API responses persisted in a cache file.
+// Only successful responses and missing references are persisted, so errors such as rate limiting are retried in subsequent runs.
+type apiCache struct {
+	CreatedAt time.Time                          `json:"created_at"`
+	Tags      map[string]*cachedTags             `json:"tags,omitempty"`
+	Releases  map[string]*cachedReleases         `json:"releases,omitempty"`
+	Commits   map[string]string                  `json:"commits,omitempty"`
+	Repos     map[string]*github.Repository      `json:"repos,omitempty"`
+	Matching  map[string][]*github.RepositoryTag `json:"matching_tags,omitempty"`
+	// MissingRefs are references which weren't found and the time when they were found missing.
+	// They're cached only for a short TTL.
+	MissingRefs map[string]time.Time `json:"missing_refs,omitempty"`
+}
+
+type cachedTags struct {
+	Tags     []*github.RepositoryTag `json:"tags"`
+	NextPage int                     `json:"next_page,omitempty"`
+}
+
+type cachedReleases struct {
+	Releases []*github.RepositoryRelease `json:"releases"`
+	NextPage int                         `json:"next_page,omitempty"`
+}
+
+func nextPage(resp *github.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.NextPage
+}
+
+func (r *RepositoriesServiceImpl) dump(now time.Time) *apiCache {
+	cache := &apiCache{
+		CreatedAt:   now,
+		Tags:        map[string]*cachedTags{},
+		Releases:    map[string]*cachedReleases{},
+		Commits:     map[string]string{},
+		Repos:       map[string]*github.Repository{},
+		Matching:    map[string][]*github.RepositoryTag{},
+		MissingRefs: map[string]time.Time{},
+	}
+	for k, v := range r.tags {
+		if v.err == nil {
+			cache.Tags[k] = &cachedTags{Tags: v.Tags, NextPage: nextPage(v.Response)}
+		}
+	}
+	for k, v := range r.releases {
+		if v.err == nil {
+			cache.Releases[k] = &cachedReleases{Releases: v.Releases, NextPage: nextPage(v.Response)}
+		}
+	}
+	for k, v := range r.commits {
+		if v.err == nil {
+			cache.Commits[k] = v.SHA
+			continue
+		}
+		if !v.notFoundAt.IsZero() && !v.isExpired(now) {
+			cache.MissingRefs[k] = v.notFoundAt
+		}
+	}
+	for k, v := range r.repos {
+		if v.err == nil {
+			cache.Repos[k] = v.Repository
+		}
+	}
+	for k, v := range r.matchingTags {
+		if v.err == nil {
+			cache.Matching[k] = v.Tags
+		}
+	}
+	return cache
+}
+
+func (r *RepositoriesServiceImpl) load(cache *apiCache) {
+	for k, v := range cache.Tags {
+		r.tags[k] = &ListTagsResult{Tags: v.Tags, Response: &github.Response{NextPage: v.NextPage}}
+	}
+	for k, v := range cache.Releases {
+		r.releases[k] = &ListReleasesResult{Releases: v.Releases, Response: &github.Response{NextPage: v.NextPage}}
+	}
+	for k, v := range cache.Commits {
+		r.commits[k] = &GetCommitSHA1Result{SHA: v, Response: &github.Response{}}
+	}
+	now := r.getNow()
+	for k, v := range cache.MissingRefs {
+		a := &GetCommitSHA1Result{Response: &github.Response{}, err: newNotFoundError(k), notFoundAt: v}
+		if !a.isExpired(now) {
+			r.commits[k] = a
+		}
+	}
+	for k, v := range cache.Repos {
+		r.repos[k] = &GetRepositoryResult{Repository: v, Response: &github.Response{}}
+	}
+	for k, v := range cache.Matching {
+		r.matchingTags[k] = &ListMatchingTagsResult{Tags: v, Response: &github.Response{}}
+	}
+}
+
+// LoadCache loads API responses from a cache file.
+// If the cache file doesn't exist, it does nothing.
+// If the expected checksum is set by --cache-checksum, the cache file is verified by it and the checksum file is ignored.
+// Otherwise the cache file is verified by the SHA256 checksum recorded by SaveCache.
+// The checksum file is shipped together with the cache file, so it detects corruption but not tampering.
+// If the checksum file doesn't exist, the cache file is ignored with a warning, and if the checksum doesn't match, it fails.
+func (c *Controller) LoadCache(logE *logrus.Entry, cacheFilePath, expectedChecksum string) error {
+	c.cacheFilePath = cacheFilePath
+	r, ok := c.repositoriesService.(*RepositoriesServiceImpl)
+	if !ok {
+		return nil
+	}
+	b, err := afero.ReadFile(c.fs, cacheFilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read a cache file: %w", err)
+	}
+	checksum := expectedChecksum
+	if checksum != "" {
+		if err := validateChecksum(checksum); err != nil {
+			return fmt.Errorf("parse --cache-checksum: %w", err)
+		}
+	} else {
+		cs, err := afero.ReadFile(c.fs, cacheFilePath+checksumFileSuffix)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// Unverified responses must not flow into rewrites.
+				logE.WithFields(logrus.Fields{
+					"cache_file":    cacheFilePath,
+					"checksum_file": cacheFilePath + checksumFileSuffix,
+				}).Warn("the cache file is ignored because the checksum file isn't found")
+				return nil
+			}
+			return fmt.Errorf("read a checksum file of a cache file: %w", err)
+		}
+		checksum = string(cs)
+	}
+	if err := verifyChecksum(b, checksum); err != nil {
+		return logerr.WithFields(fmt.Errorf("verify a cache file: %w", err), logrus.Fields{ //nolint:wrapcheck
+			"cache_file": cacheFilePath,
+		})
+	}
+	cache := &apiCache{}
+	if err := json.Unmarshal(b, cache); err != nil {
+		return fmt.Errorf("decode a cache file as JSON: %w", err)
+	}
+	r.load(cache)
+	return nil
+}
+
+// SaveCache persists API responses to the cache file loaded by LoadCache.
+// If no cache file is loaded, it does nothing.
+func (c *Controller) SaveCache(logE *logrus.Entry) {
+	if c.cacheFilePath == "" {
+		return
+	}
+	if err := c.saveCache(time.Now()); err != nil {
+		logerr.WithError(logE, err).WithField("cache_file", c.cacheFilePath).Warn("save a cache file")
+	}
+}
+
+func (c *Controller) saveCache(now time.Time) error {
+	r, ok := c.repositoriesService.(*RepositoriesServiceImpl)
+	if !ok {
+		return nil
+	}
+	b, err := json.Marshal(r.dump(now))
+	if err != nil {
+		return fmt.Errorf("encode a cache as JSON: %w", err)
+	}
+	if err := c.fs.MkdirAll(filepath.Dir(c.cacheFilePath), 0o755); err != nil { //nolint:mnd
+		return fmt.Errorf("create a directory for a cache file: %w", err)
+	}
+	if err := afero.WriteFile(c.fs, c.cacheFilePath, b, filePermission); err != nil {
+		return fmt.Errorf("write a cache file: %w", err)
+	}
+	if err := afero.WriteFile(c.fs, c.cacheFilePath+checksumFileSuffix, []byte(getChecksum(b)+"\n"), filePermission); err != nil {
+		return fmt.Errorf("write a checksum file of a cache file: %w", err)
+	}
+	return nil
+}
+
+type ParamWarmCache struct {
+	WorkflowFilePaths []string
+	ConfigFilePath    string
+	PWD               string
+	Now               time.Time
+}
+
+// WarmCache prefetches tags, releases, and commit hashes of actions used in target files and saves them to the cache file.
+// Subsequent runs with the cache file hardly call GitHub API.
+func (c *Controller) WarmCache(ctx context.Context, logE *logrus.Entry, param *ParamWarmCache) error {
+	if c.cacheFilePath == "" {
+		return errors.New("a cache file isn't set")
+	}
+	cfg := &Config{}
+	if err := c.readConfig(logE, param.ConfigFilePath, param.PWD, cfg); err != nil {
+		return err
+	}
+	if err := cfg.Init(); err != nil {
+		return fmt.Errorf("initialize the configuration: %w", err)
+	}
+	cfg.Now = param.Now
+	workflowFilePaths, err := c.searchFiles(logE, param.WorkflowFilePaths, cfg, param.PWD)
+	if err != nil {
+		return fmt.Errorf("search target files: %w", err)
+	}
+	repos := map[string]struct{}{}
+	for _, workflowFilePath := range workflowFilePaths {
+		logE := logE.WithField("workflow_file", workflowFilePath)
+		if !filepath.IsAbs(workflowFilePath) {
+			workflowFilePath = filepath.Join(param.PWD, workflowFilePath)
+		}
+		lines, err := c.readWorkflow(workflowFilePath)
+		if err != nil {
+			logerr.WithError(logE, err).Warn("read a workflow")
+			continue
+		}
+		for _, line := range lines {
+			action := c.getTargetAction(logE, line, cfg)
+			if action == nil {
+				continue
+			}
+			c.warmAction(ctx, logE.WithField("action", action.Name), action, repos)
+		}
+	}
+	if err := c.saveCache(param.Now); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Controller) warmAction(ctx context.Context, logE *logrus.Entry, action *Action, repos map[string]struct{}) {
+	for _, ref := range []string{action.Version, action.Tag} {
+		if ref == "" || getVersionType(ref) == FullCommitSHA {
+			continue
+		}
+		if _, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, ref, ""); err != nil {
+			logerr.WithError(logE, err).WithField("ref", ref).Warn("get a commit hash")
+		}
+	}
+	key := action.RepoOwner + "/" + action.RepoName
+	if _, ok := repos[key]; ok {
+		return
+	}
+	repos[key] = struct{}{}
+	if _, _, err := c.repositoriesService.ListTags(ctx, action.RepoOwner, action.RepoName, &github.ListOptions{
+		PerPage: 100, //nolint:mnd
+	}); err != nil {
+		logerr.WithError(logE, err).Warn("list tags")
+	}
+	if _, _, err := c.repositoriesService.ListReleases(ctx, action.RepoOwner, action.RepoName, &github.ListOptions{
+		PerPage: 30, //nolint:mnd
+	}); err != nil {
+		logerr.WithError(logE, err).Warn("list releases")
+	}
+}
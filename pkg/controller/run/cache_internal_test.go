@@ -0,0 +1,132 @@
+package run
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+func TestController_saveCache(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	repoService := newRepositoriesServiceImpl(nil)
+	repoService.tags["actions/checkout/0"] = &ListTagsResult{
+		Tags: []*github.RepositoryTag{
+			{
+				Name: util.StrP("v4.2.2"),
+				Commit: &github.Commit{
+					SHA: util.StrP("11bd71901bbe5b1630ceea73d27597364c9af683"),
+				},
+			},
+		},
+		Response: &github.Response{
+			NextPage: 2,
+		},
+	}
+	repoService.commits["actions/checkout/v4"] = &GetCommitSHA1Result{
+		SHA: "11bd71901bbe5b1630ceea73d27597364c9af683",
+	}
+	repoService.commits["suzuki-shunsuke/private-action/v1"] = &GetCommitSHA1Result{
+		err: &github.ErrorResponse{},
+	}
+	ctrl := NewController(repoService, fs)
+	if err := ctrl.LoadCache(logrus.NewEntry(logrus.New()), "/cache/pinact.json", ""); err != nil {
+		t.Fatal(err)
+	}
+	now, err := time.Parse(time.RFC3339, "2024-06-01T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.saveCache(now); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := newRepositoriesServiceImpl(nil)
+	if err := NewController(loaded, fs).LoadCache(logrus.NewEntry(logrus.New()), "/cache/pinact.json", ""); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(repoService.dump(now), loaded.dump(now)); diff != "" {
+		t.Fatal(diff)
+	}
+	if _, ok := loaded.commits["suzuki-shunsuke/private-action/v1"]; ok {
+		t.Fatal("errors must not be cached")
+	}
+	if loaded.tags["actions/checkout/0"].Response.NextPage != 2 {
+		t.Fatal("the next page must be cached")
+	}
+}
+
+func TestController_LoadCache_checksum(t *testing.T) {
+	t.Parallel()
+	content := []byte(`{"commits":{"actions/checkout/v4":"11bd71901bbe5b1630ceea73d27597364c9af683"}}`)
+	data := []struct {
+		name     string
+		checksum string
+		expected string
+		loaded   bool
+		isErr    bool
+	}{
+		{
+			name:     "verified",
+			checksum: getChecksum(content) + "\n",
+			loaded:   true,
+		},
+		{
+			name: "no checksum file",
+		},
+		{
+			name:     "tampered",
+			checksum: "0000000000000000000000000000000000000000000000000000000000000000\n",
+			isErr:    true,
+		},
+		{
+			name:     "verified by the expected checksum",
+			expected: "sha256:" + getChecksum(content),
+			loaded:   true,
+		},
+		{
+			name:     "the expected checksum takes precedence over the checksum file",
+			checksum: getChecksum(content) + "\n",
+			expected: "0000000000000000000000000000000000000000000000000000000000000000",
+			isErr:    true,
+		},
+		{
+			name:     "invalid expected checksum",
+			expected: "invalid",
+			isErr:    true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			if err := afero.WriteFile(fs, "/cache/pinact.json", content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if d.checksum != "" {
+				if err := afero.WriteFile(fs, "/cache/pinact.json.sha256", []byte(d.checksum), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			repoService := newRepositoriesServiceImpl(nil)
+			err := NewController(repoService, fs).LoadCache(logrus.NewEntry(logrus.New()), "/cache/pinact.json", d.expected)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if _, ok := repoService.commits["actions/checkout/v4"]; ok != d.loaded {
+				t.Fatalf("wanted loaded=%v, got %v", d.loaded, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+const (
+	checkRunName = "pinact"
+	// maxCheckRunAnnotations is the maximum number of annotations per request of Checks API.
+	maxCheckRunAnnotations = 50
+)
+
+type ChecksService interface {
+	CreateCheckRun(ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+	UpdateCheckRun(ctx context.Context, owner, repo string, checkRunID int64, opts github.UpdateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+}
+
+// ParamCheckRun is a commit where a check run is created.
+type ParamCheckRun struct {
+	Owner   string
+	Repo    string
+	HeadSHA string
+}
+
+// findingCollector collects findings and forwards events to the next reporter.
+type findingCollector struct {
+	next     Reporter
+	findings []*Finding
+}
+
+func newFindingCollector(next Reporter) *findingCollector {
+	return &findingCollector{
+		next: next,
+	}
+}
+
+func (r *findingCollector) OnFileStart(path string) {
+	r.next.OnFileStart(path)
+}
+
+func (r *findingCollector) OnFinding(finding *Finding) {
+	r.next.OnFinding(finding)
+	r.findings = append(r.findings, finding)
+}
+
+func (r *findingCollector) OnFileEnd(path string, changed bool) {
+	r.next.OnFileEnd(path, changed)
+}
+
+// sort sorts collected findings by the key of --sort.
+func (r *findingCollector) sort(key string) {
+	sortFindings(r.findings, key)
+}
+
+// createCheckRun creates a completed check run with annotations of findings.
+// File paths of findings are converted to paths relative to pwd, which must be the repository root.
+// Findings not related to specific lines are written in the summary because annotations require lines.
+func (c *Controller) createCheckRun(ctx context.Context, logE *logrus.Entry, param *ParamCheckRun, pwd string, findings []*Finding) error {
+	annotations := []*github.CheckRunAnnotation{}
+	summaries := []string{}
+	conclusion := "success"
+	for _, finding := range findings {
+		level := getAnnotationLevel(finding)
+		if level == "failure" {
+			conclusion = "failure"
+		} else if conclusion == "success" {
+			conclusion = "neutral"
+		}
+		path := finding.File
+		if filepath.IsAbs(path) {
+			if rel, err := filepath.Rel(pwd, path); err == nil {
+				path = rel
+			}
+		}
+		path = filepath.ToSlash(path)
+		msg := getFindingMessage(finding)
+		if finding.Line == 0 {
+			summaries = append(summaries, fmt.Sprintf("- %s: %s", path, msg))
+			continue
+		}
+		annotation := &github.CheckRunAnnotation{
+			Path:            util.StrP(path),
+			StartLine:       util.IntP(finding.Line),
+			EndLine:         util.IntP(finding.Line),
+			AnnotationLevel: util.StrP(level),
+			Title:           util.StrP(finding.Kind),
+			Message:         util.StrP(msg),
+		}
+		if finding.Kind == FindingKindChanged {
+			annotation.RawDetails = util.StrP(strings.TrimSpace(finding.After))
+		}
+		annotations = append(annotations, annotation)
+	}
+	summary := fmt.Sprintf("pinact found %d issues.", len(findings))
+	if len(summaries) != 0 {
+		summary += "\n\n" + strings.Join(summaries, "\n")
+	}
+	title := fmt.Sprintf("%d issues", len(findings))
+	first := annotations
+	if len(first) > maxCheckRunAnnotations {
+		first = first[:maxCheckRunAnnotations]
+	}
+	checkRun, _, err := c.checksService.CreateCheckRun(ctx, param.Owner, param.Repo, github.CreateCheckRunOptions{
+		Name:       checkRunName,
+		HeadSHA:    param.HeadSHA,
+		Status:     util.StrP("completed"),
+		Conclusion: util.StrP(conclusion),
+		Output: &github.CheckRunOutput{
+			Title:       util.StrP(title),
+			Summary:     util.StrP(summary),
+			Annotations: first,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create a check run: %w", err)
+	}
+	// Annotations are appended by updating the check run because the number of annotations per request is limited.
+	for i := maxCheckRunAnnotations; i < len(annotations); i += maxCheckRunAnnotations {
+		if _, _, err := c.checksService.UpdateCheckRun(ctx, param.Owner, param.Repo, checkRun.GetID(), github.UpdateCheckRunOptions{
+			Name: checkRunName,
+			Output: &github.CheckRunOutput{
+				Title:       util.StrP(title),
+				Summary:     util.StrP(summary),
+				Annotations: annotations[i:min(i+maxCheckRunAnnotations, len(annotations))],
+			},
+		}); err != nil {
+			return fmt.Errorf("add annotations to a check run: %w", err)
+		}
+	}
+	logE.WithFields(logrus.Fields{
+		"check_run_url": checkRun.GetHTMLURL(),
+		"conclusion":    conclusion,
+	}).Info("create a check run")
+	return nil
+}
+
+func getAnnotationLevel(finding *Finding) string {
+	switch finding.Severity {
+	case SeverityError:
+		return "failure"
+	case SeverityWarn:
+		return "warning"
+	}
+	switch finding.Kind {
+	case FindingKindError, FindingKindCompromisedAction:
+		return "failure"
+	default:
+		return "warning"
+	}
+}
+
+func getFindingMessage(finding *Finding) string {
+	if finding.Message != "" {
+		return finding.Message
+	}
+	if finding.Kind == FindingKindChanged {
+		return lintMessage(finding.Before, finding.After)
+	}
+	return finding.Kind
+}
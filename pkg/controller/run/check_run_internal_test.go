@@ -0,0 +1,88 @@
+package run
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+type testChecksService struct {
+	created []github.CreateCheckRunOptions
+	updated []github.UpdateCheckRunOptions
+}
+
+func (s *testChecksService) CreateCheckRun(_ context.Context, _, _ string, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	s.created = append(s.created, opts)
+	return &github.CheckRun{}, &github.Response{}, nil
+}
+
+func (s *testChecksService) UpdateCheckRun(_ context.Context, _, _ string, _ int64, opts github.UpdateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	s.updated = append(s.updated, opts)
+	return &github.CheckRun{}, &github.Response{}, nil
+}
+
+func TestController_createCheckRun(t *testing.T) {
+	t.Parallel()
+	checks := &testChecksService{}
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	ctrl.checksService = checks
+	findings := []*Finding{
+		{
+			Kind:   FindingKindChanged,
+			File:   "/home/foo/repo/.github/workflows/test.yaml",
+			Line:   4,
+			Before: "      - uses: actions/checkout@v2",
+			After:  "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+		},
+		{
+			Kind:    FindingKindDuplicateVersions,
+			File:    "/home/foo/repo/.github/workflows/test.yaml",
+			Message: "actions/checkout is used at multiple versions in the job test: v2, v3",
+		},
+	}
+	for range maxCheckRunAnnotations {
+		findings = append(findings, &Finding{
+			Kind:    FindingKindError,
+			File:    "/home/foo/repo/.github/workflows/test.yaml",
+			Line:    5,
+			Message: "get a commit hash",
+		})
+	}
+	if err := ctrl.createCheckRun(context.Background(), logrus.NewEntry(logrus.New()), &ParamCheckRun{
+		Owner:   "suzuki-shunsuke",
+		Repo:    "pinact",
+		HeadSHA: "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+	}, "/home/foo/repo", findings); err != nil {
+		t.Fatal(err)
+	}
+	if len(checks.created) != 1 {
+		t.Fatalf("wanted 1 check run, got %d", len(checks.created))
+	}
+	created := checks.created[0]
+	if diff := cmp.Diff(util.StrP("failure"), created.Conclusion); diff != "" {
+		t.Fatal(diff)
+	}
+	if len(created.Output.Annotations) != maxCheckRunAnnotations {
+		t.Fatalf("wanted %d annotations, got %d", maxCheckRunAnnotations, len(created.Output.Annotations))
+	}
+	exp := &github.CheckRunAnnotation{
+		Path:            util.StrP(".github/workflows/test.yaml"),
+		StartLine:       util.IntP(4),
+		EndLine:         util.IntP(4),
+		AnnotationLevel: util.StrP("warning"),
+		Title:           util.StrP(FindingKindChanged),
+		Message:         util.StrP("the action isn't pinned"),
+		RawDetails:      util.StrP("- uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0"),
+	}
+	if diff := cmp.Diff(exp, created.Output.Annotations[0]); diff != "" {
+		t.Fatal(diff)
+	}
+	if len(checks.updated) != 1 || len(checks.updated[0].Output.Annotations) != 1 {
+		t.Fatal("remaining annotations must be added by updating the check run")
+	}
+}
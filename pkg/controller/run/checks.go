@@ -0,0 +1,229 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+const (
+	// SeverityError reports findings as errors and makes the run fail.
+	SeverityError = "error"
+	// SeverityWarn reports findings as warnings.
+	SeverityWarn = "warn"
+	// SeverityOff disables the check. Lines aren't changed nor reported.
+	SeverityOff = "off"
+
+	checkUnpinned           = "unpinned"
+	checkOutdated           = "outdated"
+	checkAnnotationMismatch = "annotation_mismatch"
+	checkDynamicRef         = "dynamic_ref"
+	checkShortAnnotation    = "short_annotation"
+)
+
+// errAnnotationMismatch means the commit hash of an action doesn't match the version annotation.
+var errAnnotationMismatch = errors.New("action_version must be equal to commit_hash_of_version_annotation")
+
+// Checks configures the severity of each check.
+// If the severity of a check isn't set, pinact behaves as before checks were configurable.
+type Checks struct {
+	Unpinned           string `json:"unpinned,omitempty" jsonschema:"description=The severity of actions which aren't pinned by commit hashes. If this is off pinact doesn't pin them,enum=error,enum=warn,enum=off"`
+	Outdated           string `json:"outdated,omitempty" jsonschema:"description=The severity of actions which would be updated. If this is off pinact doesn't update them,enum=error,enum=warn,enum=off"`
+	AnnotationMismatch string `json:"annotation_mismatch,omitempty" yaml:"annotation_mismatch" jsonschema:"description=The severity of version annotations which don't match commit hashes. This requires --verify,enum=error,enum=warn,enum=off"`
+	DynamicRef         string `json:"dynamic_ref,omitempty" yaml:"dynamic_ref" jsonschema:"description=The severity of actions referenced by branches or other refs which pinact can't pin. This check is disabled by default,enum=error,enum=warn,enum=off"`
+	ShortAnnotation    string `json:"short_annotation,omitempty" yaml:"short_annotation" jsonschema:"description=The severity of version annotations which aren't full semver such as # v4. If this is off pinact doesn't expand them,enum=error,enum=warn,enum=off"`
+}
+
+func (c *Checks) validate() error {
+	for _, check := range []struct {
+		name     string
+		severity string
+	}{
+		{name: checkUnpinned, severity: c.Unpinned},
+		{name: checkOutdated, severity: c.Outdated},
+		{name: checkAnnotationMismatch, severity: c.AnnotationMismatch},
+		{name: checkDynamicRef, severity: c.DynamicRef},
+		{name: checkShortAnnotation, severity: c.ShortAnnotation},
+	} {
+		switch check.severity {
+		case "", SeverityError, SeverityWarn, SeverityOff:
+		default:
+			return fmt.Errorf("checks.%s must be error, warn, or off: %s", check.name, check.severity)
+		}
+	}
+	return nil
+}
+
+// getSeverity returns the severity of the check.
+// If the check isn't configured, it returns an empty string.
+func (c *Config) getSeverity(check string) string {
+	if c.Checks == nil {
+		return ""
+	}
+	switch check {
+	case checkUnpinned:
+		return c.Checks.Unpinned
+	case checkOutdated:
+		return c.Checks.Outdated
+	case checkAnnotationMismatch:
+		return c.Checks.AnnotationMismatch
+	case checkDynamicRef:
+		return c.Checks.DynamicRef
+	case checkShortAnnotation:
+		return c.Checks.ShortAnnotation
+	default:
+		return ""
+	}
+}
+
+// getChangeCheck returns the check of a changed line.
+// If the change is neither pinning, updating, nor expanding a short version annotation, such as adding a version annotation, it returns an empty string.
+func getChangeCheck(before, after string) string {
+	b := parseAction(before)
+	a := parseAction(after)
+	if b == nil || a == nil {
+		return ""
+	}
+	if getVersionType(b.Version) != FullCommitSHA {
+		return checkUnpinned
+	}
+	if b.Version != a.Version {
+		return checkOutdated
+	}
+	if isShortAnnotation(b) && getVersionType(a.Tag) == Semver {
+		return checkShortAnnotation
+	}
+	return ""
+}
+
+// isShortAnnotation returns true if the action is pinned and the version annotation isn't full semver such as # v4.
+func isShortAnnotation(action *Action) bool {
+	return getVersionType(action.Version) == FullCommitSHA && getVersionType(action.Tag) == Shortsemver
+}
+
+// getErrorCheck returns the check of an error of a line.
+func getErrorCheck(err error) string {
+	if errors.Is(err, errAnnotationMismatch) {
+		return checkAnnotationMismatch
+	}
+	return ""
+}
+
+// logCheck logs a finding of a check at the level of the severity.
+func logCheck(logE *logrus.Entry, severity, check, msg string) {
+	logE = logE.WithField("check", check)
+	switch severity {
+	case SeverityError:
+		logE.Error(msg)
+	case SeverityWarn:
+		logE.Warn(msg)
+	}
+}
+
+// checkDynamicRefs reports actions referenced by branches or other refs which aren't versions nor commit hashes.
+// pinact can't pin such actions to versions.
+// It returns the number of findings whose severity is error.
+func (c *Controller) checkDynamicRefs(logE *logrus.Entry, file string, lines []string, cfg *Config) int {
+	severity := cfg.getSeverity(checkDynamicRef)
+	if severity == "" || severity == SeverityOff {
+		return 0
+	}
+	failures := 0
+	for i, line := range lines {
+		action := parseAction(line)
+		if action == nil || isDockerAction(action.Name) || getVersionType(action.Version) != Other {
+			continue
+		}
+		if isIgnoredInline(logE, line, cfg) {
+			continue
+		}
+		msg := "the action is referenced by a ref which is neither a version nor a commit hash"
+		logCheck(logE.WithFields(logrus.Fields{
+			"action":      action.Name,
+			"version":     action.Version,
+			"line_number": i + 1,
+		}), severity, checkDynamicRef, msg)
+		c.reporter.OnFinding(&Finding{
+			Kind:     FindingKindDynamicRef,
+			File:     file,
+			Line:     i + 1,
+			Before:   line,
+			After:    line,
+			Message:  msg,
+			Severity: severity,
+		})
+		if severity == SeverityError {
+			failures++
+		}
+	}
+	return failures
+}
+
+// reportShortAnnotation reports a version annotation which isn't full semver without calling GitHub API.
+// It's used in the offline mode, where pinact can't expand the annotation.
+func reportShortAnnotation(logE *logrus.Entry, cfg *Config, stats *Stats) {
+	severity := cfg.getSeverity(checkShortAnnotation)
+	switch severity {
+	case SeverityError:
+		stats.CheckFailures++
+	case SeverityWarn:
+	default:
+		return
+	}
+	logCheck(logE, severity, checkShortAnnotation, "the version annotation isn't full semver. Please run pinact run to expand it")
+}
+
+// reportLineError reports an error of a line by the severity of the check.
+func (c *Controller) reportLineError(logE *logrus.Entry, file string, i int, line string, err error, cfg *Config, stats *Stats) {
+	check := getErrorCheck(err)
+	severity := cfg.getSeverity(check)
+	switch severity {
+	case SeverityOff:
+		return
+	case SeverityWarn:
+		logerr.WithError(logE, err).WithField("check", check).Warn("parse a line")
+	default:
+		// Errors of checks which aren't configured are also errors.
+		logerr.WithError(logE, err).Error("parse a line")
+		stats.Errors++
+	}
+	if severity == SeverityError {
+		stats.CheckFailures++
+	}
+	c.reporter.OnFinding(&Finding{
+		Kind:     FindingKindError,
+		File:     file,
+		Line:     i + 1,
+		Before:   line,
+		After:    line,
+		Message:  err.Error(),
+		Severity: severity,
+	})
+}
+
+// reportChange reports a changed line by the severity of the check and returns the new line.
+// If the check is off, the change is discarded and the original line is returned.
+func (c *Controller) reportChange(logE *logrus.Entry, file string, i int, before, after string, cfg *Config, stats *Stats) string {
+	check := getChangeCheck(before, after)
+	severity := cfg.getSeverity(check)
+	if severity == SeverityOff {
+		return before
+	}
+	if severity != "" {
+		logCheck(logE.WithField("line_number", i+1), severity, check, lintMessage(before, after))
+	}
+	if severity == SeverityError {
+		stats.CheckFailures++
+	}
+	c.reporter.OnFinding(&Finding{
+		Kind:     FindingKindChanged,
+		File:     file,
+		Line:     i + 1,
+		Before:   before,
+		After:    after,
+		Severity: severity,
+	})
+	return after
+}
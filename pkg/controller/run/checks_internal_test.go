@@ -0,0 +1,200 @@
+package run
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func Test_getChangeCheck(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		before string
+		after  string
+		exp    string
+	}{
+		{
+			name:   "unpinned",
+			before: "  - uses: actions/checkout@v2",
+			after:  "  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+			exp:    checkUnpinned,
+		},
+		{
+			name:   "outdated",
+			before: "  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+			after:  "  - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2",
+			exp:    checkOutdated,
+		},
+		{
+			name:   "annotation",
+			before: "  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5",
+			after:  "  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+		},
+		{
+			name:   "short annotation",
+			before: "  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2",
+			after:  "  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+			exp:    checkShortAnnotation,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if got := getChangeCheck(d.before, d.after); got != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, got)
+			}
+		})
+	}
+}
+
+func TestController_reportChange(t *testing.T) {
+	t.Parallel()
+	before := "  - uses: actions/checkout@v2"
+	after := "  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0"
+	data := []struct {
+		name     string
+		checks   *Checks
+		exp      string
+		findings int
+		failures int
+	}{
+		{
+			name:     "not configured",
+			exp:      after,
+			findings: 1,
+		},
+		{
+			name:   "off",
+			checks: &Checks{Unpinned: SeverityOff},
+			exp:    before,
+		},
+		{
+			name:     "warn",
+			checks:   &Checks{Unpinned: SeverityWarn},
+			exp:      after,
+			findings: 1,
+		},
+		{
+			name:     "error",
+			checks:   &Checks{Unpinned: SeverityError},
+			exp:      after,
+			findings: 1,
+			failures: 1,
+		},
+		{
+			name:     "another check",
+			checks:   &Checks{Outdated: SeverityOff},
+			exp:      after,
+			findings: 1,
+		},
+	}
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{}, afero.NewMemMapFs())
+			reporter := &testReporter{}
+			ctrl.SetReporter(reporter)
+			stats := &Stats{}
+			got := ctrl.reportChange(logE, "test.yaml", 0, before, after, &Config{Checks: d.checks}, stats)
+			if got != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, got)
+			}
+			if len(reporter.findings) != d.findings {
+				t.Fatalf("wanted %d findings, got %d", d.findings, len(reporter.findings))
+			}
+			if stats.CheckFailures != d.failures {
+				t.Fatalf("wanted %d failures, got %d", d.failures, stats.CheckFailures)
+			}
+		})
+	}
+}
+
+func TestController_reportLineError(t *testing.T) {
+	t.Parallel()
+	line := "  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v3.5.2"
+	data := []struct {
+		name     string
+		err      error
+		checks   *Checks
+		findings int
+		stats    *Stats
+	}{
+		{
+			name:     "not configured",
+			err:      errAnnotationMismatch,
+			findings: 1,
+			stats:    &Stats{Errors: 1},
+		},
+		{
+			name:   "off",
+			err:    errAnnotationMismatch,
+			checks: &Checks{AnnotationMismatch: SeverityOff},
+			stats:  &Stats{},
+		},
+		{
+			name:     "warn",
+			err:      errAnnotationMismatch,
+			checks:   &Checks{AnnotationMismatch: SeverityWarn},
+			findings: 1,
+			stats:    &Stats{},
+		},
+		{
+			name:     "error",
+			err:      errAnnotationMismatch,
+			checks:   &Checks{AnnotationMismatch: SeverityError},
+			findings: 1,
+			stats:    &Stats{Errors: 1, CheckFailures: 1},
+		},
+		{
+			name:     "error which isn't a check",
+			err:      errors.New("get a commit hash"),
+			checks:   &Checks{AnnotationMismatch: SeverityWarn},
+			findings: 1,
+			stats:    &Stats{Errors: 1},
+		},
+	}
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{}, afero.NewMemMapFs())
+			reporter := &testReporter{}
+			ctrl.SetReporter(reporter)
+			stats := &Stats{}
+			ctrl.reportLineError(logE, "test.yaml", 0, line, d.err, &Config{Checks: d.checks}, stats)
+			if len(reporter.findings) != d.findings {
+				t.Fatalf("wanted %d findings, got %d", d.findings, len(reporter.findings))
+			}
+			if diff := cmp.Diff(d.stats, stats); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestController_checkDynamicRefs(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"  - uses: actions/checkout@main",
+		"  - uses: actions/checkout@v4",
+		"  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+		"  - uses: suzuki-shunsuke/foo@main # pinact:ignore",
+	}
+	ctrl := NewController(&RepositoriesServiceImpl{}, afero.NewMemMapFs())
+	reporter := &testReporter{}
+	ctrl.SetReporter(reporter)
+	failures := ctrl.checkDynamicRefs(logrus.NewEntry(logrus.New()), "test.yaml", lines, &Config{
+		Checks: &Checks{DynamicRef: SeverityError},
+	})
+	if failures != 1 {
+		t.Fatalf("wanted 1 failure, got %d", failures)
+	}
+	if len(reporter.findings) != 1 || reporter.findings[0].Line != 1 {
+		t.Fatalf("unexpected findings: %+v", reporter.findings)
+	}
+}
@@ -0,0 +1,52 @@
+package run
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+// checksumFileSuffix is the suffix of a file recording the SHA256 checksum of a cache file.
+// e.g. .pinact-cache.json.sha256
+const checksumFileSuffix = ".sha256"
+
+var (
+	errChecksumMismatch = errors.New("the checksum doesn't match. The data may be tampered")
+	checksumPattern     = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// getChecksum returns the SHA256 checksum of the data as a lowercase hex string.
+func getChecksum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeChecksum trims the prefix "sha256:" and whitespace and lowercases the checksum.
+func normalizeChecksum(checksum string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(checksum), "sha256:"))
+}
+
+func validateChecksum(checksum string) error {
+	if !checksumPattern.MatchString(normalizeChecksum(checksum)) {
+		return fmt.Errorf("a checksum must be a SHA256 hex string: %s", checksum)
+	}
+	return nil
+}
+
+// verifyChecksum returns an error if the SHA256 checksum of the data doesn't match the expected checksum.
+func verifyChecksum(b []byte, expected string) error {
+	actual := getChecksum(b)
+	if actual == normalizeChecksum(expected) {
+		return nil
+	}
+	return logerr.WithFields(errChecksumMismatch, logrus.Fields{ //nolint:wrapcheck
+		"expected_checksum": normalizeChecksum(expected),
+		"actual_checksum":   actual,
+	})
+}
@@ -0,0 +1,54 @@
+package run
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_verifyChecksum(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name     string
+		checksum string
+		isErr    bool
+	}{
+		{
+			name:     "match",
+			checksum: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		},
+		{
+			name:     "prefix and upper case",
+			checksum: "sha256:2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824\n",
+		},
+		{
+			name:     "mismatch",
+			checksum: "0000000000000000000000000000000000000000000000000000000000000000",
+			isErr:    true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			err := verifyChecksum([]byte("hello"), d.checksum)
+			if d.isErr {
+				if !errors.Is(err, errChecksumMismatch) {
+					t.Fatalf("wanted errChecksumMismatch, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func Test_validateChecksum(t *testing.T) {
+	t.Parallel()
+	if err := validateChecksum("sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateChecksum("2cf24dba"); err == nil {
+		t.Fatal("a short checksum must be rejected")
+	}
+}
@@ -1,28 +1,299 @@
 package run
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	// configVersion is the latest configuration schema version supported by this version of pinact.
+	configVersion           = 1
+	schemaVersionCheckError = "error"
+	schemaVersionCheckWarn  = "warn"
+)
+
 type Config struct {
-	Files         []*File         `json:"files,omitempty" jsonschema:"description=Target files. If files are passed via positional command line arguments, this is ignored"`
-	IgnoreActions []*IgnoreAction `json:"ignore_actions,omitempty" yaml:"ignore_actions" jsonschema:"description=Actions and reusable workflows that pinact ignores"`
-	IsVerify      bool            `json:"-" yaml:"-"`
+	Version                int                `json:"version,omitempty" jsonschema:"description=Configuration schema version. The default value is 1"`
+	SchemaVersionCheck     string             `json:"schema_version_check,omitempty" yaml:"schema_version_check" jsonschema:"description=How pinact handles unknown configuration versions and fields. If this is warn pinact outputs warnings and known fields take effect. The default value is error,enum=error,enum=warn"`
+	Files                  []*File            `json:"files,omitempty" jsonschema:"description=Target files. If files are passed via positional command line arguments, this is ignored"`
+	IgnoreActions          []*IgnoreAction    `json:"ignore_actions,omitempty" yaml:"ignore_actions" jsonschema:"description=Actions and reusable workflows that pinact ignores"`
+	GitSSH                 *GitSSH            `json:"git_ssh,omitempty" yaml:"git_ssh" jsonschema:"description=Resolve versions by git ls-remote over SSH instead of GitHub REST API"`
+	GHECom                 *GHECom            `json:"ghe_com,omitempty" yaml:"ghe_com" jsonschema:"description=Resolve versions by GitHub Enterprise Cloud with data residency (GHE.com) instead of github.com. The same access token is used"`
+	ResolverCommand        string             `json:"resolver_command,omitempty" yaml:"resolver_command" jsonschema:"description=Resolve versions by an external command instead of GitHub REST API. The command receives a request as JSON via the standard input and outputs a response as JSON. A relative path is resolved from the working directory"`
+	IgnoreNotFound         []*IgnoreNotFound  `json:"ignore_not_found,omitempty" yaml:"ignore_not_found" jsonschema:"description=Repository owners whose actions are ignored if they aren't found by GitHub API"`
+	FreezeWindows          []*FreezeWindow    `json:"freeze_windows,omitempty" yaml:"freeze_windows" jsonschema:"description=Periods when actions aren't updated by --update"`
+	Cooldowns              []*Cooldown        `json:"cooldowns,omitempty" jsonschema:"description=Days after versions are released before --update adopts them per repository. The first matching rule is used"`
+	FloatingTags           bool               `json:"floating_tags,omitempty" yaml:"floating_tags" jsonschema:"description=Pin floating tags such as v1 without point releases with the resolution date and update them when the tags move"`
+	PinBranches            bool               `json:"pin_branches,omitempty" yaml:"pin_branches" jsonschema:"description=Pin actions referenced by branches such as main with the branch and the resolution date. They're updated by --update when the branches move"`
+	ConfirmThreshold       int                `json:"confirm_threshold,omitempty" yaml:"confirm_threshold" jsonschema:"description=pinact asks for confirmation if a run would modify more files than this. The default value is 100. If this is negative pinact never asks"`
+	Defaults               *Defaults          `json:"defaults,omitempty" jsonschema:"description=Built-in rules about local actions and Docker images"`
+	AdvisoryURL            string             `json:"advisory_url,omitempty" yaml:"advisory_url" jsonschema:"description=A URL of an advisory feed of compromised actions. Advisories are merged with ones bundled with pinact"`
+	AdvisoryChecksum       string             `json:"advisory_checksum,omitempty" yaml:"advisory_checksum" jsonschema:"description=A SHA256 checksum of the advisory feed of advisory_url. If this is set pinact fails if the feed doesn't match"`
+	PullRequest            *PullRequestConfig `json:"pull_request,omitempty" yaml:"pull_request" jsonschema:"description=Templates of pull requests created by pinact pr"`
+	Review                 *ReviewConfig      `json:"review,omitempty" jsonschema:"description=Templates of reviews posted by pinact serve"`
+	Mirrors                []*Mirror          `json:"mirrors,omitempty" jsonschema:"description=Rules replacing actions with mirrors. Mirrors are used only if their tags point to the same commits as the upstreams"`
+	RateLimits             []*RateLimit       `json:"rate_limits,omitempty" yaml:"rate_limits" jsonschema:"description=Limits of API calls per repository owner to avoid secondary rate limits"`
+	ImmutableReleases      bool               `json:"immutable_releases,omitempty" yaml:"immutable_releases" jsonschema:"description=Prefer the latest immutable release when updating actions and append (immutable) to version annotations of immutable releases"`
+	Provenance             bool               `json:"provenance,omitempty" jsonschema:"description=Append the host resolving commit hashes to version annotations such as # v4.2.1 via ghes.example.com"`
+	IncludeOwners          []string           `json:"include_owners,omitempty" yaml:"include_owners" jsonschema:"description=Repository owners of actions that pinact processes. Owners are compared exactly and case-insensitively. If this is empty all owners are processed"`
+	ExcludeOwners          []string           `json:"exclude_owners,omitempty" yaml:"exclude_owners" jsonschema:"description=Repository owners of actions that pinact ignores. Owners are compared exactly and case-insensitively"`
+	Includes               []string           `json:"includes,omitempty" jsonschema:"description=Regular expressions of action names that pinact processes. If this is empty all actions are processed. --include extends this"`
+	Excludes               []string           `json:"excludes,omitempty" jsonschema:"description=Regular expressions of action names that pinact ignores. --exclude extends this"`
+	Checks                 *Checks            `json:"checks,omitempty" jsonschema:"description=Severities of checks. They decide the exit code and levels of findings"`
+	Interop                *Interop           `json:"interop,omitempty" jsonschema:"description=Interoperability with other tools updating actions"`
+	Replacements           []*Replacement     `json:"replacements,omitempty" jsonschema:"description=Alternatives of actions suggested if their repositories are archived. They're checked by --skip-archived"`
+	SkipForks              bool               `json:"skip_forks,omitempty" yaml:"skip_forks" jsonschema:"description=If an action's repository is a fork, --update warns and resolves the latest version against the parent repository"`
+	IsVerify               bool               `json:"-" yaml:"-"`
+	VerifyOnly             *regexp.Regexp     `json:"-" yaml:"-"`
+	CheckDuplicateVersions bool               `json:"-" yaml:"-"`
+	CheckVulnerabilities   bool               `json:"-" yaml:"-"`
+	CheckTransitive        bool               `json:"-" yaml:"-"`
+	VerifyAttestations     bool               `json:"-" yaml:"-"`
+	Why                    bool               `json:"-" yaml:"-"`
+	SkipArchived           bool               `json:"-" yaml:"-"`
+	SecurityOnly           bool               `json:"-" yaml:"-"`
+	AnnotateOnly           bool               `json:"-" yaml:"-"`
+	Jobs                   []string           `json:"-" yaml:"-"`
+	StepName               *regexp.Regexp     `json:"-" yaml:"-"`
+	IncludeFile            *regexp.Regexp     `json:"-" yaml:"-"`
+	ExcludeFile            *regexp.Regexp     `json:"-" yaml:"-"`
+	Now                    time.Time          `json:"-" yaml:"-"`
+	MaxPinAge              time.Duration      `json:"-" yaml:"-"`
+	Since                  time.Duration      `json:"-" yaml:"-"`
+	advisories             []*Advisory
+	usedRules              map[string]struct{}
+	allow                  *AllowFile
+	annotations            *annotationIndex
+	renovateIgnores        []*renovateIgnore
+	includes               []*regexp.Regexp
+	excludes               []*regexp.Regexp
+	// skippedUpdates is the number of skipped updates per reason.
+	skippedUpdates map[string]int
+	// cooledDownVersions is the number of versions skipped by cooldowns.
+	// It's used to find updates held back by cooldowns.
+	cooledDownVersions int
+	// archivedActions are actions found to be archived while the current line is parsed.
+	archivedActions []*archivedAction
+}
+
+const (
+	skipReasonFreezeWindow = "freeze window"
+	skipReasonCooldown     = "cooldown"
+	skipReasonSince        = "--since"
+)
+
+// skipReasons are reasons of skipped updates in the order of the summary.
+var skipReasons = []string{skipReasonFreezeWindow, skipReasonCooldown, skipReasonSince} //nolint:gochecknoglobals
+
+// skipUpdate counts an update skipped by the reason.
+func (c *Config) skipUpdate(reason string) {
+	if c.skippedUpdates == nil {
+		c.skippedUpdates = map[string]int{}
+	}
+	c.skippedUpdates[reason]++
+}
+
+// setSkippedUpdates sets the numbers of skipped updates to the stats.
+func (c *Config) setSkippedUpdates(stats *Stats) {
+	stats.SkippedUpdates = 0
+	for _, n := range c.skippedUpdates {
+		stats.SkippedUpdates += n
+	}
+	if len(c.skippedUpdates) != 0 {
+		stats.SkippedUpdatesByReason = maps.Clone(c.skippedUpdates)
+	}
 }
 
 type File struct {
 	Pattern string `json:"pattern" jsonschema:"description=A regular expression of target files. If files are passed via positional command line arguments, this is ignored"`
+	Type    string `json:"type,omitempty" jsonschema:"description=A file type deciding how files are parsed. By default it's detected by the file name,enum=github-workflow,enum=github-action,enum=gitlab-ci,enum=dockerfile,enum=devcontainer"`
+	pattern *regexp.Regexp
+}
+
+func (f *File) init() error {
+	if err := validateFileType(f.Type); err != nil {
+		return err
+	}
+	if f.Type == "" || f.Pattern == "" {
+		return nil
+	}
+	p, err := regexp.Compile(f.Pattern)
+	if err != nil {
+		return fmt.Errorf("parse files[].pattern as a regular expression: %w", err)
+	}
+	f.pattern = p
+	return nil
 }
 
 type IgnoreAction struct {
-	Name string `json:"name" jsonschema:"description=Action and reusable workflow names that pinact ignores"`
+	Name string `json:"name" jsonschema:"description=Action and reusable workflow names that pinact ignores. Glob patterns such as owner/repo/.github/workflows/*.yml are supported. * doesn't match /. If the name includes @ it's matched with the action name and the version"`
+}
+
+func (a *IgnoreAction) init() error {
+	if _, err := path.Match(a.Name, ""); err != nil {
+		return fmt.Errorf("parse ignore_actions[].name as a glob pattern: %w", err)
+	}
+	return nil
+}
+
+// match returns true if the rule matches the action.
+// As * doesn't match /, the action root owner/repo and reusable workflows owner/repo/.github/workflows/foo.yml are distinguished.
+func (a *IgnoreAction) match(action *Action) bool {
+	name := action.Name
+	if strings.Contains(a.Name, "@") {
+		name += "@" + action.Version
+	}
+	f, err := path.Match(a.Name, name)
+	return err == nil && f
+}
+
+type IgnoreNotFound struct {
+	Owner string `json:"owner" jsonschema:"description=A regular expression of repository owners. Actions of these owners are ignored if they aren't found"`
+	owner *regexp.Regexp
+}
+
+type GitSSH struct {
+	Enabled bool   `json:"enabled,omitempty" jsonschema:"description=Resolve versions by git ls-remote over SSH"`
+	Host    string `json:"host,omitempty" jsonschema:"description=SSH host. The default value is github.com"`
+	User    string `json:"user,omitempty" jsonschema:"description=SSH user. The default value is git"`
+	KeyPath string `json:"key_path,omitempty" yaml:"key_path" jsonschema:"description=A file path to a SSH private key such as a deploy key"`
+}
+
+// Init validates the configuration and compiles regular expressions.
+func (c *Config) Init() error {
+	for _, file := range c.Files {
+		if err := file.init(); err != nil {
+			return err
+		}
+	}
+	for _, ignoreAction := range c.IgnoreActions {
+		if err := ignoreAction.init(); err != nil {
+			return err
+		}
+	}
+	for _, ignoreNotFound := range c.IgnoreNotFound {
+		p, err := regexp.Compile(ignoreNotFound.Owner)
+		if err != nil {
+			return fmt.Errorf("parse ignore_not_found[].owner as a regular expression: %w", err)
+		}
+		ignoreNotFound.owner = p
+	}
+	for _, w := range c.FreezeWindows {
+		if err := w.init(); err != nil {
+			return err
+		}
+	}
+	if err := c.initActionFilters(); err != nil {
+		return err
+	}
+	for _, cooldown := range c.Cooldowns {
+		if err := cooldown.init(); err != nil {
+			return err
+		}
+	}
+	for _, mirror := range c.Mirrors {
+		if err := mirror.init(); err != nil {
+			return err
+		}
+	}
+	for _, replacement := range c.Replacements {
+		if err := replacement.validate(); err != nil {
+			return err
+		}
+	}
+	for _, rateLimit := range c.RateLimits {
+		if err := rateLimit.init(); err != nil {
+			return err
+		}
+	}
+	if c.ResolverCommand != "" && c.GitSSH != nil && c.GitSSH.Enabled {
+		return errors.New("resolver_command and git_ssh can't be used together")
+	}
+	if c.GHECom != nil {
+		if err := c.GHECom.init(); err != nil {
+			return err
+		}
+		if c.ResolverCommand != "" || (c.GitSSH != nil && c.GitSSH.Enabled) {
+			return errors.New("ghe_com can't be used with resolver_command and git_ssh")
+		}
+	}
+	if c.AdvisoryChecksum != "" {
+		if c.AdvisoryURL == "" {
+			return errors.New("advisory_checksum requires advisory_url")
+		}
+		if err := validateChecksum(c.AdvisoryChecksum); err != nil {
+			return fmt.Errorf("parse advisory_checksum: %w", err)
+		}
+	}
+	if c.Checks != nil {
+		if err := c.Checks.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchIgnoreNotFound returns the index of ignore_not_found matching with the repository owner.
+// If no rule matches, it returns -1.
+func (c *Config) matchIgnoreNotFound(owner string) int {
+	for i, ignoreNotFound := range c.IgnoreNotFound {
+		if ignoreNotFound.owner.MatchString(owner) {
+			c.useRule(fmt.Sprintf("ignore_not_found[%d]", i))
+			return i
+		}
+	}
+	return -1
 }
 
-func getConfigPath(fs afero.Fs) (string, error) {
-	for _, path := range []string{".pinact.yaml", ".github/pinact.yaml", ".pinact.yml", ".github/pinact.yml"} {
+// logIgnored logs why a line is ignored.
+// The log is output at the info level if --why is set, otherwise at the debug level.
+func (c *Config) logIgnored(logE *logrus.Entry, msg string) {
+	if c.Why {
+		logE.Info(msg)
+		return
+	}
+	logE.Debug(msg)
+}
+
+// isSelected returns true if a line in the job and the step is a target.
+// If neither --job nor --step-name is set, all lines are targets.
+func (c *Config) isSelected(job, stepName string) bool {
+	if len(c.Jobs) != 0 && !slices.Contains(c.Jobs, job) {
+		return false
+	}
+	if c.StepName != nil && !c.StepName.MatchString(stepName) {
+		return false
+	}
+	return true
+}
+
+// isVerified returns true if the version annotation of the action is verified.
+// If --verify-only is set, only actions matching the regular expression are verified to reduce API calls.
+func (c *Config) isVerified(action *Action) bool {
+	if !c.IsVerify {
+		return false
+	}
+	return c.VerifyOnly == nil || c.VerifyOnly.MatchString(action.Name)
+}
+
+// configFilePaths are paths of configuration files searched in order.
+var configFilePaths = []string{".pinact.yaml", ".github/pinact.yaml", ".pinact.yml", ".github/pinact.yml"}
+
+func getConfigPath(fs afero.Fs, pwd string) (string, error) {
+	for _, path := range configFilePaths {
+		path = filepath.Join(pwd, path)
 		f, err := afero.Exists(fs, path)
 		if err != nil {
 			return "", fmt.Errorf("check if %s exists: %w", path, err)
@@ -34,10 +305,10 @@ func getConfigPath(fs afero.Fs) (string, error) {
 	return "", nil
 }
 
-func (c *Controller) readConfig(configFilePath string, cfg *Config) error {
+func (c *Controller) readConfig(logE *logrus.Entry, configFilePath, pwd string, cfg *Config) error {
 	var err error
 	if configFilePath == "" {
-		configFilePath, err = getConfigPath(c.fs)
+		configFilePath, err = getConfigPath(c.fs, pwd)
 		if err != nil {
 			return err
 		}
@@ -45,13 +316,68 @@ func (c *Controller) readConfig(configFilePath string, cfg *Config) error {
 			return nil
 		}
 	}
-	f, err := c.fs.Open(configFilePath)
+	if !filepath.IsAbs(configFilePath) {
+		configFilePath = filepath.Join(pwd, configFilePath)
+	}
+	b, err := afero.ReadFile(c.fs, configFilePath)
 	if err != nil {
-		return fmt.Errorf("open a configuration file: %w", err)
+		return fmt.Errorf("read a configuration file: %w", err)
 	}
-	defer f.Close()
-	if err := yaml.NewDecoder(f).Decode(cfg); err != nil {
+	return parseConfig(logE.WithField("config_file", configFilePath), b, cfg)
+}
+
+func parseConfig(logE *logrus.Entry, b []byte, cfg *Config) error {
+	if err := yaml.Unmarshal(b, cfg); err != nil {
 		return fmt.Errorf("decode a configuration file as YAML: %w", err)
 	}
+	return checkConfigSchema(logE, b, cfg)
+}
+
+// checkConfigSchema checks if the configuration is compatible with this version of pinact.
+// Unknown fields are errors by default because typos of field names would make rules ignored silently.
+// If schema_version_check is "warn", incompatibilities are logged as warnings and known fields still take effect.
+// This allows organizations to push a newer configuration to older pinact.
+func checkConfigSchema(logE *logrus.Entry, b []byte, cfg *Config) error {
+	switch cfg.SchemaVersionCheck {
+	case "", schemaVersionCheckError, schemaVersionCheckWarn:
+	default:
+		return fmt.Errorf("schema_version_check must be either %s or %s: %s", schemaVersionCheckError, schemaVersionCheckWarn, cfg.SchemaVersionCheck)
+	}
+	warn := cfg.SchemaVersionCheck == schemaVersionCheckWarn
+	if cfg.Version > configVersion {
+		if !warn {
+			return logerr.WithFields(errors.New("the configuration version isn't supported. Please update pinact"), logrus.Fields{ //nolint:wrapcheck
+				"config_version":           cfg.Version,
+				"supported_config_version": configVersion,
+			})
+		}
+		logE.WithFields(logrus.Fields{
+			"config_version":           cfg.Version,
+			"supported_config_version": configVersion,
+		}).Warn("the configuration version isn't supported. Known fields take effect")
+	}
+	fields, err := findUnknownFields(b)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	if !warn {
+		msgs := make([]string, len(fields))
+		for i, field := range fields {
+			msgs[i] = field.String()
+		}
+		return logerr.WithFields(errors.New("the configuration has unknown fields. If you want to ignore them, please set schema_version_check: warn"), logrus.Fields{ //nolint:wrapcheck
+			"unknown_fields": strings.Join(msgs, ", "),
+		})
+	}
+	for _, field := range fields {
+		logE.WithFields(logrus.Fields{
+			"line":       field.Line,
+			"field":      field.Name,
+			"suggestion": field.Suggestion,
+		}).Warn("the configuration has an unknown field. It's ignored")
+	}
 	return nil
 }
@@ -0,0 +1,124 @@
+package run
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (\S+) not found in type \S+\.(\S+)$`)
+
+type unknownField struct {
+	Line       string
+	Name       string
+	Suggestion string
+}
+
+func (f *unknownField) String() string {
+	if f.Suggestion == "" {
+		return fmt.Sprintf("line %s: unknown field %s", f.Line, f.Name)
+	}
+	return fmt.Sprintf("line %s: unknown field %s (did you mean %s?)", f.Line, f.Name, f.Suggestion)
+}
+
+// findUnknownFields decodes a configuration strictly and returns unknown fields.
+// Each unknown field has a suggestion of a known field if the name is similar, which helps users to find typos.
+func findUnknownFields(b []byte) ([]*unknownField, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	err := dec.Decode(&Config{})
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	typeErr := &yaml.TypeError{}
+	if !errors.As(err, &typeErr) {
+		return nil, fmt.Errorf("decode a configuration file as YAML: %w", err)
+	}
+	known := map[string][]string{}
+	collectKnownFields(reflect.TypeOf(Config{}), known)
+	fields := make([]*unknownField, 0, len(typeErr.Errors))
+	for _, e := range typeErr.Errors {
+		m := unknownFieldPattern.FindStringSubmatch(e)
+		if m == nil {
+			return nil, fmt.Errorf("decode a configuration file as YAML: %w", err)
+		}
+		fields = append(fields, &unknownField{
+			Line:       m[1],
+			Name:       m[2],
+			Suggestion: suggestField(m[2], known[m[3]]),
+		})
+	}
+	return fields, nil
+}
+
+// collectKnownFields collects YAML keys of struct types recursively.
+// The key of the map is the type name.
+func collectKnownFields(t reflect.Type, known map[string][]string) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	if _, ok := known[t.Name()]; ok {
+		return
+	}
+	keys := []string{}
+	known[t.Name()] = keys
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = strings.ToLower(field.Name)
+		}
+		keys = append(keys, key)
+		collectKnownFields(field.Type, known)
+	}
+	known[t.Name()] = keys
+}
+
+// suggestField returns the most similar known field.
+// If no field is similar enough, it returns an empty string.
+func suggestField(name string, candidates []string) string {
+	suggestion := ""
+	minDistance := 3 //nolint:mnd
+	for _, candidate := range candidates {
+		if d := levenshtein(name, candidate); d < minDistance {
+			minDistance = d
+			suggestion = candidate
+		}
+	}
+	return suggestion
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,226 @@
+package run
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spf13/afero"
+)
+
+func TestIgnoreAction_match(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		rule   string
+		action *Action
+		exp    bool
+	}{
+		{
+			name:   "exact",
+			rule:   "actions/checkout",
+			action: &Action{Name: "actions/checkout", Version: "v4"},
+			exp:    true,
+		},
+		{
+			name:   "action root doesn't match reusable workflows",
+			rule:   "suzuki-shunsuke/go-release-workflow",
+			action: &Action{Name: "suzuki-shunsuke/go-release-workflow/.github/workflows/release.yaml", Version: "v2.0.0"},
+		},
+		{
+			name:   "reusable workflows",
+			rule:   "suzuki-shunsuke/go-release-workflow/.github/workflows/*.yaml",
+			action: &Action{Name: "suzuki-shunsuke/go-release-workflow/.github/workflows/release.yaml", Version: "v2.0.0"},
+			exp:    true,
+		},
+		{
+			name:   "reusable workflows don't match the action root",
+			rule:   "suzuki-shunsuke/go-release-workflow/*",
+			action: &Action{Name: "suzuki-shunsuke/go-release-workflow", Version: "v2.0.0"},
+		},
+		{
+			name:   "* doesn't match /",
+			rule:   "suzuki-shunsuke/*",
+			action: &Action{Name: "suzuki-shunsuke/go-release-workflow/.github/workflows/release.yaml", Version: "v2.0.0"},
+		},
+		{
+			name:   "version",
+			rule:   "actions/checkout@v3*",
+			action: &Action{Name: "actions/checkout", Version: "v3.5.2"},
+			exp:    true,
+		},
+		{
+			name:   "version unmatch",
+			rule:   "actions/checkout@v3*",
+			action: &Action{Name: "actions/checkout", Version: "v4"},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			a := &IgnoreAction{Name: d.rule}
+			if err := a.init(); err != nil {
+				t.Fatal(err)
+			}
+			if got := a.match(d.action); got != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, got)
+			}
+		})
+	}
+}
+
+func TestConfig_Init(t *testing.T) {
+	t.Parallel()
+	cfg := &Config{
+		IgnoreActions: []*IgnoreAction{
+			{Name: "actions/[checkout"},
+		},
+	}
+	if err := cfg.Init(); err == nil {
+		t.Fatal("an invalid glob pattern must be rejected")
+	}
+}
+
+func TestConfig_isVerified(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		cfg    *Config
+		action string
+		exp    bool
+	}{
+		{
+			name:   "not verify",
+			cfg:    &Config{},
+			action: "actions/checkout",
+		},
+		{
+			name:   "verify",
+			cfg:    &Config{IsVerify: true},
+			action: "actions/checkout",
+			exp:    true,
+		},
+		{
+			name:   "verify only matched",
+			cfg:    &Config{IsVerify: true, VerifyOnly: regexp.MustCompile(`^suzuki-shunsuke/`)},
+			action: "suzuki-shunsuke/tfaction",
+			exp:    true,
+		},
+		{
+			name:   "verify only unmatched",
+			cfg:    &Config{IsVerify: true, VerifyOnly: regexp.MustCompile(`^suzuki-shunsuke/`)},
+			action: "actions/checkout",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if f := d.cfg.isVerified(&Action{Name: d.action}); f != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, f)
+			}
+		})
+	}
+}
+
+func TestController_getTargetAction_why(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		why  bool
+		exp  []logrus.Fields
+	}{
+		{
+			name: "the reason is logged at the info level",
+			why:  true,
+			exp: []logrus.Fields{
+				{
+					"action":    "actions/checkout",
+					"line":      "  - uses: actions/checkout@v4",
+					"rule":      "ignore_actions[0]",
+					"rule_name": "actions/checkout",
+				},
+			},
+		},
+		{
+			name: "the reason is logged at the debug level",
+			exp:  []logrus.Fields{},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			logger, hook := test.NewNullLogger()
+			cfg := &Config{
+				IgnoreActions: []*IgnoreAction{
+					{Name: "actions/checkout"},
+				},
+				Why: d.why,
+			}
+			if err := cfg.Init(); err != nil {
+				t.Fatal(err)
+			}
+			ctrl := NewController(nil, nil)
+			if action := ctrl.getTargetAction(logrus.NewEntry(logger), "  - uses: actions/checkout@v4", cfg); action != nil {
+				t.Fatalf("the action must be ignored: %s", action.Name)
+			}
+			fields := []logrus.Fields{}
+			for _, entry := range hook.AllEntries() {
+				if entry.Message != "ignore the action" || entry.Level != logrus.InfoLevel {
+					t.Fatalf("unexpected log: %s %s", entry.Level, entry.Message)
+				}
+				fields = append(fields, entry.Data)
+			}
+			if diff := cmp.Diff(d.exp, fields); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestController_readConfig(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name           string
+		configFilePath string
+		exp            bool
+	}{
+		{
+			name:           "relative path is relative to pwd",
+			configFilePath: "pinact.yaml",
+			exp:            true,
+		},
+		{
+			name: "search the configuration file in pwd",
+			exp:  true,
+		},
+		{
+			name:           "absolute path",
+			configFilePath: "/etc/pinact.yaml",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			if err := afero.WriteFile(fs, "/repo/pinact.yaml", []byte("skip_forks: true\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := afero.WriteFile(fs, "/repo/.pinact.yaml", []byte("skip_forks: true\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := afero.WriteFile(fs, "/etc/pinact.yaml", []byte("skip_forks: false\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			ctrl := NewController(nil, fs)
+			cfg := &Config{}
+			if err := ctrl.readConfig(logrus.NewEntry(logrus.New()), d.configFilePath, "/repo", cfg); err != nil {
+				t.Fatal(err)
+			}
+			if cfg.SkipForks != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, cfg.SkipForks)
+			}
+		})
+	}
+}
@@ -2,38 +2,100 @@ package run
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 
 	"github.com/spf13/afero"
 	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/offline"
+	"github.com/suzuki-shunsuke/pinact/pkg/osv"
+	"github.com/suzuki-shunsuke/pinact/pkg/registry"
+	"github.com/suzuki-shunsuke/pinact/pkg/trace"
 )
 
 type Controller struct {
-	repositoriesService RepositoriesService
-	fs                  afero.Fs
-	update              bool
+	repositoriesService  RepositoriesService
+	fs                   afero.Fs
+	update               bool
+	reporter             Reporter
+	vulnerabilityService VulnerabilityService
+	vulnerabilities      map[string]*queryVulnerabilitiesResult
+	cacheFilePath        string
+	pullRequestsService  PullRequestsService
+	checksService        ChecksService
+	gitService           GitService
+	registryService      RegistryService
+	digests              map[string]*getDigestResult
+	// host is the host resolving versions. It's appended to version annotations if provenance is enabled.
+	host string
+	// githubInput is the input of the GitHub client. It's used to create clients for other hosts such as GHE.com.
+	githubInput *github.InputNew
+	// version is the version of pinact. It's output to reports so that results can be attributed to the version.
+	version string
 }
 
 type InputNew struct {
-	Update bool
+	Update         bool
+	RecordFilePath string
+	ReplayFilePath string
+	GitHubAPIURL   string
+	GitHubToken    string
+	// Version is the version of pinact output to reports.
+	Version string
 }
 
-func New(ctx context.Context, input *InputNew) *Controller {
-	gh := github.New(ctx)
+func New(ctx context.Context, input *InputNew) (*Controller, error) {
+	githubInput := &github.InputNew{
+		RecordFilePath: input.RecordFilePath,
+		ReplayFilePath: input.ReplayFilePath,
+		APIURL:         input.GitHubAPIURL,
+		Token:          input.GitHubToken,
+	}
+	gh, err := github.New(ctx, githubInput)
+	if err != nil {
+		return nil, fmt.Errorf("create a GitHub client: %w", err)
+	}
 	return &Controller{
-		repositoriesService: &RepositoriesServiceImpl{
-			tags:                map[string]*ListTagsResult{},
-			releases:            map[string]*ListReleasesResult{},
-			commits:             map[string]*GetCommitSHA1Result{},
-			RepositoriesService: gh.Repositories,
-		},
-		fs:     afero.NewOsFs(),
-		update: input.Update,
+		repositoriesService:  newRepositoriesServiceImpl(NewGitHubRepositoriesService(gh)),
+		fs:                   afero.NewOsFs(),
+		update:               input.Update,
+		reporter:             &nopReporter{},
+		vulnerabilityService: osv.New(trace.HTTPClient(offline.HTTPClient(http.DefaultClient)), ""),
+		vulnerabilities:      map[string]*queryVulnerabilitiesResult{},
+		pullRequestsService:  gh.PullRequests,
+		checksService:        gh.Checks,
+		gitService:           gh.Git,
+		registryService:      registry.New(trace.HTTPClient(offline.HTTPClient(http.DefaultClient))),
+		digests:              map[string]*getDigestResult{},
+		host:                 getAPIHost(input.GitHubAPIURL),
+		githubInput:          githubInput,
+		version:              input.Version,
+	}, nil
+}
+
+func newRepositoriesServiceImpl(repoService RepositoriesService) *RepositoriesServiceImpl {
+	return &RepositoriesServiceImpl{
+		tags:                map[string]*ListTagsResult{},
+		releases:            map[string]*ListReleasesResult{},
+		commits:             map[string]*GetCommitSHA1Result{},
+		repos:               map[string]*GetRepositoryResult{},
+		matchingTags:        map[string]*ListMatchingTagsResult{},
+		contents:            map[string]*GetFileContentResult{},
+		commitDates:         map[string]*GetCommitDateResult{},
+		immutableReleases:   map[string]*IsImmutableReleaseResult{},
+		attestations:        map[string]*ListAttestationsResult{},
+		RepositoriesService: repoService,
 	}
 }
 
 func NewController(repoService RepositoriesService, fs afero.Fs) *Controller {
 	return &Controller{
-		repositoriesService: repoService,
-		fs:                  fs,
+		repositoriesService:  repoService,
+		fs:                   fs,
+		reporter:             &nopReporter{},
+		vulnerabilityService: osv.New(offline.HTTPClient(http.DefaultClient), ""),
+		vulnerabilities:      map[string]*queryVulnerabilitiesResult{},
+		registryService:      registry.New(offline.HTTPClient(http.DefaultClient)),
+		digests:              map[string]*getDigestResult{},
 	}
 }
@@ -0,0 +1,69 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+type Cooldown struct {
+	Name string `json:"name" jsonschema:"description=A regular expression of repositories of actions. It's matched with owner/repo. e.g. ^actions/"`
+	Days int    `json:"days" jsonschema:"description=The number of days after versions are released before --update adopts them. If this is 0 versions are adopted immediately"`
+	name *regexp.Regexp
+}
+
+func (cd *Cooldown) init() error {
+	p, err := regexp.Compile(cd.Name)
+	if err != nil {
+		return fmt.Errorf("parse cooldowns[].name as a regular expression: %w", err)
+	}
+	cd.name = p
+	if cd.Days < 0 {
+		return errors.New("cooldowns[].days must not be negative")
+	}
+	return nil
+}
+
+// getCooldown returns the cooldown of the repository.
+// The first rule of cooldowns matching owner/repo is used, so specific rules should be put before general ones.
+// If no rule matches, it returns 0.
+func (c *Config) getCooldown(owner, repo string) time.Duration {
+	name := owner + "/" + repo
+	for i, cd := range c.Cooldowns {
+		if !cd.name.MatchString(name) {
+			continue
+		}
+		c.useRule(fmt.Sprintf("cooldowns[%d]", i))
+		return time.Duration(cd.Days) * 24 * time.Hour //nolint:mnd
+	}
+	return 0
+}
+
+// isCoolingDown returns true if the version released at releasedAt is still in the cooldown.
+// If the release date is unknown, the version isn't regarded as cooling down.
+func (c *Config) isCoolingDown(cooldown time.Duration, releasedAt time.Time) bool {
+	if cooldown == 0 || releasedAt.IsZero() {
+		return false
+	}
+	return c.Now.Sub(releasedAt) < cooldown
+}
+
+// isTagCoolingDown returns true if the commit of the tag is still in the cooldown.
+// Tags don't have release dates, so the commit date is used instead.
+func (c *Controller) isTagCoolingDown(ctx context.Context, logE *logrus.Entry, cfg *Config, owner, repo string, tag *github.RepositoryTag, cooldown time.Duration) bool {
+	if cooldown == 0 {
+		return false
+	}
+	date, _, err := c.repositoriesService.GetCommitDate(ctx, owner, repo, tag.GetCommit().GetSHA())
+	if err != nil {
+		logerr.WithError(logE, err).WithField("tag", tag.GetName()).Debug("get the commit date of a tag")
+		return false
+	}
+	return cfg.isCoolingDown(cooldown, date)
+}
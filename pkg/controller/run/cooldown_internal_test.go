@@ -0,0 +1,81 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+func TestController_getLatestVersion_cooldown(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	releases := []*github.RepositoryRelease{
+		{TagName: util.StrP("v4.2.2"), PublishedAt: &github.Timestamp{Time: now.Add(-3 * 24 * time.Hour)}},
+		{TagName: util.StrP("v4.2.1"), PublishedAt: &github.Timestamp{Time: now.Add(-30 * 24 * time.Hour)}},
+	}
+	data := []struct {
+		name      string
+		owner     string
+		cooldowns []*Cooldown
+		exp       string
+		skipped   int
+	}{
+		{
+			name:  "no cooldown",
+			owner: "suzuki-shunsuke",
+			exp:   "v4.2.2",
+		},
+		{
+			name:  "cooling down",
+			owner: "suzuki-shunsuke",
+			cooldowns: []*Cooldown{
+				{Name: "^actions/", Days: 0},
+				{Name: ".*", Days: 14},
+			},
+			exp:     "v4.2.1",
+			skipped: 1,
+		},
+		{
+			name:  "first party",
+			owner: "actions",
+			cooldowns: []*Cooldown{
+				{Name: "^actions/", Days: 0},
+				{Name: ".*", Days: 14},
+			},
+			exp: "v4.2.2",
+		},
+	}
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				releases: map[string]*ListReleasesResult{
+					d.owner + "/checkout/0": {
+						Releases: releases,
+						Response: &github.Response{},
+					},
+				},
+			}, afero.NewMemMapFs())
+			cfg := &Config{Cooldowns: d.cooldowns, Now: now}
+			if err := cfg.Init(); err != nil {
+				t.Fatal(err)
+			}
+			v, err := ctrl.getLatestVersion(context.Background(), logE, cfg, d.owner, "checkout")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, v)
+			}
+			if n := cfg.skippedUpdates[skipReasonCooldown]; n != d.skipped {
+				t.Fatalf("wanted %d updates skipped by the cooldown, got %d", d.skipped, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed cron expression "minute hour day-of-month month day-of-week".
+type cronSchedule struct {
+	minutes     []bool
+	hours       []bool
+	daysOfMonth []bool
+	months      []bool
+	daysOfWeek  []bool
+	// If both day-of-month and day-of-week are restricted, a time matches if either field matches like cron.
+	domStar bool
+	dowStar bool
+}
+
+type cronField struct {
+	min int
+	max int
+}
+
+var cronFields = []cronField{ //nolint:gochecknoglobals
+	{0, 59}, //nolint:mnd // minute
+	{0, 23}, //nolint:mnd // hour
+	{1, 31}, //nolint:mnd // day of month
+	{1, 12}, //nolint:mnd // month
+	{0, 7},  //nolint:mnd // day of week. Both 0 and 7 are Sunday
+}
+
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("a cron expression must have %d fields: %s", len(cronFields), expr)
+	}
+	values := make([][]bool, len(fields))
+	for i, field := range fields {
+		v, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("parse a cron field %s: %w", field, err)
+		}
+		values[i] = v
+	}
+	// Sunday is either 0 or 7.
+	values[4][0] = values[4][0] || values[4][7]
+	return &cronSchedule{
+		minutes:     values[0],
+		hours:       values[1],
+		daysOfMonth: values[2],
+		months:      values[3],
+		daysOfWeek:  values[4],
+		domStar:     fields[2] == "*",
+		dowStar:     fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, f cronField) ([]bool, error) {
+	values := make([]bool, f.max+1)
+	for _, part := range strings.Split(field, ",") {
+		rng, stepS, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			s, err := strconv.Atoi(stepS)
+			if err != nil || s <= 0 {
+				return nil, fmt.Errorf("invalid step: %s", stepS)
+			}
+			step = s
+		}
+		start, end := f.min, f.max
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			s, err := strconv.Atoi(a)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value: %s", a)
+			}
+			start = s
+			end = s
+			if isRange {
+				e, err := strconv.Atoi(b)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value: %s", b)
+				}
+				end = e
+			} else if hasStep {
+				end = f.max
+			}
+		}
+		if start < f.min || end > f.max || start > end {
+			return nil, errors.New("out of range")
+		}
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+func (s *cronSchedule) match(t time.Time) bool {
+	if !s.minutes[t.Minute()] || !s.hours[t.Hour()] || !s.months[int(t.Month())] {
+		return false
+	}
+	dom := s.daysOfMonth[t.Day()]
+	dow := s.daysOfWeek[int(t.Weekday())]
+	if !s.domStar && !s.dowStar {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// activeSince returns true if the schedule matched at least once in the period (now - duration, now].
+func (s *cronSchedule) activeSince(now time.Time, duration time.Duration) bool {
+	now = now.Truncate(time.Minute)
+	for t := now; now.Sub(t) < duration; t = t.Add(-time.Minute) {
+		if s.match(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package run
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_cronSchedule_activeSince(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name     string
+		cron     string
+		duration time.Duration
+		now      string
+		exp      bool
+		isErr    bool
+	}{
+		{
+			name:     "weekend",
+			cron:     "0 0 * * 5-7",
+			duration: 24 * time.Hour,
+			now:      "2024-06-01T12:00:00Z", // Saturday
+			exp:      true,
+		},
+		{
+			name:     "weekday",
+			cron:     "0 0 * * 5-7",
+			duration: 24 * time.Hour,
+			now:      "2024-06-04T12:00:00Z", // Tuesday
+		},
+		{
+			name:     "monday morning is out of the window started at sunday",
+			cron:     "0 0 * * 0",
+			duration: 24 * time.Hour,
+			now:      "2024-06-03T00:00:00Z", // Monday
+		},
+		{
+			name:     "release week",
+			cron:     "0 9 1 6 *",
+			duration: 7 * 24 * time.Hour,
+			now:      "2024-06-05T00:00:00Z",
+			exp:      true,
+		},
+		{
+			name:     "list and step",
+			cron:     "*/30 1,2 * * *",
+			duration: time.Minute,
+			now:      "2024-06-05T02:30:00Z",
+			exp:      true,
+		},
+		{
+			name:  "invalid field count",
+			cron:  "0 0 * *",
+			isErr: true,
+		},
+		{
+			name:  "out of range",
+			cron:  "0 24 * * *",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := parseCron(d.cron)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			now, err := time.Parse(time.RFC3339, d.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f := s.activeSince(now, d.duration); f != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, f)
+			}
+		})
+	}
+}
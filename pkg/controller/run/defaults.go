@@ -0,0 +1,118 @@
+package run
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+var (
+	localActionPattern = regexp.MustCompile(`^[ \t]*(?:-[ \t]+)?['"]?uses['"]?[ \t]*:[ \t]+['"]?(\./[^ \t'"#]*)`)
+	dockerImagePattern = regexp.MustCompile(`^[ \t]*(?:-[ \t]+)?['"]?uses['"]?[ \t]*:[ \t]+['"]?docker://([^ \t'"#]+)`)
+)
+
+// Defaults configures built-in rules about default target files, local actions, and Docker images.
+type Defaults struct {
+	IgnoreLocal    *bool `json:"ignore_local,omitempty" yaml:"ignore_local" jsonschema:"description=Ignore local actions such as ./.github/actions/foo. If this is false local actions used in target files are also processed. The default value is true"`
+	IgnoreDocker   *bool `json:"ignore_docker,omitempty" yaml:"ignore_docker" jsonschema:"description=Ignore Docker images such as docker://alpine:3.8. If this is false pinact warns if Docker images aren't pinned by digests. The default value is true"`
+	PinDockerfile  *bool `json:"pin_dockerfile,omitempty" yaml:"pin_dockerfile" jsonschema:"description=Pin base images of Dockerfiles of local Docker actions by digests. This requires ignore_local to be false. The default value is false"`
+	IncludeActions *bool `json:"include_actions,omitempty" yaml:"include_actions" jsonschema:"description=Include action files of actions under .github/actions in default target files. This is used if neither arguments nor files are set. The default value is true"`
+}
+
+func (c *Config) ignoreLocal() bool {
+	if c.Defaults == nil || c.Defaults.IgnoreLocal == nil {
+		return true
+	}
+	return *c.Defaults.IgnoreLocal
+}
+
+func (c *Config) includeActions() bool {
+	if c.Defaults == nil || c.Defaults.IncludeActions == nil {
+		return true
+	}
+	return *c.Defaults.IncludeActions
+}
+
+func (c *Config) ignoreDocker() bool {
+	if c.Defaults == nil || c.Defaults.IgnoreDocker == nil {
+		return true
+	}
+	return *c.Defaults.IgnoreDocker
+}
+
+// isDockerAction returns true if the action is a Docker image.
+// Docker images can't be resolved by GitHub API, so they're always skipped when pinning actions.
+func isDockerAction(name string) bool {
+	return strings.HasPrefix(name, "docker://")
+}
+
+// addLocalActions adds action files of local actions used in target files.
+// GitHub Actions resolves paths of local actions from the repository root, so they're relative to pwd.
+// If pin_dockerfile is true, Dockerfiles of local Docker actions are also added.
+func (c *Controller) addLocalActions(logE *logrus.Entry, workflowFilePaths []string, pwd string, cfg *Config) []string {
+	files := workflowFilePaths
+	for _, workflowFilePath := range workflowFilePaths {
+		p := workflowFilePath
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(pwd, p)
+		}
+		lines, err := c.readWorkflow(p)
+		if err != nil {
+			continue
+		}
+		for _, line := range lines {
+			m := localActionPattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			for _, name := range []string{"action.yaml", "action.yml"} {
+				file := filepath.Join(m[1], name)
+				if f, err := afero.Exists(c.fs, filepath.Join(pwd, file)); err != nil || !f {
+					continue
+				}
+				logE.WithFields(logrus.Fields{
+					"workflow_file": workflowFilePath,
+					"local_action":  file,
+				}).Debug("add a local action to target files")
+				if !slices.Contains(files, file) {
+					// The action file may be already a target file by default
+					files = append(files, file)
+				}
+				if !cfg.pinDockerfile() {
+					break
+				}
+				if dockerfile := c.getActionDockerfile(file, pwd); dockerfile != "" {
+					logE.WithFields(logrus.Fields{
+						"local_action": file,
+						"dockerfile":   dockerfile,
+					}).Debug("add a Dockerfile of a local action to target files")
+					if !slices.Contains(files, dockerfile) {
+						files = append(files, dockerfile)
+					}
+				}
+				break
+			}
+		}
+	}
+	return files
+}
+
+// checkDockerImages warns if Docker images aren't pinned by digests.
+// pinact can't pin them because they aren't hosted on GitHub.
+func checkDockerImages(logE *logrus.Entry, lines []string) {
+	for i, line := range lines {
+		m := dockerImagePattern.FindStringSubmatch(line)
+		if m == nil || strings.Contains(m[1], "@sha256:") {
+			continue
+		}
+		logE.WithFields(logrus.Fields{
+			"image":       m[1],
+			"line_number": i + 1,
+			"help_docs":   "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/007.md",
+		}).Warn("the Docker image isn't pinned by a digest")
+	}
+}
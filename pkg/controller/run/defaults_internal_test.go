@@ -0,0 +1,54 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_addLocalActions(t *testing.T) {
+	t.Parallel()
+	pwd := t.TempDir()
+	files := map[string]string{
+		".github/workflows/test.yaml": `jobs:
+  test:
+    steps:
+      - uses: ./.github/actions/foo
+      - uses: "./.github/actions/bar"
+      - uses: ./.github/actions/not-found
+      - uses: actions/checkout@v4
+`,
+		".github/actions/foo/action.yaml": "",
+		".github/actions/bar/action.yml":  "",
+	}
+	for name, content := range files {
+		p := filepath.Join(pwd, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctrl := NewController(nil, afero.NewOsFs())
+	got := ctrl.addLocalActions(logrus.NewEntry(logrus.New()), []string{".github/workflows/test.yaml"}, pwd, &Config{})
+	exp := []string{".github/workflows/test.yaml", ".github/actions/foo/action.yaml", ".github/actions/bar/action.yml"}
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestConfig_ignoreLocal(t *testing.T) {
+	t.Parallel()
+	f := false
+	if !(&Config{}).ignoreLocal() {
+		t.Fatal("local actions must be ignored by default")
+	}
+	if (&Config{Defaults: &Defaults{IgnoreLocal: &f}}).ignoreLocal() {
+		t.Fatal("local actions must not be ignored")
+	}
+}
@@ -0,0 +1,153 @@
+package run
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+var (
+	actionImagePattern     = regexp.MustCompile(`^ +['"]?image['"]? *: +['"]?([^ '"#]+)`)
+	dockerfileFromPattern  = regexp.MustCompile(`(?i)^(\s*FROM\s+(?:--platform=\S+\s+)?)(\S+)(.*)$`)
+	dockerfileStagePattern = regexp.MustCompile(`(?i)^\s+AS\s+(\S+)`)
+)
+
+type RegistryService interface {
+	GetDigest(ctx context.Context, image string) (string, error)
+}
+
+type getDigestResult struct {
+	digest string
+	err    error
+}
+
+// getDigest returns the digest of the image.
+// Results are cached because the same base image is often used in many Dockerfiles.
+func (c *Controller) getDigest(ctx context.Context, image string) (string, error) {
+	if a, ok := c.digests[image]; ok {
+		return a.digest, a.err
+	}
+	digest, err := c.registryService.GetDigest(ctx, image)
+	c.digests[image] = &getDigestResult{
+		digest: digest,
+		err:    err,
+	}
+	return digest, err //nolint:wrapcheck
+}
+
+func (c *Config) pinDockerfile() bool {
+	return c.Defaults != nil && c.Defaults.PinDockerfile != nil && *c.Defaults.PinDockerfile
+}
+
+// isDockerfile returns true if the file is a Dockerfile such as Dockerfile, Dockerfile.dev, and dev.Dockerfile.
+// YAML files are never Dockerfiles even if they're named like Dockerfile.yaml.
+func isDockerfile(p string) bool {
+	base := filepath.Base(p)
+	if ext := filepath.Ext(base); ext == ".yaml" || ext == ".yml" {
+		return false
+	}
+	return base == "Dockerfile" || strings.HasPrefix(base, "Dockerfile.") || strings.HasSuffix(strings.ToLower(base), ".dockerfile")
+}
+
+// getActionDockerfile returns the Dockerfile of a local Docker action.
+// The path of the Dockerfile is relative to the action file.
+// If the action isn't built from a Dockerfile, it returns an empty string.
+func (c *Controller) getActionDockerfile(actionFile, pwd string) string {
+	lines, err := c.readWorkflow(filepath.Join(pwd, actionFile))
+	if err != nil {
+		return ""
+	}
+	for _, line := range lines {
+		m := actionImagePattern.FindStringSubmatch(line)
+		if m == nil || isDockerAction(m[1]) {
+			continue
+		}
+		file := filepath.Join(filepath.Dir(actionFile), m[1])
+		if f, err := afero.Exists(c.fs, filepath.Join(pwd, file)); err != nil || !f {
+			return ""
+		}
+		return file
+	}
+	return ""
+}
+
+// pinImage returns the image reference pinned by the digest, e.g. alpine:3.21 => alpine:3.21@sha256:xxx.
+// Images pinned by digests are updated only if --update is set.
+// If the reference doesn't need to be changed, it returns an empty string.
+func (c *Controller) pinImage(ctx context.Context, logE *logrus.Entry, ref string) string {
+	image, digest, pinned := strings.Cut(ref, "@")
+	if strings.Contains(image, "$") {
+		return ""
+	}
+	if pinned && (!c.update || !strings.Contains(image[strings.LastIndex(image, "/")+1:], ":")) {
+		// Images pinned without tags can't be updated.
+		return ""
+	}
+	logE = logE.WithField("image", image)
+	newDigest, err := c.getDigest(ctx, image)
+	if err != nil {
+		logerr.WithError(logE, err).Warn("get the digest of a Docker image")
+		return ""
+	}
+	if newDigest == digest {
+		return ""
+	}
+	return image + "@" + newDigest
+}
+
+// runDockerfile pins base images of a Dockerfile by digests.
+// Tags are kept for readability, e.g. FROM alpine:3.21 => FROM alpine:3.21@sha256:xxx.
+// Images pinned by digests are updated only if --update is set.
+// Stages, scratch, and images including build arguments are skipped.
+func (c *Controller) runDockerfile(ctx context.Context, logE *logrus.Entry, dockerfilePath string, stats *Stats) (*workflowChange, error) {
+	lines, err := c.readWorkflow(dockerfilePath)
+	if err != nil {
+		return nil, err
+	}
+	c.reporter.OnFileStart(dockerfilePath)
+	stages := map[string]struct{}{}
+	changed := false
+	for i, line := range lines {
+		m := dockerfileFromPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		image, _, _ := strings.Cut(m[2], "@")
+		_, isStage := stages[strings.ToLower(image)]
+		if s := dockerfileStagePattern.FindStringSubmatch(m[3]); s != nil {
+			stages[strings.ToLower(s[1])] = struct{}{}
+		}
+		if isStage || strings.EqualFold(image, "scratch") {
+			continue
+		}
+		ref := c.pinImage(ctx, logE.WithField("line_number", i+1), m[2])
+		if ref == "" {
+			continue
+		}
+		l := m[1] + ref + m[3]
+		changed = true
+		c.reporter.OnFinding(&Finding{
+			Kind:   FindingKindChanged,
+			File:   dockerfilePath,
+			Line:   i + 1,
+			Before: line,
+			After:  l,
+		})
+		stats.ChangedLines++
+		lines[i] = l
+	}
+	c.reporter.OnFileEnd(dockerfilePath, changed)
+	if !changed {
+		return nil, nil //nolint:nilnil
+	}
+	stats.ChangedFiles++
+	return &workflowChange{
+		Path:  dockerfilePath,
+		Lines: lines,
+	}, nil
+}
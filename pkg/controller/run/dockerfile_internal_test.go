@@ -0,0 +1,115 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+type testRegistryService struct {
+	digests map[string]string
+}
+
+func (s *testRegistryService) GetDigest(_ context.Context, image string) (string, error) {
+	if digest, ok := s.digests[image]; ok {
+		return digest, nil
+	}
+	return "", errors.New("not found")
+}
+
+func Test_isDockerfile(t *testing.T) {
+	t.Parallel()
+	data := map[string]bool{
+		".github/actions/foo/Dockerfile":     true,
+		".github/actions/foo/Dockerfile.dev": true,
+		".github/actions/foo/dev.Dockerfile": true,
+		".github/actions/foo/action.yaml":    false,
+		".github/workflows/Dockerfile.yaml":  false,
+		".github/workflows/test.yaml":        false,
+	}
+	for p, exp := range data {
+		if got := isDockerfile(p); got != exp {
+			t.Fatalf("%s: wanted %v, got %v", p, exp, got)
+		}
+	}
+}
+
+func TestController_runDockerfile(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name    string
+		update  bool
+		content string
+		exp     []string
+	}{
+		{
+			name: "pin",
+			content: `FROM golang:1.24 AS builder
+FROM builder AS test
+FROM --platform=linux/amd64 alpine:3.21
+FROM scratch
+FROM ${BASE_IMAGE}
+FROM ghcr.io/foo/not-found:v1
+`,
+			exp: []string{
+				"FROM golang:1.24@sha256:1111111111111111111111111111111111111111111111111111111111111111 AS builder",
+				"FROM builder AS test",
+				"FROM --platform=linux/amd64 alpine:3.21@sha256:2222222222222222222222222222222222222222222222222222222222222222",
+				"FROM scratch",
+				"FROM ${BASE_IMAGE}",
+				"FROM ghcr.io/foo/not-found:v1",
+			},
+		},
+		{
+			name:    "pinned",
+			content: "FROM alpine:3.21@sha256:0000000000000000000000000000000000000000000000000000000000000000\n",
+		},
+		{
+			name:    "update",
+			update:  true,
+			content: "FROM alpine:3.21@sha256:0000000000000000000000000000000000000000000000000000000000000000\n",
+			exp: []string{
+				"FROM alpine:3.21@sha256:2222222222222222222222222222222222222222222222222222222222222222",
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			dockerfilePath := filepath.Join(t.TempDir(), "Dockerfile")
+			if err := os.WriteFile(dockerfilePath, []byte(d.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			ctrl := NewController(nil, afero.NewOsFs())
+			ctrl.update = d.update
+			ctrl.registryService = &testRegistryService{
+				digests: map[string]string{
+					"golang:1.24": "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+					"alpine:3.21": "sha256:2222222222222222222222222222222222222222222222222222222222222222",
+				},
+			}
+			change, err := ctrl.runDockerfile(context.Background(), logrus.NewEntry(logrus.New()), dockerfilePath, &Stats{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.exp == nil {
+				if change != nil {
+					t.Fatalf("the file must not be changed: %v", change.Lines)
+				}
+				return
+			}
+			if change == nil {
+				t.Fatal("the file must be changed")
+			}
+			if diff := cmp.Diff(d.exp, change.Lines); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
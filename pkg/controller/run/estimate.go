@@ -0,0 +1,131 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+// apiCallEstimate is the approximate number of API calls needed by a run per host.
+// Pagination isn't taken into account, so the actual number can be larger.
+type apiCallEstimate struct {
+	Host    string
+	Files   int
+	Actions int
+	// refs are references resolved to commit hashes. e.g. actions/checkout/v4
+	refs map[string]struct{}
+	// tagRepos are repositories whose tags are listed to get versions from commit hashes.
+	tagRepos map[string]struct{}
+	// releaseRepos are repositories whose releases are listed to get latest versions.
+	releaseRepos map[string]struct{}
+}
+
+func newAPICallEstimate(host string) *apiCallEstimate {
+	return &apiCallEstimate{
+		Host:         host,
+		refs:         map[string]struct{}{},
+		tagRepos:     map[string]struct{}{},
+		releaseRepos: map[string]struct{}{},
+	}
+}
+
+// Calls returns the approximate number of API calls.
+func (e *apiCallEstimate) Calls() int {
+	return len(e.refs) + len(e.tagRepos) + len(e.releaseRepos)
+}
+
+// add adds API calls needed to process the action.
+// Actions are deduplicated, so the same action used in many files is counted once.
+func (e *apiCallEstimate) add(action *Action, cfg *Config, update bool, r *RepositoriesServiceImpl) {
+	repo := action.RepoOwner + "/" + action.RepoName
+	isSHA := getVersionType(action.Version) == FullCommitSHA
+	if !isSHA || (cfg.isVerified(action) && action.Tag != "") {
+		ref := action.Version
+		if isSHA {
+			ref = action.Tag
+		}
+		e.addCall(e.refs, repo+"/"+ref, r.isCommitCached(repo+"/"+ref))
+	}
+	if (isSHA && action.Tag == "") || getVersionType(action.Version) == Shortsemver || getVersionType(action.Tag) == Shortsemver || update {
+		e.addCall(e.tagRepos, repo, r.isTagCached(repo))
+	}
+	if update {
+		e.addCall(e.releaseRepos, repo, r.isReleaseCached(repo))
+	}
+}
+
+func (e *apiCallEstimate) addCall(calls map[string]struct{}, key string, cached bool) {
+	if cached {
+		return
+	}
+	calls[key] = struct{}{}
+}
+
+func (r *RepositoriesServiceImpl) isCommitCached(key string) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.commits[key]
+	return ok
+}
+
+func (r *RepositoriesServiceImpl) isTagCached(repo string) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.tags[repo+"/0"]
+	return ok
+}
+
+func (r *RepositoriesServiceImpl) isReleaseCached(repo string) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.releases[repo+"/0"]
+	return ok
+}
+
+// estimateAPICalls parses target files and outputs the approximate number of API calls needed by the run without calling API.
+// Responses cached by --cache-file aren't counted.
+func (c *Controller) estimateAPICalls(logE *logrus.Entry, workflowFilePaths []string, cfg *Config, pwd string, stdout io.Writer) error {
+	r, _ := c.repositoriesService.(*RepositoriesServiceImpl)
+	estimate := newAPICallEstimate(c.getHost())
+	actions := map[string]struct{}{}
+	for _, workflowFilePath := range workflowFilePaths {
+		logE := logE.WithField("workflow_file", workflowFilePath)
+		p := workflowFilePath
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(pwd, p)
+		}
+		lines, err := c.readWorkflow(p)
+		if err != nil {
+			logerr.WithError(logE, err).Warn("read a workflow")
+			continue
+		}
+		estimate.Files++
+		for _, line := range lines {
+			action := c.getTargetAction(logE, line, cfg)
+			if action == nil {
+				continue
+			}
+			actions[action.Name+"@"+action.Version] = struct{}{}
+			estimate.add(action, cfg, c.update, r)
+		}
+	}
+	estimate.Actions = len(actions)
+	return outputAPICallEstimate(stdout, estimate)
+}
+
+func outputAPICallEstimate(stdout io.Writer, estimate *apiCallEstimate) error {
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0) //nolint:mnd
+	fmt.Fprintln(w, "HOST\tFILES\tACTIONS\tAPI CALLS")
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", estimate.Host, estimate.Files, estimate.Actions, estimate.Calls())
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("output the estimate of API calls: %w", err)
+	}
+	return nil
+}
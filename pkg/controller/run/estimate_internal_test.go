@@ -0,0 +1,71 @@
+package run
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_estimateAPICalls(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		update bool
+		exp    string
+	}{
+		{
+			name: "pin",
+			// actions/checkout@v4 is resolved and tags are listed to get the full version
+			// actions/setup-go@v5 is cached, but tags are listed
+			// tags of actions/cache are listed to add the annotation
+			exp: "github.com  2      3        4",
+		},
+		{
+			name:   "update",
+			update: true,
+			// tags and releases of three repositories in addition to actions/checkout@v4
+			exp: "github.com  2      3        7",
+		},
+	}
+	workflow := `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+      - uses: actions/cache@1bd1e32a3bdc45362d1e726936510720a7c30a57
+`
+	dir := t.TempDir()
+	for _, name := range []string{"a.yaml", "b.yaml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(workflow), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			repoService := newRepositoriesServiceImpl(nil)
+			repoService.commits["actions/setup-go/v5"] = &GetCommitSHA1Result{
+				SHA: "d35c59abb061a4a6fb18e82ac0862c26744d6ab5",
+			}
+			ctrl := NewController(repoService, afero.NewOsFs())
+			ctrl.update = d.update
+			buf := &bytes.Buffer{}
+			if err := ctrl.estimateAPICalls(logE, []string{"a.yaml", "b.yaml"}, &Config{}, dir, buf); err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("wanted 2 lines, got %q", buf.String())
+			}
+			if got := strings.TrimSpace(lines[1]); got != d.exp {
+				t.Fatalf("wanted %q, got %q", d.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+)
+
+// File types decide how target files are parsed and patched.
+const (
+	FileTypeGitHubWorkflow = "github-workflow"
+	FileTypeGitHubAction   = "github-action"
+	FileTypeGitLabCI       = "gitlab-ci"
+	FileTypeDockerfile     = "dockerfile"
+	FileTypeDevcontainer   = "devcontainer"
+)
+
+var (
+	// gitlabCIImagePattern matches images of GitLab CI jobs and services.
+	// e.g. "image: alpine:3.21" and "  - image: postgres:17". The mapping form "image: {name: alpine:3.21}" isn't supported.
+	gitlabCIImagePattern = regexp.MustCompile(`^([ \t]*(?:-[ \t]+)?['"]?image['"]?[ \t]*:[ \t]+['"]?)([^ \t'"#{]+)(.*)$`)
+	// devcontainerImagePattern matches the image of devcontainer.json.
+	// e.g. `"image": "mcr.microsoft.com/devcontainers/go:1",`
+	devcontainerImagePattern = regexp.MustCompile(`^([ \t]*"image"[ \t]*:[ \t]*")([^"]+)(".*)$`)
+)
+
+func validateFileType(t string) error {
+	switch t {
+	case "", FileTypeGitHubWorkflow, FileTypeGitHubAction, FileTypeGitLabCI, FileTypeDockerfile, FileTypeDevcontainer:
+		return nil
+	}
+	return fmt.Errorf("files[].type must be one of %s, %s, %s, %s, and %s: %s",
+		FileTypeGitHubWorkflow, FileTypeGitHubAction, FileTypeGitLabCI, FileTypeDockerfile, FileTypeDevcontainer, t)
+}
+
+// detectFileType returns the file type by the file name.
+// GitLab CI and devcontainer files are processed only if the type is set explicitly by files[].type.
+func detectFileType(p string) string {
+	if isDockerfile(p) {
+		return FileTypeDockerfile
+	}
+	switch filepath.Base(p) {
+	case "action.yaml", "action.yml":
+		return FileTypeGitHubAction
+	}
+	return FileTypeGitHubWorkflow
+}
+
+// getFileType returns the file type.
+// The type of the first files[] whose pattern matches the file is used.
+// If no type is set, the type is detected by the file name.
+func (c *Config) getFileType(p string) string {
+	p = filepath.ToSlash(p)
+	for _, file := range c.Files {
+		if file.Type == "" || file.pattern == nil {
+			continue
+		}
+		if file.pattern.MatchString(p) {
+			return file.Type
+		}
+	}
+	return detectFileType(p)
+}
+
+// runImageFile pins images of files such as .gitlab-ci.yml and devcontainer.json by digests.
+// Each line matching the pattern is patched. The pattern must capture the prefix, the image, and the suffix.
+func (c *Controller) runImageFile(ctx context.Context, logE *logrus.Entry, filePath string, pattern *regexp.Regexp, stats *Stats) (*workflowChange, error) {
+	lines, err := c.readWorkflow(filePath)
+	if err != nil {
+		return nil, err
+	}
+	c.reporter.OnFileStart(filePath)
+	changed := false
+	for i, line := range lines {
+		m := pattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		ref := c.pinImage(ctx, logE.WithField("line_number", i+1), m[2])
+		if ref == "" {
+			continue
+		}
+		l := m[1] + ref + m[3]
+		changed = true
+		c.reporter.OnFinding(&Finding{
+			Kind:   FindingKindChanged,
+			File:   filePath,
+			Line:   i + 1,
+			Before: line,
+			After:  l,
+		})
+		stats.ChangedLines++
+		lines[i] = l
+	}
+	c.reporter.OnFileEnd(filePath, changed)
+	if !changed {
+		return nil, nil //nolint:nilnil
+	}
+	stats.ChangedFiles++
+	return &workflowChange{
+		Path:  filePath,
+		Lines: lines,
+	}, nil
+}
@@ -0,0 +1,143 @@
+package run
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestConfig_getFileType(t *testing.T) {
+	t.Parallel()
+	cfg := &Config{
+		Files: []*File{
+			{Pattern: `^\.github/workflows/.*\.ya?ml$`},
+			{Pattern: `^\.gitlab-ci\.yml$`, Type: FileTypeGitLabCI},
+			{Pattern: `devcontainer\.json$`, Type: FileTypeDevcontainer},
+		},
+	}
+	if err := cfg.Init(); err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]string{
+		".github/workflows/test.yaml":      FileTypeGitHubWorkflow,
+		".github/actions/foo/action.yaml":  FileTypeGitHubAction,
+		".github/actions/foo/Dockerfile":   FileTypeDockerfile,
+		".gitlab-ci.yml":                   FileTypeGitLabCI,
+		".devcontainer/devcontainer.json":  FileTypeDevcontainer,
+		"templates/.gitlab-ci.yml":         FileTypeGitHubWorkflow,
+		".github/workflows/Dockerfile.yml": FileTypeGitHubWorkflow,
+	}
+	for p, exp := range data {
+		if got := cfg.getFileType(p); got != exp {
+			t.Fatalf("%s: wanted %s, got %s", p, exp, got)
+		}
+	}
+}
+
+func TestConfig_Init_fileType(t *testing.T) {
+	t.Parallel()
+	cfg := &Config{
+		Files: []*File{
+			{Pattern: `^\.gitlab-ci\.yml$`, Type: "gitlab"},
+		},
+	}
+	if err := cfg.Init(); err == nil {
+		t.Fatal("an unknown file type must be rejected")
+	}
+}
+
+func TestController_runImageFile(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name    string
+		pattern *regexp.Regexp
+		content string
+		exp     []string
+	}{
+		{
+			name:    "gitlab-ci",
+			pattern: gitlabCIImagePattern,
+			content: `image: golang:1.24
+test:
+  image: "alpine:3.21"
+  services:
+    - image: alpine:3.21 # database
+  script:
+    - go test ./...
+build:
+  image: $BUILD_IMAGE
+`,
+			exp: []string{
+				"image: golang:1.24@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+				"test:",
+				`  image: "alpine:3.21@sha256:2222222222222222222222222222222222222222222222222222222222222222"`,
+				"  services:",
+				"    - image: alpine:3.21@sha256:2222222222222222222222222222222222222222222222222222222222222222 # database",
+				"  script:",
+				"    - go test ./...",
+				"build:",
+				"  image: $BUILD_IMAGE",
+			},
+		},
+		{
+			name:    "devcontainer",
+			pattern: devcontainerImagePattern,
+			content: `{
+  "name": "go",
+  "image": "golang:1.24",
+  "features": {}
+}
+`,
+			exp: []string{
+				"{",
+				`  "name": "go",`,
+				`  "image": "golang:1.24@sha256:1111111111111111111111111111111111111111111111111111111111111111",`,
+				`  "features": {}`,
+				"}",
+			},
+		},
+		{
+			name:    "pinned",
+			pattern: gitlabCIImagePattern,
+			content: "image: alpine:3.21@sha256:0000000000000000000000000000000000000000000000000000000000000000\n",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			filePath := filepath.Join(t.TempDir(), "file")
+			if err := os.WriteFile(filePath, []byte(d.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			ctrl := NewController(nil, afero.NewOsFs())
+			ctrl.registryService = &testRegistryService{
+				digests: map[string]string{
+					"golang:1.24": "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+					"alpine:3.21": "sha256:2222222222222222222222222222222222222222222222222222222222222222",
+				},
+			}
+			change, err := ctrl.runImageFile(context.Background(), logrus.NewEntry(logrus.New()), filePath, d.pattern, &Stats{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.exp == nil {
+				if change != nil {
+					t.Fatalf("the file must not be changed: %v", change.Lines)
+				}
+				return
+			}
+			if change == nil {
+				t.Fatal("the file must be changed")
+			}
+			if diff := cmp.Diff(d.exp, change.Lines); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package run
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// floatingTagDatePattern matches the resolution date appended to a floating tag annotation.
+// e.g. " (2024-06-01)" of "# v1 (2024-06-01)"
+var floatingTagDatePattern = regexp.MustCompile(`^ \(\d{4}-\d{2}-\d{2}\)`)
+
+// patchVersion patches a line with a commit hash and a version annotation.
+// If floating_tags is enabled and the annotation is a floating tag such as v1, the resolution date is appended to the annotation.
+func (c *Config) patchVersion(action *Action, sha, tag string) string {
+	if !c.FloatingTags {
+		return patchLine(action, sha, tag)
+	}
+	a := *action
+	a.Suffix = floatingTagDatePattern.ReplaceAllString(action.Suffix, "")
+	if getVersionType(tag) == Shortsemver {
+		a.Suffix = " (" + c.Now.Format(time.DateOnly) + ")" + a.Suffix
+	}
+	return patchLine(&a, sha, tag)
+}
+
+// updateFloatingTag updates the commit hash if the floating tag has moved.
+// Some actions publish only floating tags such as v1 without point releases.
+// e.g. @<old commit hash> # v1 (2024-06-01) => @<new commit hash> # v1 (2024-07-01)
+func (c *Controller) updateFloatingTag(ctx context.Context, logE *logrus.Entry, line string, cfg *Config, action *Action) (string, error) {
+	sha, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, action.Tag, "")
+	if err != nil {
+		c.logResolveError(logE, cfg, action, err, "get a reference")
+		return line, nil
+	}
+	if sha == action.Version {
+		return line, nil
+	}
+	logE.WithFields(logrus.Fields{
+		"tag":     action.Tag,
+		"old_sha": action.Version,
+		"new_sha": sha,
+	}).Info("the floating tag has moved")
+	return cfg.patchVersion(action, sha, action.Tag), nil
+}
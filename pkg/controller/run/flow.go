@@ -0,0 +1,107 @@
+package run
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// flowUsesPattern matches uses in YAML flow mappings such as {uses: actions/checkout@v4}.
+	// A line may have multiple matches. e.g. steps: [{uses: actions/checkout@v4}, {uses: actions/setup-go@v5}]
+	flowUsesPattern = regexp.MustCompile(`[{,][ \t]*['"]?uses['"]?[ \t]*:[ \t]*(['"]?)([^@ \t'"{},]+)@([^ \t'"{},]+)(['"]?)`)
+	// flowCommentPattern matches the comment at the end of a line having flow mappings.
+	flowCommentPattern = regexp.MustCompile(`^([ \t]+#[ \t]+)(.*)$`)
+)
+
+// parseFlowLine patches each action in YAML flow mappings independently.
+// As YAML doesn't allow comments in the middle of a line, version annotations are joined with ", "
+// in the comment at the end of the line in the order of actions. e.g. # v4.2.2, v5.3.0
+// If the number of annotations doesn't match the number of actions, the comment is treated as a normal comment.
+func (c *Controller) parseFlowLine(ctx context.Context, logE *logrus.Entry, line string, cfg *Config) (string, error) {
+	locs := flowUsesPattern.FindAllStringSubmatchIndex(line, -1)
+	if len(locs) == 0 {
+		// Ignore a line if the line doesn't use an action.
+		logE.WithField("line", line).Debug("unmatch")
+		return line, nil
+	}
+	if isIgnoredInline(logE, line, cfg) {
+		return line, nil
+	}
+	tags := make([]string, len(locs))
+	end := locs[len(locs)-1][1]
+	sep := " # "
+	rest := line[end:]
+	if i := strings.LastIndex(rest, " #"); i != -1 {
+		if m := flowCommentPattern.FindStringSubmatch(rest[i:]); m != nil {
+			if annotations := strings.Split(m[2], ", "); isFlowAnnotations(annotations, len(locs)) {
+				copy(tags, annotations)
+				sep = m[1]
+				rest = rest[:i]
+			}
+		}
+	}
+
+	var b strings.Builder
+	prev := 0
+	newTags := make([]string, len(locs))
+	for i, loc := range locs {
+		// loc[2:4] is the opening quote, loc[4:6] is the action name, loc[6:8] is the version, and loc[8:10] is the closing quote.
+		if line[loc[2]:loc[3]] != line[loc[8]:loc[9]] {
+			// Quotes are mismatched.
+			return line, nil
+		}
+		name := line[loc[4]:loc[5]]
+		version := line[loc[6]:loc[7]]
+		action, err := c.parseFlowAction(ctx, logE, cfg, name, version, tags[i])
+		if err != nil {
+			return line, err
+		}
+		b.WriteString(line[prev:loc[4]])
+		b.WriteString(action.Name + "@" + action.Version)
+		prev = loc[7]
+		newTags[i] = action.Tag
+	}
+	b.WriteString(line[prev:end])
+	b.WriteString(rest)
+	if slices.Contains(newTags, "") {
+		// Some actions aren't annotated, so annotations can't be associated with actions.
+		// The original comment is kept.
+		b.WriteString(line[end+len(rest):])
+	} else {
+		b.WriteString(sep + strings.Join(newTags, ", "))
+	}
+	return b.String(), nil
+}
+
+// isFlowAnnotations returns true if annotations are version annotations of n actions.
+func isFlowAnnotations(annotations []string, n int) bool {
+	if len(annotations) != n {
+		return false
+	}
+	for _, a := range annotations {
+		switch getVersionType(a) {
+		case Semver, Shortsemver:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// parseFlowAction patches an action in a flow mapping as a block style line and returns the patched action.
+// The action name may be changed by mirrors.
+func (c *Controller) parseFlowAction(ctx context.Context, logE *logrus.Entry, cfg *Config, name, version, tag string) (*Action, error) {
+	line := "uses: " + name + "@" + version
+	if tag != "" {
+		line += " # " + tag
+	}
+	l, err := c.parseLine(ctx, logE, line, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return parseAction(l), nil
+}
@@ -0,0 +1,93 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+type ParamFmt struct {
+	WorkflowFilePaths []string
+	ConfigFilePath    string
+	PWD               string
+	Now               time.Time
+	Stderr            io.Writer
+}
+
+// Fmt normalizes version annotations to the canonical form without calling GitHub API.
+// e.g. @<commit hash>  #   tag=1.0.0 => @<commit hash> # v1.0.0
+func (c *Controller) Fmt(logE *logrus.Entry, param *ParamFmt) error {
+	cfg := &Config{}
+	if err := c.readConfig(logE, param.ConfigFilePath, param.PWD, cfg); err != nil {
+		return err
+	}
+	if err := cfg.Init(); err != nil {
+		return fmt.Errorf("initialize the configuration: %w", err)
+	}
+	cfg.Now = param.Now
+	workflowFilePaths, err := c.searchFiles(logE, param.WorkflowFilePaths, cfg, param.PWD)
+	if err != nil {
+		return fmt.Errorf("search target files: %w", err)
+	}
+	stats := &Stats{
+		Time:  param.Now,
+		Files: len(workflowFilePaths),
+	}
+	defer printSummary(param.Stderr, stats)
+	for _, workflowFilePath := range workflowFilePaths {
+		logE := logE.WithField("workflow_file", workflowFilePath)
+		if !filepath.IsAbs(workflowFilePath) {
+			workflowFilePath = filepath.Join(param.PWD, workflowFilePath)
+		}
+		if err := c.fmtWorkflow(logE, workflowFilePath, cfg, stats); err != nil {
+			logerr.WithError(logE, err).Warn("format a workflow")
+			stats.Errors++
+		}
+	}
+	return nil
+}
+
+func (c *Controller) fmtWorkflow(logE *logrus.Entry, workflowFilePath string, cfg *Config, stats *Stats) error {
+	lines, err := c.readWorkflow(workflowFilePath)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i, line := range lines {
+		l := c.fmtLine(logE, line, cfg)
+		stats.addLine(line, l)
+		if l != line {
+			changed = true
+			lines[i] = l
+		}
+	}
+	if !changed {
+		return nil
+	}
+	stats.ChangedFiles++
+	return writeWorkflow(&workflowChange{
+		Path:  workflowFilePath,
+		Lines: lines,
+	})
+}
+
+// fmtLine normalizes the version annotation of a line.
+// The separator is normalized to " # ", "tag=" is removed, and the prefix "v" is added.
+func (c *Controller) fmtLine(logE *logrus.Entry, line string, cfg *Config) string {
+	action := c.getTargetAction(logE, line, cfg)
+	if action == nil || action.Tag == "" {
+		return line
+	}
+	tag := action.Tag
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	a := *action
+	a.VersionTagSeparator = " # "
+	return patchLine(&a, action.Version, tag)
+}
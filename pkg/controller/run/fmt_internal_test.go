@@ -0,0 +1,94 @@
+package run
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_fmtLine(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		line string
+		exp  string
+	}{
+		{
+			name: "canonical",
+			line: "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+			exp:  "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		},
+		{
+			name: "separator",
+			line: "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683   #  v4.2.2",
+			exp:  "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		},
+		{
+			name: "tag=",
+			line: "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # tag=v4.2.2",
+			exp:  "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		},
+		{
+			name: "v prefix",
+			line: "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # 4.2.2",
+			exp:  "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		},
+		{
+			name: "quote and suffix",
+			line: `  - uses: "actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683" # tag=4.2.2 - pinned for reasons`,
+			exp:  `  - uses: "actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683" # v4.2.2 - pinned for reasons`,
+		},
+		{
+			name: "no annotation",
+			line: "  - uses: actions/checkout@v4",
+			exp:  "  - uses: actions/checkout@v4",
+		},
+		{
+			name: "ignored",
+			line: "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # tag=4.2.2 # pinact:ignore",
+			exp:  "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # tag=4.2.2 # pinact:ignore",
+		},
+	}
+	logE := logrus.NewEntry(logrus.New())
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if l := ctrl.fmtLine(logE, d.line, &Config{}); l != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, l)
+			}
+		})
+	}
+}
+
+func TestController_Fmt(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	workflowFilePath := filepath.Join(dir, "test.yaml")
+	content := `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683  #   tag=4.2.2
+      - uses: actions/setup-go@v5
+`
+	if err := os.WriteFile(workflowFilePath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stderr := &bytes.Buffer{}
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	if err := ctrl.Fmt(logrus.NewEntry(logrus.New()), &ParamFmt{
+		WorkflowFilePaths: []string{workflowFilePath},
+		PWD:               dir,
+		Stderr:            stderr,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("pinact: 1 file changed, 1 line changed, 1/2 actions pinned\n", stderr.String()); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,44 @@
+package run
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// forkUpstream returns the action of the parent repository if skip_forks is enabled and the action's repository is a fork.
+// Forks often stop syncing tags with the parent, so tracking their tags may miss new releases.
+// The returned action is used only to get the latest version. Lines keep the fork and the version is resolved in the fork.
+// The path of the action in the repository is kept.
+// If the repository isn't a fork or the parent can't be got, the action is returned as is.
+func (c *Controller) forkUpstream(ctx context.Context, logE *logrus.Entry, cfg *Config, action *Action) *Action {
+	if !cfg.SkipForks {
+		return action
+	}
+	repo, _, err := c.repositoriesService.Get(ctx, action.RepoOwner, action.RepoName)
+	if err != nil {
+		c.logResolveError(logE, cfg, action, err, "get a repository")
+		return action
+	}
+	if !repo.GetFork() {
+		return action
+	}
+	parent := repo.GetParent()
+	if parent.GetOwner().GetLogin() == "" || parent.GetName() == "" {
+		logE.Debug("the parent of the forked repository isn't found")
+		return action
+	}
+	a := *action
+	a.RepoOwner = parent.GetOwner().GetLogin()
+	a.RepoName = parent.GetName()
+	a.Name = a.RepoOwner + "/" + a.RepoName
+	if parts := strings.SplitN(action.Name, "/", 3); len(parts) == 3 { //nolint:mnd
+		a.Name += "/" + parts[2]
+	}
+	logE.WithFields(logrus.Fields{
+		"upstream":  a.Name,
+		"help_docs": "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/013.md",
+	}).Warn("the action's repository is a fork. The latest version is resolved against the parent repository")
+	return &a
+}
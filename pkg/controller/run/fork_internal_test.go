@@ -0,0 +1,177 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+func TestController_forkUpstream(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		cfg    *Config
+		action *Action
+		exp    *Action
+	}{
+		{
+			name:   "disabled",
+			cfg:    &Config{},
+			action: &Action{Name: "suzuki-shunsuke/forked-action", RepoOwner: "suzuki-shunsuke", RepoName: "forked-action"},
+			exp:    &Action{Name: "suzuki-shunsuke/forked-action", RepoOwner: "suzuki-shunsuke", RepoName: "forked-action"},
+		},
+		{
+			name:   "not fork",
+			cfg:    &Config{SkipForks: true},
+			action: &Action{Name: "actions/checkout", RepoOwner: "actions", RepoName: "checkout"},
+			exp:    &Action{Name: "actions/checkout", RepoOwner: "actions", RepoName: "checkout"},
+		},
+		{
+			name:   "fork",
+			cfg:    &Config{SkipForks: true},
+			action: &Action{Name: "suzuki-shunsuke/forked-action", RepoOwner: "suzuki-shunsuke", RepoName: "forked-action", Version: "v1"},
+			exp:    &Action{Name: "suzuki-shunsuke/upstream-action", RepoOwner: "suzuki-shunsuke", RepoName: "upstream-action", Version: "v1"},
+		},
+		{
+			name:   "fork with a path",
+			cfg:    &Config{SkipForks: true},
+			action: &Action{Name: "suzuki-shunsuke/forked-action/setup", RepoOwner: "suzuki-shunsuke", RepoName: "forked-action"},
+			exp:    &Action{Name: "suzuki-shunsuke/upstream-action/setup", RepoOwner: "suzuki-shunsuke", RepoName: "upstream-action"},
+		},
+		{
+			name:   "parent isn't found",
+			cfg:    &Config{SkipForks: true},
+			action: &Action{Name: "suzuki-shunsuke/orphan-action", RepoOwner: "suzuki-shunsuke", RepoName: "orphan-action"},
+			exp:    &Action{Name: "suzuki-shunsuke/orphan-action", RepoOwner: "suzuki-shunsuke", RepoName: "orphan-action"},
+		},
+	}
+	ctx := context.Background()
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				repos: map[string]*GetRepositoryResult{
+					"actions/checkout": {
+						Repository: &github.Repository{},
+					},
+					"suzuki-shunsuke/forked-action": {
+						Repository: &github.Repository{
+							Fork: util.BoolP(true),
+							Parent: &github.Repository{
+								Owner: &github.User{
+									Login: util.StrP("suzuki-shunsuke"),
+								},
+								Name: util.StrP("upstream-action"),
+							},
+						},
+					},
+					"suzuki-shunsuke/orphan-action": {
+						Repository: &github.Repository{
+							Fork: util.BoolP(true),
+						},
+					},
+				},
+			}, afero.NewMemMapFs())
+			a := ctrl.forkUpstream(ctx, logE, d.cfg, d.action)
+			if diff := cmp.Diff(d.exp, a); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestController_parseLine_fork(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		line string
+		exp  string
+	}{
+		{
+			name: "semver",
+			line: "  uses: suzuki-shunsuke/forked-action@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0",
+			exp:  "  uses: suzuki-shunsuke/forked-action@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.0.0",
+		},
+		{
+			name: "short semver",
+			line: "  uses: suzuki-shunsuke/forked-action@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1",
+			exp:  "  uses: suzuki-shunsuke/forked-action@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.0.0",
+		},
+		{
+			name: "tag without annotation",
+			line: "  uses: suzuki-shunsuke/forked-action@v1",
+			exp:  "  uses: suzuki-shunsuke/forked-action@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.0.0",
+		},
+		{
+			name: "commit hash without annotation",
+			line: "  uses: suzuki-shunsuke/forked-action@8e5e7e5ab8b370d6c329ec480221332ada57f0ab",
+			exp:  "  uses: suzuki-shunsuke/forked-action@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.0.0",
+		},
+		{
+			name: "the fork doesn't have the latest version",
+			line: "  uses: suzuki-shunsuke/stale-fork@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0",
+			exp:  "  uses: suzuki-shunsuke/stale-fork@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1.0.0",
+		},
+	}
+	parent := &github.Repository{
+		Owner: &github.User{
+			Login: util.StrP("suzuki-shunsuke"),
+		},
+		Name: util.StrP("upstream-action"),
+	}
+	ctx := context.Background()
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				repos: map[string]*GetRepositoryResult{
+					"suzuki-shunsuke/forked-action": {
+						Repository: &github.Repository{Fork: util.BoolP(true), Parent: parent},
+					},
+					"suzuki-shunsuke/stale-fork": {
+						Repository: &github.Repository{Fork: util.BoolP(true), Parent: parent},
+					},
+				},
+				releases: map[string]*ListReleasesResult{
+					"suzuki-shunsuke/upstream-action/0": {
+						Releases: []*github.RepositoryRelease{
+							{TagName: util.StrP("v2.0.0")},
+						},
+						Response: &github.Response{},
+					},
+				},
+				commits: map[string]*GetCommitSHA1Result{
+					"suzuki-shunsuke/forked-action/v2.0.0": {
+						SHA: "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+					},
+					"suzuki-shunsuke/forked-action/v1": {
+						SHA: "8e5e7e5ab8b370d6c329ec480221332ada57f0ab",
+					},
+					"suzuki-shunsuke/stale-fork/v2.0.0": {
+						err: errors.New("not found"),
+					},
+				},
+			}, afero.NewMemMapFs())
+			ctrl.update = true
+			cfg := &Config{SkipForks: true}
+			if err := cfg.Init(); err != nil {
+				t.Fatal(err)
+			}
+			line, err := ctrl.parseLine(ctx, logE, d.line, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(d.exp, line); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
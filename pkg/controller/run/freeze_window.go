@@ -0,0 +1,64 @@
+package run
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const defaultFreezeWindowDuration = 24 * time.Hour
+
+type FreezeWindow struct {
+	Name     string `json:"name" jsonschema:"description=A regular expression of actions that aren't updated during the freeze window"`
+	Cron     string `json:"cron" jsonschema:"description=A cron expression when the freeze window starts. e.g. 0 0 * * 5-7"`
+	Duration string `json:"duration,omitempty" jsonschema:"description=How long the freeze window lasts from each start time. The format is Go's time.Duration. The default value is 24h"`
+	name     *regexp.Regexp
+	schedule *cronSchedule
+	duration time.Duration
+	active   bool
+}
+
+func (w *FreezeWindow) init() error {
+	p, err := regexp.Compile(w.Name)
+	if err != nil {
+		return fmt.Errorf("parse freeze_windows[].name as a regular expression: %w", err)
+	}
+	w.name = p
+	s, err := parseCron(w.Cron)
+	if err != nil {
+		return fmt.Errorf("parse freeze_windows[].cron: %w", err)
+	}
+	w.schedule = s
+	w.duration = defaultFreezeWindowDuration
+	if w.Duration != "" {
+		d, err := time.ParseDuration(w.Duration)
+		if err != nil {
+			return fmt.Errorf("parse freeze_windows[].duration: %w", err)
+		}
+		w.duration = d
+	}
+	return nil
+}
+
+// setFreezeWindows evaluates whether each freeze window is active at now.
+func (c *Config) setFreezeWindows(now time.Time) {
+	for _, w := range c.FreezeWindows {
+		w.active = w.schedule.activeSince(now, w.duration)
+	}
+}
+
+// matchFreezeWindow returns the index of the active freeze window matching with the action.
+// If no freeze window matches, it returns -1.
+// Inactive freeze windows matching with the action are also recorded as used rules.
+func (c *Config) matchFreezeWindow(actionName string) int {
+	for i, w := range c.FreezeWindows {
+		if !w.name.MatchString(actionName) {
+			continue
+		}
+		c.useRule(fmt.Sprintf("freeze_windows[%d]", i))
+		if w.active {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,200 @@
+package run
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// WorkflowTypeUpdate is a workflow updating actions and creating pull requests periodically.
+	WorkflowTypeUpdate = "update"
+	// WorkflowTypeCheck is a workflow checking pull requests and posting suggestions as reviews.
+	WorkflowTypeCheck = "check"
+
+	defaultWorkflowFilePath = ".github/workflows/pinact.yml"
+	defaultWorkflowSchedule = "0 0 * * 1"
+
+	// Placeholders are replaced by strings.Replacer instead of text/template because workflows include expressions such as ${{ github.token }}.
+	templateUpdateWorkflow = `# Generated by pinact generate-workflow
+name: pinact
+on:
+  schedule:
+    - cron: "__SCHEDULE__"
+  workflow_dispatch: {}
+permissions: {}
+jobs:
+  pinact:
+    runs-on: ubuntu-latest
+    timeout-minutes: 30
+    permissions:
+      contents: write
+      pull-requests: write
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          persist-credentials: false
+      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+      - run: go install github.com/suzuki-shunsuke/pinact/cmd/pinact@latest
+      - run: pinact pr --group-by __GROUP_BY__
+        env:
+          GITHUB_TOKEN: ${{ github.token }}
+`
+	templateCheckWorkflow = `# Generated by pinact generate-workflow
+name: pinact
+on: pull_request
+permissions: {}
+jobs:
+  pinact:
+    runs-on: ubuntu-latest
+    timeout-minutes: 10
+    permissions:
+      contents: read
+      pull-requests: write
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          persist-credentials: false
+      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+      - uses: reviewdog/action-setup@v1
+      - run: go install github.com/suzuki-shunsuke/pinact/cmd/pinact@latest
+      - run: pinact run --format rdjson | reviewdog -f=rdjson -reporter=github-pr-review -fail-level=any
+        env:
+          GITHUB_TOKEN: ${{ github.token }}
+          REVIEWDOG_GITHUB_API_TOKEN: ${{ github.token }}
+`
+)
+
+type ParamGenerateWorkflow struct {
+	// Type is either WorkflowTypeUpdate or WorkflowTypeCheck. If this is empty, users are asked.
+	Type string
+	// OutputFilePath is a generated workflow file path. If this is empty, .github/workflows/pinact.yml is used.
+	OutputFilePath string
+	Schedule       string
+	GroupBy        string
+	Force          bool
+	ConfigFilePath string
+	PWD            string
+	Now            time.Time
+	Stdin          io.Reader
+	Stderr         io.Writer
+}
+
+// GenerateWorkflow generates a GitHub Actions workflow running pinact.
+// Actions in the generated workflow are pinned by pinact itself.
+func (c *Controller) GenerateWorkflow(ctx context.Context, logE *logrus.Entry, param *ParamGenerateWorkflow) error {
+	if param.Type == "" {
+		t, err := askWorkflowType(param)
+		if err != nil {
+			return err
+		}
+		param.Type = t
+	}
+	content, err := renderWorkflow(param)
+	if err != nil {
+		return err
+	}
+
+	outputFilePath := param.OutputFilePath
+	if outputFilePath == "" {
+		outputFilePath = defaultWorkflowFilePath
+	}
+	if !filepath.IsAbs(outputFilePath) {
+		outputFilePath = filepath.Join(param.PWD, outputFilePath)
+	}
+	f, err := afero.Exists(c.fs, outputFilePath)
+	if err != nil {
+		return fmt.Errorf("check if a workflow file exists: %w", err)
+	}
+	if f && !param.Force {
+		return fmt.Errorf("a workflow file already exists. Pass --force to overwrite it: %s", outputFilePath)
+	}
+
+	cfg := &Config{}
+	if err := c.readConfig(logE, param.ConfigFilePath, param.PWD, cfg); err != nil {
+		return err
+	}
+	if err := cfg.Init(); err != nil {
+		return fmt.Errorf("initialize the configuration: %w", err)
+	}
+	cfg.Now = param.Now
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		l, err := c.parseLine(ctx, logE, line, cfg)
+		if err != nil {
+			return fmt.Errorf("pin actions in the workflow: %w", err)
+		}
+		lines[i] = l
+	}
+
+	if err := c.fs.MkdirAll(filepath.Dir(outputFilePath), 0o755); err != nil { //nolint:mnd
+		return fmt.Errorf("create a directory for the workflow: %w", err)
+	}
+	if err := afero.WriteFile(c.fs, outputFilePath, []byte(strings.Join(lines, "\n")), filePermission); err != nil {
+		return fmt.Errorf("write a workflow file: %w", err)
+	}
+	logE.WithField("workflow_file", outputFilePath).Info("a workflow has been generated")
+	return nil
+}
+
+// renderWorkflow renders the template of the workflow type.
+func renderWorkflow(param *ParamGenerateWorkflow) (string, error) {
+	switch param.Type {
+	case WorkflowTypeUpdate:
+		schedule := param.Schedule
+		if schedule == "" {
+			schedule = defaultWorkflowSchedule
+		}
+		if _, err := parseCron(schedule); err != nil {
+			return "", fmt.Errorf("parse --schedule: %w", err)
+		}
+		groupBy := param.GroupBy
+		if groupBy == "" {
+			groupBy = GroupByAll
+		}
+		switch groupBy {
+		case GroupByAll, GroupByAction, GroupByMajor:
+		default:
+			return "", fmt.Errorf("--group-by must be one of %s, %s, and %s: %s", GroupByAll, GroupByAction, GroupByMajor, groupBy)
+		}
+		return strings.NewReplacer(
+			"__SCHEDULE__", schedule,
+			"__GROUP_BY__", groupBy,
+		).Replace(templateUpdateWorkflow), nil
+	case WorkflowTypeCheck:
+		return templateCheckWorkflow, nil
+	default:
+		return "", fmt.Errorf("--type must be either %s or %s: %s", WorkflowTypeUpdate, WorkflowTypeCheck, param.Type)
+	}
+}
+
+// askWorkflowType asks users which workflow is generated.
+func askWorkflowType(param *ParamGenerateWorkflow) (string, error) {
+	fmt.Fprintf(param.Stderr, `Which workflow do you want to generate?
+  1) %s: Update actions and create pull requests periodically
+  2) %s: Check pull requests and post suggestions as reviews
+[1/2]: `, WorkflowTypeUpdate, WorkflowTypeCheck)
+	answer, err := bufio.NewReader(param.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read the answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "1", WorkflowTypeUpdate:
+		return WorkflowTypeUpdate, nil
+	case "2", WorkflowTypeCheck:
+		return WorkflowTypeCheck, nil
+	}
+	return "", errors.New("the workflow type isn't selected. Pass --type to select it without prompts")
+}
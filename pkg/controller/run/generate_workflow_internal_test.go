@@ -0,0 +1,129 @@
+package run
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_renderWorkflow(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name     string
+		param    *ParamGenerateWorkflow
+		contains []string
+		isErr    bool
+	}{
+		{
+			name:  "update",
+			param: &ParamGenerateWorkflow{Type: WorkflowTypeUpdate},
+			contains: []string{
+				`- cron: "0 0 * * 1"`,
+				"pinact pr --group-by all",
+				"GITHUB_TOKEN: ${{ github.token }}",
+			},
+		},
+		{
+			name: "update with a schedule and a group",
+			param: &ParamGenerateWorkflow{
+				Type:     WorkflowTypeUpdate,
+				Schedule: "0 9 * * *",
+				GroupBy:  GroupByMajor,
+			},
+			contains: []string{
+				`- cron: "0 9 * * *"`,
+				"pinact pr --group-by major",
+			},
+		},
+		{
+			name:  "check",
+			param: &ParamGenerateWorkflow{Type: WorkflowTypeCheck},
+			contains: []string{
+				"on: pull_request",
+				"reviewdog -f=rdjson",
+			},
+		},
+		{
+			name:  "invalid schedule",
+			param: &ParamGenerateWorkflow{Type: WorkflowTypeUpdate, Schedule: "daily"},
+			isErr: true,
+		},
+		{
+			name:  "invalid group",
+			param: &ParamGenerateWorkflow{Type: WorkflowTypeUpdate, GroupBy: "owner"},
+			isErr: true,
+		},
+		{
+			name:  "invalid type",
+			param: &ParamGenerateWorkflow{Type: "release"},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := renderWorkflow(d.param)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			for _, c := range d.contains {
+				if !strings.Contains(s, c) {
+					t.Fatalf("the workflow must contain %q: %s", c, s)
+				}
+			}
+		})
+	}
+}
+
+func Test_askWorkflowType(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		answer string
+		exp    string
+		isErr  bool
+	}{
+		{
+			name:   "number",
+			answer: "2\n",
+			exp:    WorkflowTypeCheck,
+		},
+		{
+			name:   "name",
+			answer: "Update\n",
+			exp:    WorkflowTypeUpdate,
+		},
+		{
+			name:   "no answer",
+			answer: "",
+			isErr:  true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			typ, err := askWorkflowType(&ParamGenerateWorkflow{
+				Stdin:  strings.NewReader(d.answer),
+				Stderr: &bytes.Buffer{},
+			})
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if typ != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, typ)
+			}
+		})
+	}
+}
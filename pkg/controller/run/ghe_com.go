@@ -0,0 +1,59 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+// gheComDomain is the domain of GitHub Enterprise Cloud with data residency.
+// Unlike GitHub Enterprise Server, the API is served by the subdomain api.<subdomain>.ghe.com instead of the path /api/v3.
+const gheComDomain = "ghe.com"
+
+var gheComSubdomainPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+type GHECom struct {
+	Subdomain string `json:"subdomain" jsonschema:"description=The subdomain of GHE.com. e.g. octocorp of octocorp.ghe.com"`
+}
+
+func (g *GHECom) init() error {
+	if g.Subdomain == "" {
+		return errors.New("ghe_com.subdomain is required")
+	}
+	if !gheComSubdomainPattern.MatchString(g.Subdomain) {
+		return fmt.Errorf("ghe_com.subdomain must be a subdomain such as octocorp: %s", g.Subdomain)
+	}
+	return nil
+}
+
+func (g *GHECom) host() string {
+	return g.Subdomain + "." + gheComDomain
+}
+
+func (g *GHECom) apiURL() string {
+	return "https://api." + g.host() + "/"
+}
+
+// isGHEComAPIHost returns true if the host is the API host of GHE.com such as api.octocorp.ghe.com.
+func isGHEComAPIHost(host string) bool {
+	return strings.HasPrefix(host, "api.") && strings.HasSuffix(host, "."+gheComDomain)
+}
+
+// newGHEComRepositoriesService returns a RepositoriesService resolving versions by the API of GHE.com.
+// The access token and the options to record and replay interactions of the default client are used.
+func (c *Controller) newGHEComRepositoriesService(ctx context.Context, cfg *GHECom) (RepositoriesService, error) {
+	input := github.InputNew{}
+	if c.githubInput != nil {
+		input = *c.githubInput
+	}
+	input.APIURL = cfg.apiURL()
+	gh, err := github.New(ctx, &input)
+	if err != nil {
+		return nil, fmt.Errorf("create a GitHub client for GHE.com: %w", err)
+	}
+	return newRepositoriesServiceImpl(NewGitHubRepositoriesService(gh)), nil
+}
@@ -0,0 +1,54 @@
+package run
+
+import (
+	"testing"
+)
+
+func TestGHECom_init(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name      string
+		subdomain string
+		isErr     bool
+	}{
+		{
+			name:      "normal",
+			subdomain: "octocorp",
+		},
+		{
+			name:  "empty",
+			isErr: true,
+		},
+		{
+			name:      "host",
+			subdomain: "octocorp.ghe.com",
+			isErr:     true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			err := (&GHECom{Subdomain: d.subdomain}).init()
+			if d.isErr {
+				if err == nil {
+					t.Fatal("error must be returned")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestGHECom_apiURL(t *testing.T) {
+	t.Parallel()
+	g := &GHECom{Subdomain: "octocorp"}
+	if u := g.apiURL(); u != "https://api.octocorp.ghe.com/" {
+		t.Fatalf("wanted https://api.octocorp.ghe.com/, got %s", u)
+	}
+	if h := g.host(); h != "octocorp.ghe.com" {
+		t.Fatalf("wanted octocorp.ghe.com, got %s", h)
+	}
+}
@@ -0,0 +1,200 @@
+package run
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/offline"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+// GitSSHService resolves tags and commit hashes by `git ls-remote` over SSH.
+// It's used in environments where the REST API isn't available but a deploy key can read repositories.
+type GitSSHService struct {
+	host    string
+	user    string
+	keyPath string
+	// execLsRemote executes `git ls-remote` and returns the standard output. It's replaced in tests.
+	execLsRemote func(ctx context.Context, env []string, args ...string) ([]byte, error)
+}
+
+func NewGitSSHService(cfg *GitSSH) *GitSSHService {
+	host := cfg.Host
+	if host == "" {
+		host = "github.com"
+	}
+	user := cfg.User
+	if user == "" {
+		user = "git"
+	}
+	return &GitSSHService{
+		host:         host,
+		user:         user,
+		keyPath:      cfg.KeyPath,
+		execLsRemote: execLsRemote,
+	}
+}
+
+type lsRemoteRef struct {
+	SHA  string
+	Name string
+}
+
+func (s *GitSSHService) lsRemote(ctx context.Context, owner, repo string, patterns ...string) ([]*lsRemoteRef, error) {
+	if offline.Enabled() {
+		return nil, offline.ErrOffline
+	}
+	url := fmt.Sprintf("%s@%s:%s/%s.git", s.user, s.host, owner, repo)
+	env := os.Environ()
+	if s.keyPath != "" {
+		// GIT_SSH_COMMAND is interpreted by the shell, so the key path must be quoted.
+		env = append(env, fmt.Sprintf("GIT_SSH_COMMAND=ssh -i %s -o IdentitiesOnly=yes", shellQuote(s.keyPath)))
+	}
+	out, err := s.execLsRemote(ctx, env, append([]string{url}, patterns...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLsRemote(out), nil
+}
+
+func execLsRemote(ctx context.Context, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"ls-remote"}, args...)...)
+	cmd.Env = env
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("execute git ls-remote: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// shellQuote quotes a string with single quotes for POSIX shells.
+// A single quote in the string is escaped by closing the quoted string, adding an escaped quote, and reopening it.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// parseLsRemote parses the output of `git ls-remote`.
+// If an annotated tag is peeled (refs/tags/<tag>^{}), the commit hash of the peeled tag is used.
+func parseLsRemote(out []byte) []*lsRemoteRef {
+	refs := []*lsRemoteRef{}
+	indexes := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		sha, name, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok {
+			continue
+		}
+		if n, ok := strings.CutSuffix(name, "^{}"); ok {
+			if i, ok := indexes[n]; ok {
+				refs[i].SHA = sha
+				continue
+			}
+			name = n
+		}
+		indexes[name] = len(refs)
+		refs = append(refs, &lsRemoteRef{
+			SHA:  sha,
+			Name: name,
+		})
+	}
+	return refs
+}
+
+func (s *GitSSHService) ListTags(ctx context.Context, owner string, repo string, _ *github.ListOptions) ([]*github.RepositoryTag, *github.Response, error) {
+	refs, err := s.lsRemote(ctx, owner, repo, "refs/tags/*")
+	if err != nil {
+		return nil, nil, err
+	}
+	tags := make([]*github.RepositoryTag, 0, len(refs))
+	for _, ref := range refs {
+		tags = append(tags, &github.RepositoryTag{
+			Name: util.StrP(strings.TrimPrefix(ref.Name, "refs/tags/")),
+			Commit: &github.Commit{
+				SHA: util.StrP(ref.SHA),
+			},
+		})
+	}
+	// git ls-remote returns all tags at once, so there is no next page.
+	return tags, &github.Response{}, nil
+}
+
+func (s *GitSSHService) ListMatchingTags(ctx context.Context, owner, repo, prefix string) ([]*github.RepositoryTag, *github.Response, error) {
+	refs, err := s.lsRemote(ctx, owner, repo, "refs/tags/"+prefix+"*")
+	if err != nil {
+		return nil, nil, err
+	}
+	tags := make([]*github.RepositoryTag, 0, len(refs))
+	for _, ref := range refs {
+		tags = append(tags, &github.RepositoryTag{
+			Name: util.StrP(strings.TrimPrefix(ref.Name, "refs/tags/")),
+			Commit: &github.Commit{
+				SHA: util.StrP(ref.SHA),
+			},
+		})
+	}
+	return tags, &github.Response{}, nil
+}
+
+func (s *GitSSHService) GetCommitSHA1(ctx context.Context, owner, repo, ref, _ string) (string, *github.Response, error) {
+	if fullCommitSHAPattern.MatchString(ref) {
+		return ref, &github.Response{}, nil
+	}
+	refs, err := s.lsRemote(ctx, owner, repo, "refs/tags/"+ref, "refs/tags/"+ref+"^{}", "refs/heads/"+ref)
+	if err != nil {
+		return "", nil, err
+	}
+	// Prefer tags to branches as GitHub Actions does.
+	for _, prefix := range []string{"refs/tags/", "refs/heads/"} {
+		for _, r := range refs {
+			if r.Name == prefix+ref {
+				return r.SHA, &github.Response{}, nil
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("ref isn't found: %s", ref)
+}
+
+func (s *GitSSHService) ListReleases(context.Context, string, string, *github.ListOptions) ([]*github.RepositoryRelease, *github.Response, error) {
+	// Releases aren't available via git, so the latest version is resolved from tags.
+	return nil, &github.Response{}, nil
+}
+
+func (s *GitSSHService) GetFileContent(context.Context, string, string, string, string) (string, *github.Response, error) {
+	// File contents aren't available via git ls-remote.
+	return "", nil, errors.New("file contents can't be got via git_ssh")
+}
+
+func (s *GitSSHService) IsImmutableRelease(context.Context, string, string, string) (bool, *github.Response, error) {
+	// Releases aren't available via git, so no release is immutable.
+	return false, &github.Response{}, nil
+}
+
+func (s *GitSSHService) ListAttestations(context.Context, string, string, string, *github.ListOptions) (*github.AttestationsResponse, *github.Response, error) {
+	// Attestations aren't available via git.
+	return nil, nil, errors.New("attestations can't be got via git_ssh")
+}
+
+func (s *GitSSHService) GetCommitDate(context.Context, string, string, string) (time.Time, *github.Response, error) {
+	// Commit dates aren't available via git ls-remote.
+	return time.Time{}, nil, errors.New("commit dates can't be got via git_ssh")
+}
+
+func (s *GitSSHService) Get(_ context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	// Repository metadata such as archived isn't available via git.
+	return &github.Repository{
+		Owner: &github.User{
+			Login: util.StrP(owner),
+		},
+		Name: util.StrP(repo),
+	}, &github.Response{}, nil
+}
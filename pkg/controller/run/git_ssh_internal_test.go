@@ -0,0 +1,253 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+func Test_parseLsRemote(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		out  string
+		exp  []*lsRemoteRef
+	}{
+		{
+			name: "empty",
+			out:  "",
+			exp:  []*lsRemoteRef{},
+		},
+		{
+			name: "lightweight and annotated tags",
+			out: `8ae65de0d6104192b67272275dc638aa2774ab57	refs/tags/v1
+2234b1d16b0bc12fe9cfca7681bf3308cabc1681	refs/tags/v1.0.0
+8ae65de0d6104192b67272275dc638aa2774ab57	refs/tags/v1.0.0^{}
+`,
+			exp: []*lsRemoteRef{
+				{
+					SHA:  "8ae65de0d6104192b67272275dc638aa2774ab57",
+					Name: "refs/tags/v1",
+				},
+				{
+					SHA:  "8ae65de0d6104192b67272275dc638aa2774ab57",
+					Name: "refs/tags/v1.0.0",
+				},
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			refs := parseLsRemote([]byte(d.out))
+			if diff := cmp.Diff(d.exp, refs); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_shellQuote(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		s    string
+		exp  string
+	}{
+		{
+			name: "normal",
+			s:    "/home/foo/.ssh/id_ed25519",
+			exp:  "'/home/foo/.ssh/id_ed25519'",
+		},
+		{
+			name: "space",
+			s:    "/home/foo/my keys/id_ed25519",
+			exp:  "'/home/foo/my keys/id_ed25519'",
+		},
+		{
+			name: "single quote",
+			s:    "/home/foo/it's/id_ed25519",
+			exp:  `'/home/foo/it'\''s/id_ed25519'`,
+		},
+		{
+			name: "shell metacharacters",
+			s:    "/tmp/key; rm -rf $HOME",
+			exp:  "'/tmp/key; rm -rf $HOME'",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if s := shellQuote(d.s); s != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, s)
+			}
+		})
+	}
+}
+
+// fakeLsRemote returns a fake of execLsRemote outputting out.
+// The arguments and the last environment variable GIT_SSH_COMMAND are recorded to args and sshCommand.
+func fakeLsRemote(out string, args *[]string, sshCommand *string) func(context.Context, []string, ...string) ([]byte, error) {
+	return func(_ context.Context, env []string, a ...string) ([]byte, error) {
+		*args = a
+		for _, e := range env {
+			if s, ok := strings.CutPrefix(e, "GIT_SSH_COMMAND="); ok {
+				*sshCommand = s
+			}
+		}
+		return []byte(out), nil
+	}
+}
+
+func TestGitSSHService_GetCommitSHA1(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name       string
+		ref        string
+		out        string
+		exp        string
+		args       []string
+		sshCommand string
+		isErr      bool
+	}{
+		{
+			name: "lightweight tag",
+			ref:  "v1.0.0",
+			out:  "8ae65de0d6104192b67272275dc638aa2774ab57\trefs/tags/v1.0.0\n",
+			exp:  "8ae65de0d6104192b67272275dc638aa2774ab57",
+			args: []string{
+				"git@github.com:suzuki-shunsuke/pinact.git",
+				"refs/tags/v1.0.0", "refs/tags/v1.0.0^{}", "refs/heads/v1.0.0",
+			},
+			sshCommand: `ssh -i '/home/foo/it'\''s/id_ed25519' -o IdentitiesOnly=yes`,
+		},
+		{
+			name: "annotated tag is peeled",
+			ref:  "v1.0.0",
+			out:  "2234b1d16b0bc12fe9cfca7681bf3308cabc1681\trefs/tags/v1.0.0\n8ae65de0d6104192b67272275dc638aa2774ab57\trefs/tags/v1.0.0^{}\n",
+			exp:  "8ae65de0d6104192b67272275dc638aa2774ab57",
+			args: []string{
+				"git@github.com:suzuki-shunsuke/pinact.git",
+				"refs/tags/v1.0.0", "refs/tags/v1.0.0^{}", "refs/heads/v1.0.0",
+			},
+			sshCommand: `ssh -i '/home/foo/it'\''s/id_ed25519' -o IdentitiesOnly=yes`,
+		},
+		{
+			name: "tags are preferred to branches",
+			ref:  "v1",
+			out:  "2234b1d16b0bc12fe9cfca7681bf3308cabc1681\trefs/heads/v1\n8ae65de0d6104192b67272275dc638aa2774ab57\trefs/tags/v1\n",
+			exp:  "8ae65de0d6104192b67272275dc638aa2774ab57",
+			args: []string{
+				"git@github.com:suzuki-shunsuke/pinact.git",
+				"refs/tags/v1", "refs/tags/v1^{}", "refs/heads/v1",
+			},
+			sshCommand: `ssh -i '/home/foo/it'\''s/id_ed25519' -o IdentitiesOnly=yes`,
+		},
+		{
+			name: "branch",
+			ref:  "main",
+			out:  "2234b1d16b0bc12fe9cfca7681bf3308cabc1681\trefs/heads/main\n",
+			exp:  "2234b1d16b0bc12fe9cfca7681bf3308cabc1681",
+			args: []string{
+				"git@github.com:suzuki-shunsuke/pinact.git",
+				"refs/tags/main", "refs/tags/main^{}", "refs/heads/main",
+			},
+			sshCommand: `ssh -i '/home/foo/it'\''s/id_ed25519' -o IdentitiesOnly=yes`,
+		},
+		{
+			name: "full commit hash isn't resolved",
+			ref:  "8ae65de0d6104192b67272275dc638aa2774ab57",
+			exp:  "8ae65de0d6104192b67272275dc638aa2774ab57",
+		},
+		{
+			name:  "not found",
+			ref:   "v2.0.0",
+			isErr: true,
+			args: []string{
+				"git@github.com:suzuki-shunsuke/pinact.git",
+				"refs/tags/v2.0.0", "refs/tags/v2.0.0^{}", "refs/heads/v2.0.0",
+			},
+			sshCommand: `ssh -i '/home/foo/it'\''s/id_ed25519' -o IdentitiesOnly=yes`,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			var args []string
+			var sshCommand string
+			s := NewGitSSHService(&GitSSH{KeyPath: "/home/foo/it's/id_ed25519"})
+			s.execLsRemote = fakeLsRemote(d.out, &args, &sshCommand)
+			sha, _, err := s.GetCommitSHA1(context.Background(), "suzuki-shunsuke", "pinact", d.ref, "")
+			if err != nil {
+				if !d.isErr {
+					t.Fatal(err)
+				}
+			} else if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if sha != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, sha)
+			}
+			if diff := cmp.Diff(d.args, args); diff != "" {
+				t.Fatal(diff)
+			}
+			if sshCommand != d.sshCommand {
+				t.Fatalf("wanted GIT_SSH_COMMAND=%s, got %s", d.sshCommand, sshCommand)
+			}
+		})
+	}
+}
+
+func TestGitSSHService_ListTags(t *testing.T) {
+	t.Parallel()
+	var args []string
+	var sshCommand string
+	s := NewGitSSHService(&GitSSH{Host: "ghes.example.com"})
+	s.execLsRemote = fakeLsRemote(`8ae65de0d6104192b67272275dc638aa2774ab57	refs/tags/v1
+2234b1d16b0bc12fe9cfca7681bf3308cabc1681	refs/tags/v1.0.0
+8ae65de0d6104192b67272275dc638aa2774ab57	refs/tags/v1.0.0^{}
+`, &args, &sshCommand)
+	tags, resp, err := s.ListTags(context.Background(), "suzuki-shunsuke", "pinact", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []*github.RepositoryTag{
+		{
+			Name:   util.StrP("v1"),
+			Commit: &github.Commit{SHA: util.StrP("8ae65de0d6104192b67272275dc638aa2774ab57")},
+		},
+		{
+			Name:   util.StrP("v1.0.0"),
+			Commit: &github.Commit{SHA: util.StrP("8ae65de0d6104192b67272275dc638aa2774ab57")},
+		},
+	}
+	if diff := cmp.Diff(exp, tags); diff != "" {
+		t.Fatal(diff)
+	}
+	if resp.NextPage != 0 {
+		t.Fatalf("git ls-remote returns all tags at once, but the next page is %d", resp.NextPage)
+	}
+	if diff := cmp.Diff([]string{"git@ghes.example.com:suzuki-shunsuke/pinact.git", "refs/tags/*"}, args); diff != "" {
+		t.Fatal(diff)
+	}
+	if sshCommand != os.Getenv("GIT_SSH_COMMAND") {
+		t.Fatalf("GIT_SSH_COMMAND must not be set without key_path: %s", sshCommand)
+	}
+}
+
+func TestGitSSHService_ListTags_error(t *testing.T) {
+	t.Parallel()
+	s := NewGitSSHService(&GitSSH{})
+	s.execLsRemote = func(context.Context, []string, ...string) ([]byte, error) {
+		return nil, errors.New("permission denied")
+	}
+	if _, _, err := s.ListTags(context.Background(), "suzuki-shunsuke", "pinact", nil); err == nil {
+		t.Fatal("error must be returned")
+	}
+}
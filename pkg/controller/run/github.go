@@ -2,32 +2,151 @@ package run
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/logrus-error/logerr"
 	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
 )
 
 type RepositoriesService interface {
 	ListTags(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.RepositoryTag, *github.Response, error)
 	GetCommitSHA1(ctx context.Context, owner, repo, ref, lastSHA string) (string, *github.Response, error)
 	ListReleases(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.RepositoryRelease, *github.Response, error)
+	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
+	ListMatchingTags(ctx context.Context, owner, repo, prefix string) ([]*github.RepositoryTag, *github.Response, error)
+	GetFileContent(ctx context.Context, owner, repo, path, ref string) (string, *github.Response, error)
+	GetCommitDate(ctx context.Context, owner, repo, sha string) (time.Time, *github.Response, error)
+	IsImmutableRelease(ctx context.Context, owner, repo, tag string) (bool, *github.Response, error)
+	ListAttestations(ctx context.Context, owner, repo, subjectDigest string, opts *github.ListOptions) (*github.AttestationsResponse, *github.Response, error)
+}
+
+// maxAnnotatedTags is the maximum number of annotated tags dereferenced by ListMatchingTags.
+// Dereferencing an annotated tag requires an API call, so tags exceeding this are ignored.
+const maxAnnotatedTags = 30
+
+// errTooManyAnnotatedTags is returned by ListMatchingTags with tags if annotated tags exceeding maxAnnotatedTags are ignored.
+var errTooManyAnnotatedTags = errors.New("too many annotated tags match the prefix")
+
+// GitHubRepositoriesService is a RepositoriesService using GitHub REST API.
+type GitHubRepositoriesService struct {
+	*github.RepositoriesService
+	git    *github.GitService
+	client *github.Client
+}
+
+func NewGitHubRepositoriesService(gh *github.Client) *GitHubRepositoriesService {
+	return &GitHubRepositoriesService{
+		RepositoriesService: gh.Repositories,
+		git:                 gh.Git,
+		client:              gh,
+	}
+}
+
+// ListMatchingTags returns tags whose names start with the prefix.
+// Unlike ListTags, it doesn't need to paginate all tags, so it works well for repositories with a huge number of tags.
+// Annotated tags are dereferenced so that the commit hash of each tag is returned.
+// If annotated tags exceeding maxAnnotatedTags are ignored, the other tags are returned with errTooManyAnnotatedTags.
+func (s *GitHubRepositoriesService) ListMatchingTags(ctx context.Context, owner, repo, prefix string) ([]*github.RepositoryTag, *github.Response, error) {
+	opts := &github.ReferenceListOptions{
+		Ref: "tags/" + prefix,
+		ListOptions: github.ListOptions{
+			PerPage: 100, //nolint:mnd
+		},
+	}
+	var refs []*github.Reference
+	var resp *github.Response
+	for {
+		rs, r, err := s.git.ListMatchingRefs(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, r, fmt.Errorf("list matching refs: %w", err)
+		}
+		refs = append(refs, rs...)
+		resp = r
+		if r.NextPage == 0 {
+			break
+		}
+		opts.Page = r.NextPage
+	}
+	tags := make([]*github.RepositoryTag, 0, len(refs))
+	annotated := 0
+	truncated := false
+	for _, ref := range refs {
+		sha := ref.GetObject().GetSHA()
+		if ref.GetObject().GetType() == "tag" {
+			if annotated == maxAnnotatedTags {
+				truncated = true
+				continue
+			}
+			annotated++
+			tag, _, err := s.git.GetTag(ctx, owner, repo, sha)
+			if err != nil {
+				return nil, resp, fmt.Errorf("get an annotated tag: %w", err)
+			}
+			sha = tag.GetObject().GetSHA()
+		}
+		tags = append(tags, &github.RepositoryTag{
+			Name: util.StrP(strings.TrimPrefix(ref.GetRef(), "refs/tags/")),
+			Commit: &github.Commit{
+				SHA: util.StrP(sha),
+			},
+		})
+	}
+	if truncated {
+		return tags, resp, errTooManyAnnotatedTags
+	}
+	return tags, resp, nil
+}
+
+// GetFileContent returns the content of a file at the ref.
+func (s *GitHubRepositoriesService) GetFileContent(ctx context.Context, owner, repo, path, ref string) (string, *github.Response, error) {
+	file, _, resp, err := s.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{
+		Ref: ref,
+	})
+	if err != nil {
+		return "", resp, fmt.Errorf("get a file content: %w", err)
+	}
+	if file == nil {
+		return "", resp, fmt.Errorf("the path isn't a file: %s", path)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return "", resp, fmt.Errorf("decode a file content: %w", err)
+	}
+	return content, resp, nil
+}
+
+// GetCommitDate returns the date when the commit was committed.
+func (s *GitHubRepositoriesService) GetCommitDate(ctx context.Context, owner, repo, sha string) (time.Time, *github.Response, error) {
+	commit, resp, err := s.GetCommit(ctx, owner, repo, sha, nil)
+	if err != nil {
+		return time.Time{}, resp, fmt.Errorf("get a commit: %w", err)
+	}
+	return commit.GetCommit().GetCommitter().GetDate().Time, resp, nil
 }
 
 func (r *RepositoriesServiceImpl) GetCommitSHA1(ctx context.Context, owner, repo, ref, lastSHA string) (string, *github.Response, error) {
 	key := fmt.Sprintf("%s/%s/%s", owner, repo, ref)
 	a, ok := r.commits[key]
-	if ok {
+	if ok && !a.isExpired(r.getNow()) {
 		return a.SHA, a.Response, a.err
 	}
 	sha, resp, err := r.RepositoriesService.GetCommitSHA1(ctx, owner, repo, ref, lastSHA)
-	r.commits[key] = &GetCommitSHA1Result{
+	result := &GetCommitSHA1Result{
 		SHA:      sha,
 		Response: resp,
 		err:      err,
 	}
+	if github.IsNotFound(err) {
+		// Missing references are cached with the short TTL so that they aren't retried for every occurrence.
+		result.notFoundAt = r.getNow()
+	}
+	r.commits[key] = result
 	return sha, resp, err //nolint:wrapcheck
 }
 
@@ -43,17 +162,62 @@ type ListReleasesResult struct {
 	err      error
 }
 
+type ListMatchingTagsResult struct {
+	Tags     []*github.RepositoryTag
+	Response *github.Response
+	err      error
+}
+
+type GetFileContentResult struct {
+	Content  string
+	Response *github.Response
+	err      error
+}
+
+type GetCommitDateResult struct {
+	Date     time.Time
+	Response *github.Response
+	err      error
+}
+
+type IsImmutableReleaseResult struct {
+	Immutable bool
+	Response  *github.Response
+	err       error
+}
+
+type ListAttestationsResult struct {
+	Attestations *github.AttestationsResponse
+	Response     *github.Response
+	err          error
+}
+
+type GetRepositoryResult struct {
+	Repository *github.Repository
+	Response   *github.Response
+	err        error
+}
+
 type RepositoriesServiceImpl struct {
 	RepositoriesService RepositoriesService
 	tags                map[string]*ListTagsResult
 	commits             map[string]*GetCommitSHA1Result
 	releases            map[string]*ListReleasesResult
+	repos               map[string]*GetRepositoryResult
+	matchingTags        map[string]*ListMatchingTagsResult
+	contents            map[string]*GetFileContentResult
+	commitDates         map[string]*GetCommitDateResult
+	immutableReleases   map[string]*IsImmutableReleaseResult
+	attestations        map[string]*ListAttestationsResult
+	now                 func() time.Time
 }
 
 type GetCommitSHA1Result struct {
 	SHA      string
 	Response *github.Response
 	err      error
+	// notFoundAt is the time when the reference was found missing. It's zero unless the reference is missing.
+	notFoundAt time.Time
 }
 
 func (r *RepositoriesServiceImpl) ListTags(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.RepositoryTag, *github.Response, error) {
@@ -86,15 +250,128 @@ func (r *RepositoriesServiceImpl) ListReleases(ctx context.Context, owner string
 	return releases, resp, err //nolint:wrapcheck
 }
 
-func (c *Controller) getLatestVersion(ctx context.Context, logE *logrus.Entry, owner string, repo string) (string, error) {
-	lv, err := c.getLatestVersionFromReleases(ctx, logE, owner, repo)
+func (r *RepositoriesServiceImpl) Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	key := fmt.Sprintf("%s/%s", owner, repo)
+	a, ok := r.repos[key]
+	if ok {
+		return a.Repository, a.Response, a.err
+	}
+	repository, resp, err := r.RepositoriesService.Get(ctx, owner, repo)
+	r.repos[key] = &GetRepositoryResult{
+		Repository: repository,
+		Response:   resp,
+		err:        err,
+	}
+	return repository, resp, err //nolint:wrapcheck
+}
+
+func (r *RepositoriesServiceImpl) ListMatchingTags(ctx context.Context, owner, repo, prefix string) ([]*github.RepositoryTag, *github.Response, error) {
+	key := fmt.Sprintf("%s/%s/%s", owner, repo, prefix)
+	a, ok := r.matchingTags[key]
+	if ok {
+		return a.Tags, a.Response, a.err
+	}
+	tags, resp, err := r.RepositoriesService.ListMatchingTags(ctx, owner, repo, prefix)
+	r.matchingTags[key] = &ListMatchingTagsResult{
+		Tags:     tags,
+		Response: resp,
+		err:      err,
+	}
+	return tags, resp, err //nolint:wrapcheck
+}
+
+func (r *RepositoriesServiceImpl) GetFileContent(ctx context.Context, owner, repo, path, ref string) (string, *github.Response, error) {
+	key := fmt.Sprintf("%s/%s/%s@%s", owner, repo, path, ref)
+	a, ok := r.contents[key]
+	if ok {
+		return a.Content, a.Response, a.err
+	}
+	content, resp, err := r.RepositoriesService.GetFileContent(ctx, owner, repo, path, ref)
+	r.contents[key] = &GetFileContentResult{
+		Content:  content,
+		Response: resp,
+		err:      err,
+	}
+	return content, resp, err //nolint:wrapcheck
+}
+
+func (r *RepositoriesServiceImpl) GetCommitDate(ctx context.Context, owner, repo, sha string) (time.Time, *github.Response, error) {
+	key := fmt.Sprintf("%s/%s/%s", owner, repo, sha)
+	a, ok := r.commitDates[key]
+	if ok {
+		return a.Date, a.Response, a.err
+	}
+	date, resp, err := r.RepositoriesService.GetCommitDate(ctx, owner, repo, sha)
+	r.commitDates[key] = &GetCommitDateResult{
+		Date:     date,
+		Response: resp,
+		err:      err,
+	}
+	return date, resp, err //nolint:wrapcheck
+}
+
+func (r *RepositoriesServiceImpl) IsImmutableRelease(ctx context.Context, owner, repo, tag string) (bool, *github.Response, error) {
+	key := fmt.Sprintf("%s/%s/%s", owner, repo, tag)
+	a, ok := r.immutableReleases[key]
+	if ok {
+		return a.Immutable, a.Response, a.err
+	}
+	immutable, resp, err := r.RepositoriesService.IsImmutableRelease(ctx, owner, repo, tag)
+	r.immutableReleases[key] = &IsImmutableReleaseResult{
+		Immutable: immutable,
+		Response:  resp,
+		err:       err,
+	}
+	return immutable, resp, err //nolint:wrapcheck
+}
+
+func (r *RepositoriesServiceImpl) ListAttestations(ctx context.Context, owner, repo, subjectDigest string, opts *github.ListOptions) (*github.AttestationsResponse, *github.Response, error) {
+	key := fmt.Sprintf("%s/%s/%s", owner, repo, subjectDigest)
+	a, ok := r.attestations[key]
+	if ok {
+		return a.Attestations, a.Response, a.err
+	}
+	attestations, resp, err := r.RepositoriesService.ListAttestations(ctx, owner, repo, subjectDigest, opts)
+	r.attestations[key] = &ListAttestationsResult{
+		Attestations: attestations,
+		Response:     resp,
+		err:          err,
+	}
+	return attestations, resp, err //nolint:wrapcheck
+}
+
+// getLatestVersion returns the latest version of the action.
+// If immutable_releases is enabled, the latest immutable release is preferred.
+// Versions released within the cooldown of the repository are skipped.
+// If any version is skipped by the cooldown, the update is counted as skipped by the cooldown.
+func (c *Controller) getLatestVersion(ctx context.Context, logE *logrus.Entry, cfg *Config, owner string, repo string) (string, error) {
+	cooledDownVersions := cfg.cooledDownVersions
+	v, err := c.getLatestVersionWithCooldown(ctx, logE, cfg, owner, repo)
+	if cfg.cooledDownVersions != cooledDownVersions {
+		cfg.skipUpdate(skipReasonCooldown)
+	}
+	return v, err
+}
+
+func (c *Controller) getLatestVersionWithCooldown(ctx context.Context, logE *logrus.Entry, cfg *Config, owner string, repo string) (string, error) {
+	cooldown := cfg.getCooldown(owner, repo)
+	if cfg.ImmutableReleases {
+		v, err := c.getLatestImmutableVersion(ctx, logE, cfg, owner, repo, cooldown)
+		if err != nil {
+			logerr.WithError(logE, err).Debug("get the latest immutable release")
+		}
+		if v != "" {
+			return v, nil
+		}
+	}
+	lv, err := c.getLatestVersionFromReleases(ctx, logE, cfg, owner, repo, cooldown)
 	if err != nil {
 		logerr.WithError(logE, err).Debug("get the latest version from releases")
 	}
 	if lv != "" {
 		return lv, nil
 	}
-	return c.getLatestVersionFromTags(ctx, logE, owner, repo)
+	return c.getLatestVersionFromTags(ctx, logE, cfg, owner, repo, cooldown)
 }
 
 func compare(latestSemver *version.Version, latestVersion, tag string) (*version.Version, string, error) {
@@ -114,7 +391,7 @@ func compare(latestSemver *version.Version, latestVersion, tag string) (*version
 	return v, "", nil
 }
 
-func (c *Controller) getLatestVersionFromReleases(ctx context.Context, logE *logrus.Entry, owner string, repo string) (string, error) {
+func (c *Controller) getLatestVersionFromReleases(ctx context.Context, logE *logrus.Entry, cfg *Config, owner string, repo string, cooldown time.Duration) (string, error) {
 	opts := &github.ListOptions{
 		PerPage: 30, //nolint:mnd
 	}
@@ -126,6 +403,11 @@ func (c *Controller) getLatestVersionFromReleases(ctx context.Context, logE *log
 	latestVersion := ""
 	for _, release := range releases {
 		tag := release.GetTagName()
+		if cfg.isCoolingDown(cooldown, release.GetPublishedAt().Time) {
+			logE.WithField("tag", tag).Debug("skip a release in the cooldown")
+			cfg.cooledDownVersions++
+			continue
+		}
 		ls, lv, err := compare(latestSemver, latestVersion, tag)
 		latestSemver = ls
 		latestVersion = lv
@@ -140,7 +422,7 @@ func (c *Controller) getLatestVersionFromReleases(ctx context.Context, logE *log
 	return latestVersion, nil
 }
 
-func (c *Controller) getLatestVersionFromTags(ctx context.Context, logE *logrus.Entry, owner string, repo string) (string, error) {
+func (c *Controller) getLatestVersionFromTags(ctx context.Context, logE *logrus.Entry, cfg *Config, owner string, repo string, cooldown time.Duration) (string, error) {
 	opts := &github.ListOptions{
 		PerPage: 30, //nolint:mnd
 	}
@@ -152,6 +434,12 @@ func (c *Controller) getLatestVersionFromTags(ctx context.Context, logE *logrus.
 	latestVersion := ""
 	for _, tag := range tags {
 		t := tag.GetName()
+		// Commit dates are fetched only for candidates of the latest version to reduce API calls.
+		if latestSemver == nil && c.isTagCoolingDown(ctx, logE, cfg, owner, repo, tag, cooldown) {
+			logE.WithField("tag", t).Debug("skip a tag in the cooldown")
+			cfg.cooledDownVersions++
+			continue
+		}
 		ls, lv, err := compare(latestSemver, latestVersion, t)
 		latestSemver = ls
 		latestVersion = lv
@@ -0,0 +1,75 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+func TestGitHubRepositoriesService_ListMatchingTags(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("/repos/suzuki-shunsuke/pinact/git/matching-refs/tags/v1", func(w http.ResponseWriter, r *http.Request) {
+		if perPage := r.URL.Query().Get("per_page"); perPage != "100" {
+			t.Errorf("per_page must be 100: %s", perPage)
+		}
+		refs := []map[string]any{}
+		if r.URL.Query().Get("page") != "2" {
+			// The first page has a lightweight tag.
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2&per_page=100>; rel="next"`, server.URL, r.URL.Path))
+			refs = append(refs, map[string]any{
+				"ref":    "refs/tags/v1.0.0",
+				"object": map[string]any{"type": "commit", "sha": "commit-v1.0.0"},
+			})
+		} else {
+			// The second page has annotated tags exceeding maxAnnotatedTags.
+			for i := range maxAnnotatedTags + 1 {
+				refs = append(refs, map[string]any{
+					"ref":    fmt.Sprintf("refs/tags/v1.1.%d", i),
+					"object": map[string]any{"type": "tag", "sha": fmt.Sprintf("tag-v1.1.%d", i)},
+				})
+			}
+		}
+		if err := json.NewEncoder(w).Encode(refs); err != nil {
+			t.Error(err)
+		}
+	})
+	mux.HandleFunc("/repos/suzuki-shunsuke/pinact/git/tags/", func(w http.ResponseWriter, r *http.Request) {
+		sha := strings.TrimPrefix(r.URL.Path, "/repos/suzuki-shunsuke/pinact/git/tags/")
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"sha":    sha,
+			"object": map[string]any{"type": "commit", "sha": "commit-" + strings.TrimPrefix(sha, "tag-")},
+		}); err != nil {
+			t.Error(err)
+		}
+	})
+	gh, err := github.New(context.Background(), &github.InputNew{
+		APIURL: server.URL,
+		Token:  "dummy",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, _, err := NewGitHubRepositoriesService(gh).ListMatchingTags(context.Background(), "suzuki-shunsuke", "pinact", "v1")
+	if !errors.Is(err, errTooManyAnnotatedTags) {
+		t.Fatalf("wanted errTooManyAnnotatedTags, got %v", err)
+	}
+	if len(tags) != maxAnnotatedTags+1 {
+		t.Fatalf("wanted %d tags, got %d", maxAnnotatedTags+1, len(tags))
+	}
+	if tags[0].GetName() != "v1.0.0" || tags[0].GetCommit().GetSHA() != "commit-v1.0.0" {
+		t.Fatalf("the lightweight tag is wrong: %s %s", tags[0].GetName(), tags[0].GetCommit().GetSHA())
+	}
+	if tags[1].GetName() != "v1.1.0" || tags[1].GetCommit().GetSHA() != "commit-v1.1.0" {
+		t.Fatalf("the annotated tag isn't dereferenced: %s %s", tags[1].GetName(), tags[1].GetCommit().GetSHA())
+	}
+}
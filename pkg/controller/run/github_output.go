@@ -0,0 +1,80 @@
+package run
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// gitHubOutput is results of a run written to GITHUB_OUTPUT.
+// Downstream steps such as creating a pull request can branch on them without parsing logs.
+// changed and changed_files are files actually written, so they're empty if --format is set without --fix.
+// would_change is true if pinact would change files regardless of whether they're written.
+type gitHubOutput struct {
+	ChangedFiles []string
+	WouldChange  bool
+	Findings     int
+}
+
+func newGitHubOutput(pwd string, changes []*workflowChange, wouldChange bool, findings int) *gitHubOutput {
+	out := &gitHubOutput{
+		ChangedFiles: make([]string, len(changes)),
+		WouldChange:  wouldChange,
+		Findings:     findings,
+	}
+	for i, change := range changes {
+		path := change.Path
+		if rel, err := filepath.Rel(pwd, path); err == nil {
+			path = rel
+		}
+		out.ChangedFiles[i] = filepath.ToSlash(path)
+	}
+	return out
+}
+
+// format formats outputs in the format of GITHUB_OUTPUT.
+// changed_files is a multiline value, so it's enclosed by the delimiter.
+// https://docs.github.com/en/actions/writing-workflows/choosing-what-your-workflow-does/workflow-commands-for-github-actions#multiline-strings
+func (o *gitHubOutput) format(delimiter string) string {
+	return fmt.Sprintf("changed=%s\nchanged_files<<%s\n%s%s\nwould_change=%s\nfindings=%d\n",
+		strconv.FormatBool(len(o.ChangedFiles) != 0),
+		delimiter, joinLines(o.ChangedFiles), delimiter,
+		strconv.FormatBool(o.WouldChange),
+		o.Findings)
+}
+
+func joinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// newDelimiter returns a random delimiter so that values can't inject outputs.
+func newDelimiter() (string, error) {
+	b := make([]byte, 16) //nolint:mnd
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate a delimiter: %w", err)
+	}
+	return "PINACT_" + hex.EncodeToString(b), nil
+}
+
+func (c *Controller) writeGitHubOutput(outputFilePath string, out *gitHubOutput) error {
+	delimiter, err := newDelimiter()
+	if err != nil {
+		return err
+	}
+	f, err := c.fs.OpenFile(outputFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePermission)
+	if err != nil {
+		return fmt.Errorf("open GITHUB_OUTPUT: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(out.format(delimiter)); err != nil {
+		return fmt.Errorf("write outputs to GITHUB_OUTPUT: %w", err)
+	}
+	return nil
+}
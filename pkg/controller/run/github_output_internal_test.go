@@ -0,0 +1,158 @@
+package run
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+func Test_gitHubOutput_format(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name        string
+		changes     []*workflowChange
+		wouldChange bool
+		exp         string
+	}{
+		{
+			name: "no change",
+			exp:  "changed=false\nchanged_files<<EOF\nEOF\nwould_change=false\nfindings=3\n",
+		},
+		{
+			name: "changed",
+			changes: []*workflowChange{
+				{Path: "/home/foo/repo/.github/workflows/test.yaml"},
+				{Path: "/home/foo/repo/action.yaml"},
+			},
+			wouldChange: true,
+			exp:         "changed=true\nchanged_files<<EOF\n.github/workflows/test.yaml\naction.yaml\nEOF\nwould_change=true\nfindings=3\n",
+		},
+		{
+			name:        "files would be changed but aren't written",
+			wouldChange: true,
+			exp:         "changed=false\nchanged_files<<EOF\nEOF\nwould_change=true\nfindings=3\n",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if s := newGitHubOutput("/home/foo/repo", d.changes, d.wouldChange, 3).format("EOF"); s != d.exp {
+				t.Fatalf("wanted %q, got %q", d.exp, s)
+			}
+		})
+	}
+}
+
+func TestController_writeGitHubOutput(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "github_output", []byte("foo=bar\n"), filePermission); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := NewController(nil, fs)
+	if err := ctrl.writeGitHubOutput("github_output", &gitHubOutput{Findings: 1}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := afero.ReadFile(fs, "github_output")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(b)
+	if !strings.HasPrefix(s, "foo=bar\nchanged=false\nchanged_files<<PINACT_") || !strings.HasSuffix(s, "\nwould_change=false\nfindings=1\n") {
+		t.Fatalf("outputs aren't appended: %q", s)
+	}
+}
+
+func TestController_Run_gitHubOutput(t *testing.T) {
+	t.Parallel()
+	const before = "      - uses: actions/checkout@v2"
+	data := []struct {
+		name    string
+		fix     bool
+		content string
+		outputs string
+	}{
+		{
+			name:    "check only",
+			content: before,
+			outputs: "changed=false\nchanged_files<<EOF\nEOF\nwould_change=true\n",
+		},
+		{
+			name:    "fix",
+			fix:     true,
+			content: "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+			outputs: "changed=true\nchanged_files<<EOF\n.github/workflows/test.yaml\nEOF\nwould_change=true\n",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			pwd := t.TempDir()
+			workflowFilePath := filepath.Join(pwd, ".github", "workflows", "test.yaml")
+			if err := os.MkdirAll(filepath.Dir(workflowFilePath), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(workflowFilePath, []byte("jobs:\n  test:\n    steps:\n"+before+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			ctrl := NewController(&RepositoriesServiceImpl{
+				tags: map[string]*ListTagsResult{
+					"actions/checkout/0": {
+						Tags: []*github.RepositoryTag{
+							{
+								Name: util.StrP("v2.7.0"),
+								Commit: &github.Commit{
+									SHA: util.StrP("ee0669bd1cc54295c223e0bb666b733df41de1c5"),
+								},
+							},
+						},
+						Response: &github.Response{},
+					},
+				},
+				commits: map[string]*GetCommitSHA1Result{
+					"actions/checkout/v2": {
+						SHA: "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+					},
+				},
+			}, afero.NewOsFs())
+			outputFilePath := filepath.Join(pwd, "github_output")
+			if err := ctrl.Run(context.Background(), logrus.NewEntry(logrus.New()), &ParamRun{
+				WorkflowFilePaths:    []string{workflowFilePath},
+				PWD:                  pwd,
+				Format:               FormatJSON,
+				Fix:                  d.fix,
+				Stdout:               io.Discard,
+				GitHubOutputFilePath: outputFilePath,
+				NoState:              true,
+				NoLock:               true,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			b, err := os.ReadFile(workflowFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if content := strings.Split(string(b), "\n")[3]; content != d.content {
+				t.Fatalf("wanted %s, got %s", d.content, content)
+			}
+			b, err = os.ReadFile(outputFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The delimiter is random.
+			outputs := regexp.MustCompile(`PINACT_[0-9a-f]+`).ReplaceAllString(string(b), "EOF")
+			if outputs, ok := strings.CutSuffix(outputs, "findings=1\n"); !ok || outputs != d.outputs {
+				t.Fatalf("wanted %q, got %q", d.outputs, outputs)
+			}
+		})
+	}
+}
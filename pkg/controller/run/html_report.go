@@ -0,0 +1,142 @@
+package run
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+//go:embed html_report.html.tmpl
+var htmlReportTemplate string
+
+// htmlReport is a standalone HTML report of a run.
+// Users can share results of audits with people who don't use CLI.
+type htmlReport struct {
+	Stats    *Stats
+	Findings []*htmlReportFinding
+	Actions  []*htmlReportAction
+	Kinds    []string
+}
+
+type htmlReportFinding struct {
+	Kind    string
+	File    string
+	Line    int
+	Message string
+	Before  string
+	After   string
+}
+
+// htmlReportAction is a summary of changes of an action.
+// Actions without changes aren't listed.
+type htmlReportAction struct {
+	Name            string
+	Lines           int
+	OldVersions     []string
+	NewVersions     []string
+	UpdateAvailable bool
+}
+
+// getReportVersion returns the version annotation of an action, or the version if it isn't annotated.
+func getReportVersion(action *Action) string {
+	if action.Tag != "" {
+		return action.Tag
+	}
+	return action.Version
+}
+
+// newHTMLReport converts findings to a report.
+// File paths are converted to paths relative to pwd.
+func newHTMLReport(pwd string, stats *Stats, findings []*Finding) *htmlReport {
+	report := &htmlReport{
+		Stats:    stats,
+		Findings: make([]*htmlReportFinding, 0, len(findings)),
+	}
+	actions := map[string]*htmlReportAction{}
+	for _, finding := range findings {
+		path := finding.File
+		if filepath.IsAbs(path) {
+			if rel, err := filepath.Rel(pwd, path); err == nil {
+				path = rel
+			}
+		}
+		report.Findings = append(report.Findings, &htmlReportFinding{
+			Kind:    finding.Kind,
+			File:    filepath.ToSlash(path),
+			Line:    finding.Line,
+			Message: getFindingMessage(finding),
+			Before:  finding.Before,
+			After:   finding.After,
+		})
+		if !slices.Contains(report.Kinds, finding.Kind) {
+			report.Kinds = append(report.Kinds, finding.Kind)
+		}
+		if finding.Kind != FindingKindChanged {
+			continue
+		}
+		before := parseAction(finding.Before)
+		after := parseAction(finding.After)
+		if before == nil || after == nil {
+			continue
+		}
+		a, ok := actions[after.Name]
+		if !ok {
+			a = &htmlReportAction{
+				Name: after.Name,
+			}
+			actions[after.Name] = a
+		}
+		a.Lines++
+		oldVersion := getReportVersion(before)
+		newVersion := getReportVersion(after)
+		if !slices.Contains(a.OldVersions, oldVersion) {
+			a.OldVersions = append(a.OldVersions, oldVersion)
+		}
+		if !slices.Contains(a.NewVersions, newVersion) {
+			a.NewVersions = append(a.NewVersions, newVersion)
+		}
+		if before.Tag != "" && before.Tag != after.Tag {
+			a.UpdateAvailable = true
+		}
+	}
+	report.Actions = make([]*htmlReportAction, 0, len(actions))
+	for _, a := range actions {
+		report.Actions = append(report.Actions, a)
+	}
+	slices.SortFunc(report.Actions, func(a, b *htmlReportAction) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.Sort(report.Kinds)
+	return report
+}
+
+func (r *htmlReport) render() ([]byte, error) {
+	tpl, err := template.New("report").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(htmlReportTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parse the template of HTML reports: %w", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, r); err != nil {
+		return nil, fmt.Errorf("render a HTML report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *Controller) writeHTMLReport(reportFilePath, pwd string, stats *Stats, findings []*Finding) error {
+	b, err := newHTMLReport(pwd, stats, findings).render()
+	if err != nil {
+		return err
+	}
+	if err := afero.WriteFile(c.fs, reportFilePath, b, filePermission); err != nil {
+		return fmt.Errorf("write a HTML report: %w", err)
+	}
+	return nil
+}
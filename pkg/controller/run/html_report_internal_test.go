@@ -0,0 +1,71 @@
+package run
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_newHTMLReport(t *testing.T) {
+	t.Parallel()
+	stats := &Stats{
+		Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	findings := []*Finding{
+		{
+			Kind:   FindingKindChanged,
+			File:   "/home/foo/repo/.github/workflows/test.yaml",
+			Line:   4,
+			Before: "      - uses: actions/checkout@v2",
+			After:  "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+		},
+		{
+			Kind:   FindingKindChanged,
+			File:   "/home/foo/repo/.github/workflows/test.yaml",
+			Line:   8,
+			Before: "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+			After:  "      - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2",
+		},
+		{
+			Kind:    FindingKindVulnerability,
+			File:    "/home/foo/repo/.github/workflows/test.yaml",
+			Line:    10,
+			Message: "<script>alert(1)</script>",
+		},
+	}
+	report := newHTMLReport("/home/foo/repo", stats, findings)
+	exp := []*htmlReportAction{
+		{
+			Name:            "actions/checkout",
+			Lines:           2,
+			OldVersions:     []string{"v2", "v2.7.0"},
+			NewVersions:     []string{"v2.7.0", "v3.5.2"},
+			UpdateAvailable: true,
+		},
+	}
+	if diff := cmp.Diff(exp, report.Actions); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := cmp.Diff([]string{FindingKindChanged, FindingKindVulnerability}, report.Kinds); diff != "" {
+		t.Fatal(diff)
+	}
+	if report.Findings[0].File != ".github/workflows/test.yaml" {
+		t.Fatalf("the file path isn't relative: %s", report.Findings[0].File)
+	}
+	b, err := report.render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(b)
+	for _, s := range []string{
+		"2025-01-02 03:04:05 UTC",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		`<option value="vulnerability">vulnerability</option>`,
+	} {
+		if !strings.Contains(html, s) {
+			t.Fatalf("the report doesn't contain %s", s)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+// immutableSuffix is appended to version annotations of immutable releases.
+// e.g. # v4.2.1 (immutable)
+const immutableSuffix = " (immutable)"
+
+// maxImmutableReleaseChecks is the maximum number of releases checked to find the latest immutable release.
+// Checking a release requires an API call.
+const maxImmutableReleaseChecks = 5
+
+type immutableRelease struct {
+	Immutable bool `json:"immutable"`
+}
+
+// IsImmutableRelease returns true if the release of the tag is immutable.
+// If the tag has no release, it returns false.
+// https://docs.github.com/en/code-security/supply-chain-security/understanding-your-software-supply-chain/immutable-releases
+func (s *GitHubRepositoriesService) IsImmutableRelease(ctx context.Context, owner, repo, tag string) (bool, *github.Response, error) {
+	req, err := s.client.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/releases/tags/%s", owner, repo, url.PathEscape(tag)), nil)
+	if err != nil {
+		return false, nil, fmt.Errorf("create a request: %w", err)
+	}
+	release := &immutableRelease{}
+	resp, err := s.client.Do(ctx, req, release)
+	if err != nil {
+		if github.IsNotFound(err) {
+			return false, resp, nil
+		}
+		return false, resp, fmt.Errorf("get a release by tag: %w", err)
+	}
+	return release.Immutable, resp, nil
+}
+
+// isImmutableRelease returns true if the release of the tag is immutable.
+// Errors are logged and treated as mutable.
+func (c *Controller) isImmutableRelease(ctx context.Context, logE *logrus.Entry, action *Action, tag string) bool {
+	immutable, _, err := c.repositoriesService.IsImmutableRelease(ctx, action.RepoOwner, action.RepoName, tag)
+	if err != nil {
+		logerr.WithError(logE, err).WithField("tag", tag).Warn("check if the release is immutable")
+		return false
+	}
+	return immutable
+}
+
+// getLatestImmutableVersion returns the latest semver release which is immutable.
+// Releases are checked in descending order up to maxImmutableReleaseChecks.
+// If no release is immutable, it returns an empty string.
+func (c *Controller) getLatestImmutableVersion(ctx context.Context, logE *logrus.Entry, cfg *Config, owner, repo string, cooldown time.Duration) (string, error) {
+	releases, _, err := c.repositoriesService.ListReleases(ctx, owner, repo, &github.ListOptions{
+		PerPage: 30, //nolint:mnd
+	})
+	if err != nil {
+		return "", fmt.Errorf("list releases: %w", err)
+	}
+	versions := make([]*version.Version, 0, len(releases))
+	for _, release := range releases {
+		if release.GetDraft() || release.GetPrerelease() {
+			continue
+		}
+		if cfg.isCoolingDown(cooldown, release.GetPublishedAt().Time) {
+			cfg.cooledDownVersions++
+			continue
+		}
+		v, err := version.NewVersion(release.GetTagName())
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	slices.SortFunc(versions, func(a, b *version.Version) int {
+		return b.Compare(a)
+	})
+	action := &Action{
+		RepoOwner: owner,
+		RepoName:  repo,
+	}
+	for _, v := range versions[:min(len(versions), maxImmutableReleaseChecks)] {
+		if c.isImmutableRelease(ctx, logE, action, v.Original()) {
+			return v.Original(), nil
+		}
+	}
+	return "", nil
+}
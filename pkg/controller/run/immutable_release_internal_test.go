@@ -0,0 +1,111 @@
+package run
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+func newImmutableReleaseTestController() *Controller {
+	return NewController(&RepositoriesServiceImpl{
+		releases: map[string]*ListReleasesResult{
+			"actions/checkout/0": {
+				Releases: []*github.RepositoryRelease{
+					{TagName: util.StrP("v5.0.0-beta"), Prerelease: util.BoolP(true)},
+					{TagName: util.StrP("v4.2.2")},
+					{TagName: util.StrP("v4.2.1")},
+					{TagName: util.StrP("v4.2.0")},
+				},
+				Response: &github.Response{},
+			},
+		},
+		immutableReleases: map[string]*IsImmutableReleaseResult{
+			"actions/checkout/v4.2.2": {Immutable: false},
+			"actions/checkout/v4.2.1": {Immutable: true},
+			"actions/checkout/v4.2.0": {Immutable: true},
+		},
+	}, afero.NewMemMapFs())
+}
+
+func TestController_getLatestImmutableVersion(t *testing.T) {
+	t.Parallel()
+	ctrl := newImmutableReleaseTestController()
+	v, err := ctrl.getLatestImmutableVersion(context.Background(), logrus.NewEntry(logrus.New()), &Config{}, "actions", "checkout", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "v4.2.1" {
+		t.Fatalf("wanted v4.2.1, got %s", v)
+	}
+}
+
+func TestController_patchVersion(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		line string
+		sha  string
+		tag  string
+		cfg  *Config
+		exp  string
+	}{
+		{
+			name: "immutable",
+			line: "  - uses: actions/checkout@v4",
+			sha:  "9a9194f87191a7e9055e3e9b95b8cfb13023bb08",
+			tag:  "v4.2.1",
+			cfg:  &Config{ImmutableReleases: true},
+			exp:  "  - uses: actions/checkout@9a9194f87191a7e9055e3e9b95b8cfb13023bb08 # v4.2.1 (immutable)",
+		},
+		{
+			name: "mutable",
+			line: "  - uses: actions/checkout@9a9194f87191a7e9055e3e9b95b8cfb13023bb08 # v4.2.1 (immutable) # comment",
+			sha:  "11bd71901bbe5b1630ceea73d27597364c9af683",
+			tag:  "v4.2.2",
+			cfg:  &Config{ImmutableReleases: true},
+			exp:  "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2 # comment",
+		},
+		{
+			name: "provenance",
+			line: "  - uses: actions/checkout@v4",
+			sha:  "9a9194f87191a7e9055e3e9b95b8cfb13023bb08",
+			tag:  "v4.2.1",
+			cfg:  &Config{ImmutableReleases: true, Provenance: true},
+			exp:  "  - uses: actions/checkout@9a9194f87191a7e9055e3e9b95b8cfb13023bb08 # v4.2.1 (immutable) via github.com",
+		},
+		{
+			name: "update provenance",
+			line: "  - uses: actions/checkout@9a9194f87191a7e9055e3e9b95b8cfb13023bb08 # v4.2.1 (immutable) via ghes.example.com # comment",
+			sha:  "11bd71901bbe5b1630ceea73d27597364c9af683",
+			tag:  "v4.2.2",
+			cfg:  &Config{ImmutableReleases: true, Provenance: true},
+			exp:  "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2 via github.com # comment",
+		},
+		{
+			name: "disabled",
+			line: "  - uses: actions/checkout@v4",
+			sha:  "9a9194f87191a7e9055e3e9b95b8cfb13023bb08",
+			tag:  "v4.2.1",
+			cfg:  &Config{},
+			exp:  "  - uses: actions/checkout@9a9194f87191a7e9055e3e9b95b8cfb13023bb08 # v4.2.1",
+		},
+	}
+	ctrl := newImmutableReleaseTestController()
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			action := parseAction(d.line)
+			if !ctrl.parseActionName(action) {
+				t.Fatal("failed to parse the action name")
+			}
+			if got := ctrl.patchVersion(context.Background(), logE, d.cfg, action, d.sha, d.tag); got != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, got)
+			}
+		})
+	}
+}
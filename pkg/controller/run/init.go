@@ -3,17 +3,31 @@ package run
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/afero"
 )
 
 const (
+	// templateConfig is the configuration created by pinact init.
+	// Each field has a comment explaining why the field exists with a link to the document, so the file is self-documenting.
 	templateConfig = `# yaml-language-server: $schema=https://raw.githubusercontent.com/suzuki-shunsuke/pinact/refs/heads/main/json-schema/pinact.json
 # pinact - https://github.com/suzuki-shunsuke/pinact
+
+# The configuration schema version.
+# pinact fails if the version is newer than it supports, so an old pinact doesn't misread the configuration.
+# https://github.com/suzuki-shunsuke/pinact#version
+version: 1
+
+# Regular expressions of target files.
+# They're used if files aren't passed via positional command line arguments.
+# https://github.com/suzuki-shunsuke/pinact#filespattern
 files:
   - pattern: "^\\.github/workflows/.*\\.ya?ml$"
   - pattern: "^(.*/)?action\\.ya?ml$"
 
+# Actions and reusable workflows that pinact doesn't pin, such as ones which don't support pinning.
+# https://github.com/suzuki-shunsuke/pinact#ignore_actionsname
 ignore_actions:
 # - name: actions/checkout
 # - name: slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml
@@ -21,7 +35,12 @@ ignore_actions:
 	filePermission os.FileMode = 0o644
 )
 
-func (c *Controller) Init(configFilePath string) error {
+// Init creates a configuration file if it doesn't exist.
+// If configFilePath is a relative path, it's relative to pwd.
+func (c *Controller) Init(configFilePath, pwd string) error {
+	if !filepath.IsAbs(configFilePath) {
+		configFilePath = filepath.Join(pwd, configFilePath)
+	}
 	f, err := afero.Exists(c.fs, configFilePath)
 	if err != nil {
 		return fmt.Errorf("check if a configuration file exists: %w", err)
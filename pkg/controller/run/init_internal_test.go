@@ -0,0 +1,75 @@
+package run
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_Init(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name           string
+		configFilePath string
+		exp            string
+	}{
+		{
+			name:           "relative path is relative to pwd",
+			configFilePath: ".pinact.yaml",
+			exp:            "/repo/.pinact.yaml",
+		},
+		{
+			name:           "absolute path",
+			configFilePath: "/etc/pinact.yaml",
+			exp:            "/etc/pinact.yaml",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			ctrl := NewController(nil, fs)
+			if err := ctrl.Init(d.configFilePath, "/repo"); err != nil {
+				t.Fatal(err)
+			}
+			f, err := afero.Exists(fs, d.exp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !f {
+				t.Fatalf("the configuration file isn't created: %s", d.exp)
+			}
+		})
+	}
+}
+
+func TestController_Init_template(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	ctrl := NewController(nil, fs)
+	if err := ctrl.Init(".pinact.yaml", "/repo"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{}
+	if err := ctrl.readConfig(logrus.NewEntry(logrus.New()), ".pinact.yaml", "/repo", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Version != configVersion {
+		t.Fatalf("the template must have the latest configuration version: wanted %d, got %d", configVersion, cfg.Version)
+	}
+	if len(cfg.Files) != 2 {
+		t.Fatalf("wanted 2 files, got %d", len(cfg.Files))
+	}
+	// Each field is preceded by a link to the document.
+	lines := strings.Split(templateConfig, "\n")
+	for i, line := range lines {
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, " ") {
+			continue
+		}
+		if i == 0 || !strings.HasPrefix(lines[i-1], "# https://github.com/suzuki-shunsuke/pinact#") {
+			t.Fatalf("the field doesn't have a link to the document: %s", line)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package run
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// inlineIgnorePattern matches an inline suppression comment.
+// e.g. "# pinact:ignore", "# pinact:ignore until=2025-01-01"
+var inlineIgnorePattern = regexp.MustCompile(`#\s*pinact:ignore(?:\s+until=(\S+))?`)
+
+// isIgnoredInline returns true if the line has an inline suppression comment which hasn't expired.
+// The expiry date is inclusive. After the date, the suppression expires and the line is processed again with a warning,
+// which avoids forgotten permanent suppressions.
+func isIgnoredInline(logE *logrus.Entry, line string, cfg *Config) bool {
+	m := inlineIgnorePattern.FindStringSubmatch(line)
+	if m == nil {
+		return false
+	}
+	if m[1] == "" {
+		cfg.logIgnored(logE.WithFields(logrus.Fields{
+			"line": line,
+			"rule": "pinact:ignore",
+		}), "ignore the action")
+		return true
+	}
+	until, err := time.ParseInLocation(time.DateOnly, m[1], cfg.Now.Location())
+	if err != nil {
+		logE.WithError(err).WithField("line", line).Warn("the expiry date of pinact:ignore is invalid. The format must be YYYY-MM-DD")
+		return false
+	}
+	if !cfg.Now.Before(until.AddDate(0, 0, 1)) {
+		logE.WithFields(logrus.Fields{
+			"line":  line,
+			"until": m[1],
+		}).Warn("pinact:ignore has expired")
+		return false
+	}
+	cfg.logIgnored(logE.WithFields(logrus.Fields{
+		"line":  line,
+		"rule":  "pinact:ignore",
+		"until": m[1],
+	}), "ignore the action")
+	return true
+}
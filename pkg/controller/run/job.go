@@ -0,0 +1,235 @@
+package run
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	jobsPattern     = regexp.MustCompile(`^['"]?jobs['"]? *:`)
+	jobKeyPattern   = regexp.MustCompile(`^( +)['"]?([^ '"#:]+)['"]? *:`)
+	stepsPattern    = regexp.MustCompile(`^( *)['"]?steps['"]? *: *(?:#.*)?$`)
+	stepNamePattern = regexp.MustCompile(`^['"]?name['"]? *: *(.*?) *$`)
+	inputsPattern   = regexp.MustCompile(`^( *(?:- +)?)['"]?(?:with|secrets|env|outputs)['"]? *: *(?:#.*)?$`)
+)
+
+// getJobs returns the job id of each line.
+// If a line doesn't belong to any job, the job id is empty.
+// This is a lightweight line based parser, so it assumes the workflow is formatted in the block style.
+func getJobs(lines []string) []string {
+	jobs := make([]string, len(lines))
+	inJobs := false
+	indent := -1
+	job := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			jobs[i] = job
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			// top level key
+			inJobs = jobsPattern.MatchString(line)
+			indent = -1
+			job = ""
+			continue
+		}
+		if !inJobs {
+			continue
+		}
+		if m := jobKeyPattern.FindStringSubmatch(line); m != nil {
+			if indent == -1 {
+				indent = len(m[1])
+			}
+			if len(m[1]) == indent {
+				job = m[2]
+			}
+		}
+		jobs[i] = job
+	}
+	return jobs
+}
+
+// getStepNames returns the step name of each line.
+// If a line doesn't belong to any step or the step doesn't have a name, the step name is empty.
+// Like getJobs, this assumes the workflow is formatted in the block style.
+func getStepNames(lines []string) []string {
+	names := make([]string, len(lines))
+	stepsIndent := -1 // the indentation of "steps:". -1 means the line isn't in steps
+	itemIndent := -1  // the indentation of "- " of each step
+	start := -1       // the first line of the current step
+	flush := func(end int) {
+		if start == -1 {
+			return
+		}
+		name := findStepName(lines[start:end])
+		for j := start; j < end; j++ {
+			names[j] = name
+		}
+	}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if stepsIndent != -1 {
+			isItem := strings.HasPrefix(trimmed, "- ")
+			if itemIndent == -1 && isItem && indent >= stepsIndent {
+				itemIndent = indent
+			}
+			switch {
+			case indent == itemIndent && isItem:
+				flush(i)
+				start = i
+				continue
+			case itemIndent != -1 && indent > itemIndent:
+				continue
+			default:
+				flush(i)
+				start = -1
+				stepsIndent = -1
+				itemIndent = -1
+			}
+		}
+		if m := stepsPattern.FindStringSubmatch(line); m != nil {
+			stepsIndent = len(m[1])
+		}
+	}
+	flush(len(lines))
+	return names
+}
+
+// getInputLines returns whether each line is in a mapping of arbitrary keys such as with and secrets.
+// A key "uses" in the mapping is an input of an action or a reusable workflow rather than a reference, so it must not be pinned.
+//
+//	jobs:
+//	  call:
+//	    uses: suzuki-shunsuke/foo/.github/workflows/test.yaml@v1 # a reference
+//	    secrets: inherit
+//	    with:
+//	      uses: suzuki-shunsuke/bar@v1 # an input
+func getInputLines(lines []string) []bool {
+	inputs := make([]bool, len(lines))
+	keyIndent := -1 // the indentation of "with:". -1 means the line isn't in inputs
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			inputs[i] = keyIndent != -1
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if keyIndent != -1 && indent > keyIndent {
+			inputs[i] = true
+			continue
+		}
+		keyIndent = -1
+		if m := inputsPattern.FindStringSubmatch(line); m != nil {
+			// "- with:" is unusual, but the indentation of the key includes "- ".
+			keyIndent = len(m[1])
+		}
+	}
+	return inputs
+}
+
+// findStepName finds the name of a step.
+// The first line of lines must start with "- ".
+func findStepName(lines []string) string {
+	// "  - name: foo" -> "name: foo"
+	first := strings.TrimLeft(strings.TrimLeft(lines[0], " ")[1:], " ")
+	keyIndent := len(lines[0]) - len(first)
+	if m := stepNamePattern.FindStringSubmatch(first); m != nil {
+		return unquoteYAMLString(m[1])
+	}
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " ")
+		if len(line)-len(trimmed) != keyIndent {
+			continue
+		}
+		if m := stepNamePattern.FindStringSubmatch(trimmed); m != nil {
+			return unquoteYAMLString(m[1])
+		}
+	}
+	return ""
+}
+
+func unquoteYAMLString(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+type duplicateVersions struct {
+	Job      string
+	Action   string
+	Versions []string
+	// Line is a 1-based line number where the action is first used at a conflicting version.
+	Line int
+}
+
+// resolvedVersion returns the version compared by findDuplicateVersions.
+// If the action is pinned to a full commit hash with a version annotation, the annotation is returned
+// so that the same tag pinned in different ways isn't reported as a duplicate.
+func resolvedVersion(action *Action) string {
+	if action.Tag != "" && getVersionType(action.Version) == FullCommitSHA {
+		return action.Tag
+	}
+	return action.Version
+}
+
+// isCompatibleVersion returns true if two versions don't conflict.
+// A floating tag such as v4 or v4.2 is compatible with versions it covers such as v4.2.2,
+// so actions/checkout@v4 and actions/checkout@<commit hash> # v4.2.2 aren't reported.
+func isCompatibleVersion(a, b string) bool {
+	return a == b || strings.HasPrefix(b, a+".") || strings.HasPrefix(a, b+".")
+}
+
+// findDuplicateVersions finds actions used at multiple conflicting versions in the same job.
+// Versions are compared by resolvedVersion and isCompatibleVersion.
+func findDuplicateVersions(lines []string) []*duplicateVersions {
+	jobs := getJobs(lines)
+	// job -> action -> versions
+	versions := map[string]map[string][]string{}
+	dups := []*duplicateVersions{}
+	for i, line := range lines {
+		job := jobs[i]
+		if job == "" {
+			continue
+		}
+		action := parseAction(line)
+		if action == nil {
+			continue
+		}
+		actions, ok := versions[job]
+		if !ok {
+			actions = map[string][]string{}
+			versions[job] = actions
+		}
+		version := resolvedVersion(action)
+		vs := actions[action.Name]
+		if slices.Contains(vs, version) {
+			continue
+		}
+		actions[action.Name] = append(vs, version)
+		if slices.ContainsFunc(dups, func(dup *duplicateVersions) bool {
+			return dup.Job == job && dup.Action == action.Name
+		}) {
+			continue
+		}
+		if slices.ContainsFunc(vs, func(v string) bool {
+			return !isCompatibleVersion(v, version)
+		}) {
+			dups = append(dups, &duplicateVersions{
+				Job:    job,
+				Action: action.Name,
+				Line:   i + 1,
+			})
+		}
+	}
+	for _, dup := range dups {
+		dup.Versions = versions[dup.Job][dup.Action]
+	}
+	return dups
+}
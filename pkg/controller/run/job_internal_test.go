@@ -0,0 +1,226 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_getJobs(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"name: test",
+		"on: pull_request",
+		"jobs:",
+		"  test:",
+		"    runs-on: ubuntu-latest",
+		"    steps:",
+		"      - uses: actions/checkout@v4",
+		"",
+		"  # comment",
+		"  build:",
+		"    uses: suzuki-shunsuke/foo/.github/workflows/build.yaml@v1",
+	}
+	exp := []string{
+		"", "", "", "test", "test", "test", "test", "test", "test", "build", "build",
+	}
+	if diff := cmp.Diff(exp, getJobs(lines)); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_getStepNames(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"jobs:",
+		"  test:",
+		"    steps:",
+		"      - name: Checkout",
+		"        uses: actions/checkout@v4",
+		"      - uses: actions/setup-go@v5",
+		"        name: 'Setup Go'",
+		"        with:",
+		"          go-version-file: go.mod",
+		"      - uses: actions/cache@v4",
+		"  build:",
+		"    steps:",
+		"    - name: Build",
+		"      run: go build",
+		"    - uses: actions/checkout@v4",
+	}
+	exp := []string{
+		"", "", "",
+		"Checkout", "Checkout",
+		"Setup Go", "Setup Go", "Setup Go", "Setup Go",
+		"",
+		"", "",
+		"Build", "Build",
+		"",
+	}
+	if diff := cmp.Diff(exp, getStepNames(lines)); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_findDuplicateVersions(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name  string
+		lines []string
+		exp   []*duplicateVersions
+	}{
+		{
+			name: "no duplicate",
+			lines: []string{
+				"jobs:",
+				"  test:",
+				"    steps:",
+				"      - uses: actions/checkout@v4",
+				"      - uses: actions/checkout@v4",
+				"  build:",
+				"    steps:",
+				"      - uses: actions/checkout@v3",
+			},
+			exp: []*duplicateVersions{},
+		},
+		{
+			name: "duplicate",
+			lines: []string{
+				"jobs:",
+				"  test:",
+				"    steps:",
+				"      - uses: actions/checkout@v4",
+				"      - uses: actions/setup-go@v5",
+				"      - uses: actions/checkout@v3",
+				"      - uses: actions/checkout@v2",
+			},
+			exp: []*duplicateVersions{
+				{
+					Job:      "test",
+					Action:   "actions/checkout",
+					Versions: []string{"v4", "v3", "v2"},
+					Line:     6,
+				},
+			},
+		},
+		{
+			name: "the same tag pinned to a commit",
+			lines: []string{
+				"jobs:",
+				"  test:",
+				"    steps:",
+				"      - uses: actions/checkout@v4.2.2",
+				"      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+			},
+			exp: []*duplicateVersions{},
+		},
+		{
+			name: "different tags pinned to commits",
+			lines: []string{
+				"jobs:",
+				"  test:",
+				"    steps:",
+				"      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+				"      - uses: actions/checkout@eef61447b9ff4aafe5dcd4e0bbf5d482be7e7871 # v4.2.1",
+			},
+			exp: []*duplicateVersions{
+				{
+					Job:      "test",
+					Action:   "actions/checkout",
+					Versions: []string{"v4.2.2", "v4.2.1"},
+					Line:     5,
+				},
+			},
+		},
+		{
+			name: "a floating tag and a commit annotated with a tag it covers",
+			lines: []string{
+				"jobs:",
+				"  test:",
+				"    steps:",
+				"      - uses: actions/checkout@v4",
+				"      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+				"      - uses: actions/checkout@v4.2",
+			},
+			exp: []*duplicateVersions{},
+		},
+		{
+			name: "a floating tag and a commit annotated with another major version",
+			lines: []string{
+				"jobs:",
+				"  test:",
+				"    steps:",
+				"      - uses: actions/checkout@v4",
+				"      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+			},
+			exp: []*duplicateVersions{
+				{
+					Job:      "test",
+					Action:   "actions/checkout",
+					Versions: []string{"v4", "v2.7.0"},
+					Line:     5,
+				},
+			},
+		},
+		{
+			name: "versions covered by a floating tag conflict with each other",
+			lines: []string{
+				"jobs:",
+				"  test:",
+				"    steps:",
+				"      - uses: actions/checkout@v4",
+				"      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+				"      - uses: actions/checkout@eef61447b9ff4aafe5dcd4e0bbf5d482be7e7871 # v4.2.1",
+			},
+			exp: []*duplicateVersions{
+				{
+					Job:      "test",
+					Action:   "actions/checkout",
+					Versions: []string{"v4", "v4.2.2", "v4.2.1"},
+					Line:     6,
+				},
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(d.exp, findDuplicateVersions(d.lines)); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_getInputLines(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"jobs:",
+		"  call:",
+		"    uses: suzuki-shunsuke/foo/.github/workflows/test.yaml@v1",
+		"    secrets: inherit",
+		"    with:",
+		"      uses: suzuki-shunsuke/bar@v1",
+		"",
+		"      # comment",
+		"      version: v1",
+		"  call-2:",
+		"    secrets:",
+		"      token: ${{ secrets.TOKEN }}",
+		"    uses: suzuki-shunsuke/foo/.github/workflows/test.yaml@v1",
+		"  test:",
+		"    steps:",
+		"      - with:",
+		"          uses: suzuki-shunsuke/bar@v1",
+		"        uses: actions/checkout@v4",
+	}
+	exp := []bool{
+		false, false, false, false, false,
+		true, true, true, true,
+		false, false, true, false,
+		false, false, false, true, false,
+	}
+	if diff := cmp.Diff(exp, getInputLines(lines)); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,106 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	// FormatJSON outputs findings as JSON instead of modifying files.
+	FormatJSON = "json"
+	// jsonReportVersion is the version of the JSON report format.
+	// It must be incremented when the format is changed in a backward incompatible way.
+	jsonReportVersion = 1
+)
+
+// JSONReport is a report of findings for editor integrations.
+// Editors can apply fixes of findings as single-line quick fixes.
+type JSONReport struct {
+	Version int `json:"version"`
+	// PinactVersion is the version of pinact which outputs the report.
+	PinactVersion string               `json:"pinact_version,omitempty"`
+	Findings      []*JSONReportFinding `json:"findings"`
+}
+
+type JSONReportFinding struct {
+	Kind    string `json:"kind"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Severity is the severity configured by checks. It's empty if the check isn't configured.
+	Severity string `json:"severity,omitempty"`
+	// Replacement is an alternative of a deprecated action configured by replacements.
+	Replacement string `json:"replacement,omitempty"`
+	Fix         *Fix   `json:"fix,omitempty"`
+}
+
+// Fix is a replacement of a range of a file.
+type Fix struct {
+	Range       *Range `json:"range"`
+	Replacement string `json:"replacement"`
+}
+
+// Range is a range of a file like LSP.
+// Lines and characters are 0-based, and the end is exclusive.
+type Range struct {
+	Start *Position `json:"start"`
+	End   *Position `json:"end"`
+}
+
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// jsonReporter collects findings and forwards events to the next reporter.
+type jsonReporter struct {
+	*findingCollector
+	version string
+}
+
+func newJSONReporter(next Reporter, version string) *jsonReporter {
+	return &jsonReporter{
+		findingCollector: newFindingCollector(next),
+		version:          version,
+	}
+}
+
+func newJSONReportFinding(finding *Finding) *JSONReportFinding {
+	f := &JSONReportFinding{
+		Kind:        finding.Kind,
+		File:        finding.File,
+		Line:        finding.Line,
+		Message:     finding.Message,
+		Severity:    finding.Severity,
+		Replacement: finding.Replacement,
+	}
+	if finding.Kind == FindingKindChanged {
+		// Replace the whole line.
+		f.Fix = &Fix{
+			Range: &Range{
+				Start: &Position{Line: finding.Line - 1},
+				End:   &Position{Line: finding.Line - 1, Character: len([]rune(finding.Before))},
+			},
+			Replacement: finding.After,
+		}
+	}
+	return f
+}
+
+func (r *jsonReporter) write(w io.Writer) error {
+	report := &JSONReport{
+		Version:       jsonReportVersion,
+		PinactVersion: r.version,
+		Findings:      make([]*JSONReportFinding, len(r.findings)),
+	}
+	for i, finding := range r.findings {
+		report.Findings[i] = newJSONReportFinding(finding)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("output a report as JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package run
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSONReporter(t *testing.T) {
+	t.Parallel()
+	next := &testReporter{}
+	reporter := newJSONReporter(next, "v3.0.0")
+	reporter.OnFileStart("test.yaml")
+	reporter.OnFinding(&Finding{
+		Kind:   FindingKindChanged,
+		File:   "test.yaml",
+		Line:   4,
+		Before: "      - uses: actions/checkout@v2",
+		After:  "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+	})
+	reporter.OnFinding(&Finding{
+		Kind:    FindingKindError,
+		File:    "test.yaml",
+		Line:    5,
+		Message: "get a commit hash",
+	})
+	reporter.OnFileEnd("test.yaml", true)
+	if diff := cmp.Diff([]string{"start", "finding", "finding", "end"}, next.events); diff != "" {
+		t.Fatal(diff)
+	}
+	buf := &bytes.Buffer{}
+	if err := reporter.write(buf); err != nil {
+		t.Fatal(err)
+	}
+	exp := `{
+  "version": 1,
+  "pinact_version": "v3.0.0",
+  "findings": [
+    {
+      "kind": "changed",
+      "file": "test.yaml",
+      "line": 4,
+      "fix": {
+        "range": {
+          "start": {
+            "line": 3,
+            "character": 0
+          },
+          "end": {
+            "line": 3,
+            "character": 33
+          }
+        },
+        "replacement": "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0"
+      }
+    },
+    {
+      "kind": "error",
+      "file": "test.yaml",
+      "line": 5,
+      "message": "get a commit hash"
+    }
+  ]
+}
+`
+	if diff := cmp.Diff(exp, buf.String()); diff != "" {
+		t.Fatal(diff)
+	}
+}
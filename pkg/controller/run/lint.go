@@ -0,0 +1,99 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+type ParamLint struct {
+	ConfigFilePath string
+	PWD            string
+	IsVerify       bool
+	Now            time.Time
+}
+
+// Linter checks the content of a file without modifying it.
+// It's used by the language server, which checks files being edited in editors.
+type Linter struct {
+	controller *Controller
+	cfg        *Config
+}
+
+// NewLinter reads the configuration and returns a Linter.
+func (c *Controller) NewLinter(logE *logrus.Entry, param *ParamLint) (*Linter, error) {
+	cfg := &Config{}
+	if err := c.readConfig(logE, param.ConfigFilePath, param.PWD, cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Init(); err != nil {
+		return nil, fmt.Errorf("initialize the configuration: %w", err)
+	}
+	cfg.Now = param.Now
+	cfg.setFreezeWindows(param.Now)
+	cfg.IsVerify = param.IsVerify
+	return &Linter{
+		controller: c,
+		cfg:        cfg,
+	}, nil
+}
+
+// Lint returns findings of the content.
+// Findings of the kind changed have the fixed line as After.
+func (l *Linter) Lint(ctx context.Context, logE *logrus.Entry, path, content string) []*Finding {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	findings := []*Finding{}
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		fixed, err := l.controller.parseLine(ctx, logE, line, l.cfg)
+		if err != nil {
+			logerr.WithError(logE, err).Debug("parse a line")
+			severity := l.cfg.getSeverity(getErrorCheck(err))
+			if severity == SeverityOff {
+				continue
+			}
+			findings = append(findings, &Finding{
+				Kind:     FindingKindError,
+				File:     path,
+				Line:     i + 1,
+				Before:   line,
+				After:    line,
+				Message:  err.Error(),
+				Severity: severity,
+			})
+			continue
+		}
+		if fixed == line {
+			continue
+		}
+		severity := l.cfg.getSeverity(getChangeCheck(line, fixed))
+		if severity == SeverityOff {
+			continue
+		}
+		findings = append(findings, &Finding{
+			Kind:     FindingKindChanged,
+			File:     path,
+			Line:     i + 1,
+			Before:   line,
+			After:    fixed,
+			Message:  lintMessage(line, fixed),
+			Severity: severity,
+		})
+	}
+	return findings
+}
+
+func lintMessage(before, after string) string {
+	action := parseAction(before)
+	if action == nil || getVersionType(action.Version) != FullCommitSHA {
+		return "the action isn't pinned"
+	}
+	if getChangeCheck(before, after) == checkShortAnnotation {
+		return "the version annotation isn't full semver"
+	}
+	return "pinact would update the line"
+}
@@ -0,0 +1,65 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+func TestLinter_Lint(t *testing.T) {
+	t.Parallel()
+	ctrl := NewController(&RepositoriesServiceImpl{
+		tags: map[string]*ListTagsResult{
+			"actions/checkout/0": {
+				Tags: []*github.RepositoryTag{
+					{
+						Name: util.StrP("v2.7.0"),
+						Commit: &github.Commit{
+							SHA: util.StrP("ee0669bd1cc54295c223e0bb666b733df41de1c5"),
+						},
+					},
+				},
+				Response: &github.Response{},
+			},
+		},
+		commits: map[string]*GetCommitSHA1Result{
+			"actions/checkout/v2": {
+				SHA: "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+			},
+		},
+	}, afero.NewMemMapFs())
+	logE := logrus.NewEntry(logrus.New())
+	linter, err := ctrl.NewLinter(logE, &ParamLint{
+		PWD: "/home/foo/repo",
+		Now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v2
+      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0
+`
+	findings := linter.Lint(context.Background(), logE, "test.yaml", content)
+	exp := []*Finding{
+		{
+			Kind:    FindingKindChanged,
+			File:    "test.yaml",
+			Line:    4,
+			Before:  "      - uses: actions/checkout@v2",
+			After:   "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+			Message: "the action isn't pinned",
+		},
+	}
+	if diff := cmp.Diff(exp, findings); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,265 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+type ParamList struct {
+	WorkflowFilePaths []string
+	ConfigFilePath    string
+	PWD               string
+	Now               time.Time
+	// WithDates outputs dates of pinned commits.
+	// This calls GitHub API per action, so it's disabled by default.
+	WithDates bool
+	// CheckUpdates outputs the latest versions of actions and whether updates are available.
+	// This calls GitHub API per action, so it's disabled by default.
+	CheckUpdates bool
+	// UsedBy outputs the reverse index from actions to lines using them instead of the table.
+	UsedBy bool
+	// LineTemplate is a Go template rendered per action instead of the table.
+	// Fields of listedAction and functions such as ToUpper, replace, shortSHA, and padRight are available.
+	LineTemplate string
+	// Sort is the order of actions. It's either file or action. The default value is file.
+	Sort string
+	// Format is the format of --used-by. It's either json or markdown. The default value is markdown.
+	Format string
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// listedAction is an action used in target files.
+type listedAction struct {
+	File    string
+	Line    int
+	Name    string
+	Version string
+	Tag     string
+	Date    time.Time
+	// LatestVersion is the latest version of the action. It's set only if --check-updates is set.
+	LatestVersion string
+	// UpdateAvailable is true if the latest version is newer than the current version.
+	UpdateAvailable bool
+}
+
+// List outputs actions used in target files.
+// Files aren't changed.
+func (c *Controller) List(ctx context.Context, logE *logrus.Entry, param *ParamList) error {
+	if param.Format != "" && !param.UsedBy {
+		return errors.New("--format requires --used-by")
+	}
+	if param.WithDates && param.UsedBy {
+		return errors.New("--with-dates and --used-by can't be used together")
+	}
+	if param.LineTemplate != "" && param.UsedBy {
+		return errors.New("--line-template and --used-by can't be used together")
+	}
+	if err := validateUsedByFormat(param.Format); err != nil {
+		return err
+	}
+	if err := validateSortKey(param.Sort, SortFile, SortAction); err != nil {
+		return err
+	}
+	var lineTemplate *template.Template
+	if param.LineTemplate != "" {
+		tmpl, err := newLineTemplate(param.LineTemplate)
+		if err != nil {
+			return err
+		}
+		lineTemplate = tmpl
+	}
+	cfg := &Config{}
+	if err := c.readConfig(logE, param.ConfigFilePath, param.PWD, cfg); err != nil {
+		return err
+	}
+	if err := cfg.Init(); err != nil {
+		return fmt.Errorf("initialize the configuration: %w", err)
+	}
+	cfg.Now = param.Now
+	workflowFilePaths, err := c.searchFiles(logE, param.WorkflowFilePaths, cfg, param.PWD)
+	if err != nil {
+		return fmt.Errorf("search target files: %w", err)
+	}
+	stats := &Stats{
+		Time:  param.Now,
+		Files: len(workflowFilePaths),
+	}
+	defer printSummary(param.Stderr, stats)
+	actions := []*listedAction{}
+	for _, workflowFilePath := range workflowFilePaths {
+		logE := logE.WithField("workflow_file", workflowFilePath)
+		p := workflowFilePath
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(param.PWD, p)
+		}
+		lines, err := c.readWorkflow(p)
+		if err != nil {
+			logerr.WithError(logE, err).Warn("read a workflow")
+			stats.Errors++
+			continue
+		}
+		actions = append(actions, c.listActions(ctx, logE, workflowFilePath, lines, cfg, param.WithDates)...)
+	}
+	if param.CheckUpdates {
+		c.checkUpdates(ctx, logE, cfg, actions)
+	}
+	sortListedActions(actions, param.Sort)
+	for _, a := range actions {
+		stats.Actions++
+		if getVersionType(a.Version) == FullCommitSHA {
+			stats.Pinned++
+		} else {
+			stats.Unpinned++
+		}
+	}
+	if param.UsedBy {
+		return outputUsedBy(param.Stdout, actions, param.Format)
+	}
+	if lineTemplate != nil {
+		return outputLines(param.Stdout, lineTemplate, actions)
+	}
+	return outputActions(param.Stdout, actions, param.WithDates, param.CheckUpdates)
+}
+
+// listActions returns actions used in lines.
+// If withDates is true, dates of pinned commits are got by GitHub API.
+func (c *Controller) listActions(ctx context.Context, logE *logrus.Entry, file string, lines []string, cfg *Config, withDates bool) []*listedAction {
+	actions := []*listedAction{}
+	for i, line := range lines {
+		action := c.getTargetAction(logE, line, cfg)
+		if action == nil {
+			continue
+		}
+		a := &listedAction{
+			File:    file,
+			Line:    i + 1,
+			Name:    action.Name,
+			Version: action.Version,
+			Tag:     action.Tag,
+		}
+		actions = append(actions, a)
+		if !withDates {
+			continue
+		}
+		// GitHub API resolves not only commit hashes but also tags and branches.
+		date, _, err := c.repositoriesService.GetCommitDate(ctx, action.RepoOwner, action.RepoName, action.Version)
+		if err != nil {
+			logerr.WithError(logE, err).WithFields(logrus.Fields{
+				"action":      action.Name,
+				"line_number": i + 1,
+			}).Warn("get the date of the pinned commit")
+			continue
+		}
+		a.Date = date
+	}
+	return actions
+}
+
+// checkUpdates sets the latest versions of actions and whether updates are available.
+// The latest version is got once per repository.
+func (c *Controller) checkUpdates(ctx context.Context, logE *logrus.Entry, cfg *Config, actions []*listedAction) {
+	latestVersions := map[string]string{}
+	for _, a := range actions {
+		owner, repo, ok := splitActionRepo(a.Name)
+		if !ok {
+			continue
+		}
+		key := owner + "/" + repo
+		latest, ok := latestVersions[key]
+		if !ok {
+			v, err := c.getLatestVersion(ctx, logE, cfg, owner, repo)
+			if err != nil {
+				logerr.WithError(logE, err).WithField("action", a.Name).Warn("get the latest version")
+			}
+			latestVersions[key] = v
+			latest = v
+		}
+		if latest == "" {
+			continue
+		}
+		a.LatestVersion = latest
+		current := a.Tag
+		if current == "" {
+			current = a.Version
+		}
+		a.UpdateAvailable = isUpdateAvailable(current, latest)
+	}
+}
+
+// splitActionRepo returns the repository owner and name of the action.
+// e.g. actions/cache/restore => actions, cache
+func splitActionRepo(name string) (string, string, bool) {
+	owner, rest, ok := strings.Cut(name, "/")
+	if !ok {
+		return "", "", false
+	}
+	repo, _, _ := strings.Cut(rest, "/")
+	return owner, repo, true
+}
+
+// isUpdateAvailable returns true if the latest version is newer than the current version.
+// If either version isn't semver, they're compared as strings.
+func isUpdateAvailable(current, latest string) bool {
+	cv, err := version.NewVersion(current)
+	if err != nil {
+		return current != latest
+	}
+	lv, err := version.NewVersion(latest)
+	if err != nil {
+		return current != latest
+	}
+	return lv.GreaterThan(cv)
+}
+
+func outputActions(stdout io.Writer, actions []*listedAction, withDates, checkUpdates bool) error {
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0) //nolint:mnd
+	header := []string{"FILE", "LINE", "ACTION", "VERSION", "TAG"}
+	if withDates {
+		header = append(header, "DATE")
+	}
+	if checkUpdates {
+		header = append(header, "LATEST", "UPDATE")
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, a := range actions {
+		tag := a.Tag
+		if tag == "" {
+			tag = "-"
+		}
+		columns := []string{a.File, strconv.Itoa(a.Line), a.Name, a.Version, tag}
+		if withDates {
+			date := "-"
+			if !a.Date.IsZero() {
+				date = a.Date.Format(time.DateOnly)
+			}
+			columns = append(columns, date)
+		}
+		if checkUpdates {
+			latest := "-"
+			update := "-"
+			if a.LatestVersion != "" {
+				latest = a.LatestVersion
+				update = strconv.FormatBool(a.UpdateAvailable)
+			}
+			columns = append(columns, latest, update)
+		}
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("output actions: %w", err)
+	}
+	return nil
+}
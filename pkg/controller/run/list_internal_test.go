@@ -0,0 +1,253 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+func TestController_listActions(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		"      - uses: actions/setup-go@v5",
+		"      - uses: actions/cache@v4 # pinact:ignore",
+		"      - uses: docker://alpine:3.21",
+	}
+	date := time.Date(2024, 10, 23, 0, 0, 0, 0, time.UTC)
+	data := []struct {
+		name      string
+		withDates bool
+		exp       []*listedAction
+	}{
+		{
+			name: "without dates",
+			exp: []*listedAction{
+				{File: "test.yaml", Line: 1, Name: "actions/checkout", Version: "11bd71901bbe5b1630ceea73d27597364c9af683", Tag: "v4.2.2"},
+				{File: "test.yaml", Line: 2, Name: "actions/setup-go", Version: "v5"},
+			},
+		},
+		{
+			name:      "with dates",
+			withDates: true,
+			exp: []*listedAction{
+				{File: "test.yaml", Line: 1, Name: "actions/checkout", Version: "11bd71901bbe5b1630ceea73d27597364c9af683", Tag: "v4.2.2", Date: date},
+				{File: "test.yaml", Line: 2, Name: "actions/setup-go", Version: "v5"},
+			},
+		},
+	}
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				commitDates: map[string]*GetCommitDateResult{
+					"actions/checkout/11bd71901bbe5b1630ceea73d27597364c9af683": {
+						Date: date,
+					},
+					"actions/setup-go/v5": {
+						err: errors.New("not found"),
+					},
+				},
+			}, afero.NewMemMapFs())
+			got := ctrl.listActions(context.Background(), logE, "test.yaml", lines, &Config{}, d.withDates)
+			if diff := cmp.Diff(d.exp, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestController_checkUpdates(t *testing.T) {
+	t.Parallel()
+	ctrl := NewController(&RepositoriesServiceImpl{
+		releases: map[string]*ListReleasesResult{
+			"actions/checkout/0": {
+				Releases: []*github.RepositoryRelease{
+					{TagName: util.StrP("v4.2.2")},
+				},
+				Response: &github.Response{},
+			},
+			"actions/cache/0": {
+				Releases: []*github.RepositoryRelease{
+					{TagName: util.StrP("v4.2.0")},
+				},
+				Response: &github.Response{},
+			},
+		},
+	}, afero.NewMemMapFs())
+	cfg := &Config{}
+	if err := cfg.Init(); err != nil {
+		t.Fatal(err)
+	}
+	actions := []*listedAction{
+		{Name: "actions/checkout", Version: "11bd71901bbe5b1630ceea73d27597364c9af683", Tag: "v4.2.2"},
+		{Name: "actions/checkout", Version: "v3"},
+		{Name: "actions/cache/restore", Version: "v4.1.0"},
+	}
+	ctrl.checkUpdates(context.Background(), logrus.NewEntry(logrus.New()), cfg, actions)
+	exp := []*listedAction{
+		{Name: "actions/checkout", Version: "11bd71901bbe5b1630ceea73d27597364c9af683", Tag: "v4.2.2", LatestVersion: "v4.2.2"},
+		{Name: "actions/checkout", Version: "v3", LatestVersion: "v4.2.2", UpdateAvailable: true},
+		{Name: "actions/cache/restore", Version: "v4.1.0", LatestVersion: "v4.2.0", UpdateAvailable: true},
+	}
+	if diff := cmp.Diff(exp, actions); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_outputActions(t *testing.T) {
+	t.Parallel()
+	actions := []*listedAction{
+		{File: "test.yaml", Line: 1, Name: "actions/checkout", Version: "11bd71901bbe5b1630ceea73d27597364c9af683", Tag: "v4.2.2", Date: time.Date(2024, 10, 23, 0, 0, 0, 0, time.UTC)},
+		{File: "test.yaml", Line: 2, Name: "actions/setup-go", Version: "v5"},
+	}
+	buf := &bytes.Buffer{}
+	if err := outputActions(buf, actions, true, false); err != nil {
+		t.Fatal(err)
+	}
+	exp := `FILE       LINE  ACTION            VERSION                                   TAG     DATE
+test.yaml  1     actions/checkout  11bd71901bbe5b1630ceea73d27597364c9af683  v4.2.2  2024-10-23
+test.yaml  2     actions/setup-go  v5                                        -       -
+`
+	if diff := cmp.Diff(exp, buf.String()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_outputUsedBy(t *testing.T) {
+	t.Parallel()
+	actions := []*listedAction{
+		{File: "build.yaml", Line: 20, Name: "actions/upload-artifact", Version: "ff15f0306b3f739f7b6fd43fb5d26cd321bd4de5", Tag: "v3.1.3"},
+		{File: "build.yaml", Line: 10, Name: "actions/checkout", Version: "v4"},
+		{File: "release.yaml", Line: 35, Name: "actions/upload-artifact", Version: "v3"},
+	}
+	data := []struct {
+		name   string
+		format string
+		exp    string
+	}{
+		{
+			name: "markdown",
+			exp: `## actions/checkout
+
+- build.yaml:10 v4
+
+## actions/upload-artifact
+
+- build.yaml:20 v3.1.3
+- release.yaml:35 v3
+`,
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			exp: `{
+  "actions/checkout": [
+    {
+      "file": "build.yaml",
+      "line": 10,
+      "version": "v4"
+    }
+  ],
+  "actions/upload-artifact": [
+    {
+      "file": "build.yaml",
+      "line": 20,
+      "version": "ff15f0306b3f739f7b6fd43fb5d26cd321bd4de5",
+      "tag": "v3.1.3"
+    },
+    {
+      "file": "release.yaml",
+      "line": 35,
+      "version": "v3"
+    }
+  ]
+}
+`,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			buf := &bytes.Buffer{}
+			if err := outputUsedBy(buf, actions, d.format); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(d.exp, buf.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_outputLines(t *testing.T) {
+	t.Parallel()
+	actions := []*listedAction{
+		{File: "test.yaml", Line: 1, Name: "actions/checkout", Version: "11bd71901bbe5b1630ceea73d27597364c9af683", Tag: "v4.2.2", Date: time.Date(2024, 10, 23, 0, 0, 0, 0, time.UTC)},
+		{File: "test.yaml", Line: 2, Name: "actions/setup-go", Version: "v5", LatestVersion: "v5.5.0", UpdateAvailable: true},
+	}
+	data := []struct {
+		name  string
+		tmpl  string
+		exp   string
+		isErr bool
+	}{
+		{
+			name: "fields",
+			tmpl: "{{.File}}:{{.Line}} {{.Name}}@{{.Version}}",
+			exp: `test.yaml:1 actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683
+test.yaml:2 actions/setup-go@v5
+`,
+		},
+		{
+			name: "functions",
+			tmpl: `{{padRight 18 .Name}}|{{padLeft 8 (shortSHA .Version)}}|{{ToUpper .Tag}}|{{replace "actions/" "" .Name}}|{{trunc 4 .File}}|{{trimPrefix "v" .LatestVersion}}`,
+			exp: `actions/checkout  | 11bd719|V4.2.2|checkout|test|
+actions/setup-go  |      v5||setup-go|test|5.5.0
+`,
+		},
+		{
+			name: "update available",
+			tmpl: `{{.Name}}{{if .UpdateAvailable}} {{.LatestVersion}}{{end}} {{.Date.Format "2006-01-02"}}`,
+			exp: `actions/checkout 2024-10-23
+actions/setup-go v5.5.0 0001-01-01
+`,
+		},
+		{
+			name:  "invalid template",
+			tmpl:  "{{.Name",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			tmpl, err := newLineTemplate(d.tmpl)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			buf := &bytes.Buffer{}
+			if err := outputLines(buf, tmpl, actions); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(d.exp, buf.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
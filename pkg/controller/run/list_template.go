@@ -0,0 +1,72 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// shortSHALength is the length of commit hashes truncated by the template function shortSHA.
+const shortSHALength = 7
+
+// lineTemplateFuncs are functions available in --line-template.
+// They're similar to sprig's functions so that users can format output without post-processing with awk or sed.
+func lineTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"ToUpper": strings.ToUpper,
+		"ToLower": strings.ToLower,
+		"replace": func(old, newS, s string) string {
+			return strings.ReplaceAll(s, old, newS)
+		},
+		"trimPrefix": func(prefix, s string) string {
+			return strings.TrimPrefix(s, prefix)
+		},
+		"trunc": truncate,
+		"shortSHA": func(s string) string {
+			if getVersionType(s) != FullCommitSHA {
+				return s
+			}
+			return s[:shortSHALength]
+		},
+		"padRight": func(n int, s string) string {
+			return fmt.Sprintf("%-*s", n, s)
+		},
+		"padLeft": func(n int, s string) string {
+			return fmt.Sprintf("%*s", n, s)
+		},
+	}
+}
+
+// truncate returns the first n characters of s.
+func truncate(n int, s string) string {
+	r := []rune(s)
+	if n < 0 || len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+// newLineTemplate parses --line-template.
+func newLineTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("line").Funcs(lineTemplateFuncs()).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse --line-template as a template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// outputLines outputs a line rendered by the template per action.
+func outputLines(stdout io.Writer, tmpl *template.Template, actions []*listedAction) error {
+	sb := &strings.Builder{}
+	for _, a := range actions {
+		if err := tmpl.Execute(sb, a); err != nil {
+			return fmt.Errorf("render --line-template: %w", err)
+		}
+		sb.WriteString("\n")
+	}
+	if _, err := io.WriteString(stdout, sb.String()); err != nil {
+		return fmt.Errorf("output actions: %w", err)
+	}
+	return nil
+}
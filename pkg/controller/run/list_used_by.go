@@ -0,0 +1,89 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// FormatMarkdown outputs the reverse index of pinact list --used-by in Markdown.
+const FormatMarkdown = "markdown"
+
+// actionUsage is a line using an action.
+type actionUsage struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Version string `json:"version"`
+	Tag     string `json:"tag,omitempty"`
+	// LatestVersion and UpdateAvailable are set only if --check-updates is set.
+	LatestVersion   string `json:"latest_version,omitempty"`
+	UpdateAvailable bool   `json:"update_available,omitempty"`
+}
+
+// getUsedBy builds the reverse index of actions from action names to lines using them.
+// It's useful for inventory and impact analysis such as which workflows use actions/upload-artifact v3.
+func getUsedBy(actions []*listedAction) map[string][]*actionUsage {
+	usedBy := map[string][]*actionUsage{}
+	for _, a := range actions {
+		usedBy[a.Name] = append(usedBy[a.Name], &actionUsage{
+			File:            a.File,
+			Line:            a.Line,
+			Version:         a.Version,
+			Tag:             a.Tag,
+			LatestVersion:   a.LatestVersion,
+			UpdateAvailable: a.UpdateAvailable,
+		})
+	}
+	return usedBy
+}
+
+func validateUsedByFormat(format string) error {
+	switch format {
+	case "", FormatJSON, FormatMarkdown:
+		return nil
+	}
+	return fmt.Errorf("--format must be either %s or %s: %s", FormatJSON, FormatMarkdown, format)
+}
+
+// outputUsedBy outputs the reverse index of actions in the format.
+// If the format is empty, Markdown is used.
+func outputUsedBy(stdout io.Writer, actions []*listedAction, format string) error {
+	usedBy := getUsedBy(actions)
+	if format == FormatJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(usedBy); err != nil {
+			return fmt.Errorf("output actions as JSON: %w", err)
+		}
+		return nil
+	}
+	names := make([]string, 0, len(usedBy))
+	for name := range usedBy {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	sb := &strings.Builder{}
+	for i, name := range names {
+		if i != 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(sb, "## %s\n\n", name)
+		for _, u := range usedBy[name] {
+			version := u.Version
+			if u.Tag != "" {
+				version = u.Tag
+			}
+			if u.UpdateAvailable {
+				fmt.Fprintf(sb, "- %s:%d %s (latest: %s)\n", u.File, u.Line, version, u.LatestVersion)
+				continue
+			}
+			fmt.Fprintf(sb, "- %s:%d %s\n", u.File, u.Line, version)
+		}
+	}
+	if _, err := io.WriteString(stdout, sb.String()); err != nil {
+		return fmt.Errorf("output actions as Markdown: %w", err)
+	}
+	return nil
+}
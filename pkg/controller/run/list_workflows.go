@@ -3,16 +3,31 @@ package run
 import (
 	"fmt"
 	"path/filepath"
+
+	"github.com/spf13/afero"
 )
 
-func listWorkflows() ([]string, error) {
-	files, err := filepath.Glob(".github/workflows/*.yml")
-	if err != nil {
-		return nil, fmt.Errorf("find .github/workflows/*.yml: %w", err)
+// listWorkflows returns workflow files under .github/workflows.
+// If include_actions is true, action files of composite actions under .github/actions are also returned.
+// Returned paths are relative to pwd.
+func (c *Controller) listWorkflows(pwd string, cfg *Config) ([]string, error) {
+	patterns := []string{".github/workflows/*.yml", ".github/workflows/*.yaml"}
+	if cfg.includeActions() {
+		patterns = append(patterns, ".github/actions/*/action.yml", ".github/actions/*/action.yaml")
 	}
-	files2, err := filepath.Glob(".github/workflows/*.yaml")
-	if err != nil {
-		return nil, fmt.Errorf("find .github/workflows/*.yaml: %w", err)
+	files := []string{}
+	for _, pattern := range patterns {
+		matches, err := afero.Glob(c.fs, filepath.Join(pwd, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("find %s: %w", pattern, err)
+		}
+		for _, match := range matches {
+			file, err := filepath.Rel(pwd, match)
+			if err != nil {
+				return nil, fmt.Errorf("get a relative path: %w", err)
+			}
+			files = append(files, file)
+		}
 	}
-	return append(files, files2...), nil
+	return files, nil
 }
@@ -0,0 +1,134 @@
+package run
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+// staleLockAge is the age of a lock file which is regarded as left by a killed process.
+// It's used only if it can't be checked whether the process recorded in the lock file is running.
+const staleLockAge = time.Hour
+
+var errLocked = errors.New("another pinact process is running in the same repository. If no pinact process is running, please remove the lock file or pass --no-lock")
+
+// lockFile is the content of a lock file.
+type lockFile struct {
+	PID       int       `json:"pid"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// getLockFilePath returns a lock file path.
+// If pwd is the root of a Git repository, the lock file is created in the .git directory so that it isn't committed.
+// Otherwise, it's created in the temporary directory like the state file.
+func (c *Controller) getLockFilePath(pwd string) string {
+	gitDir := filepath.Join(pwd, ".git")
+	if f, err := afero.IsDir(c.fs, gitDir); err == nil && f {
+		return filepath.Join(gitDir, "pinact.lock")
+	}
+	h := sha256.Sum256([]byte(pwd))
+	return filepath.Join(os.TempDir(), "pinact", "lock-"+hex.EncodeToString(h[:8]))
+}
+
+// lock creates a lock file so that simultaneous runs in the same repository don't interleave file writes.
+// The returned function removes the lock file.
+// A lock file whose process isn't running is regarded as left by a killed process and is replaced.
+// If it can't be checked whether the process is running, a lock file older than staleLockAge is replaced.
+func (c *Controller) lock(logE *logrus.Entry, pwd string, now time.Time) (func(), error) {
+	lockFilePath := c.getLockFilePath(pwd)
+	if err := c.fs.MkdirAll(filepath.Dir(lockFilePath), 0o755); err != nil { //nolint:mnd
+		return nil, fmt.Errorf("create a directory for a lock file: %w", err)
+	}
+	b, err := json.Marshal(&lockFile{
+		PID:       os.Getpid(),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode a lock file as JSON: %w", err)
+	}
+	if err := c.createLockFile(lockFilePath, b); err != nil {
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		current := c.readLockFile(lockFilePath)
+		if current == nil || !isStaleLock(logE, current, now) {
+			fields := logrus.Fields{
+				"lock_file": lockFilePath,
+			}
+			if current != nil {
+				fields["pid"] = current.PID
+			}
+			return nil, logerr.WithFields(errLocked, fields) //nolint:wrapcheck
+		}
+		logE.WithFields(logrus.Fields{
+			"lock_file": lockFilePath,
+			"pid":       current.PID,
+		}).Warn("remove a stale lock file")
+		if err := c.fs.Remove(lockFilePath); err != nil {
+			return nil, fmt.Errorf("remove a stale lock file: %w", err)
+		}
+		if err := c.createLockFile(lockFilePath, b); err != nil {
+			if errors.Is(err, os.ErrExist) {
+				return nil, logerr.WithFields(errLocked, logrus.Fields{ //nolint:wrapcheck
+					"lock_file": lockFilePath,
+				})
+			}
+			return nil, err
+		}
+	}
+	return func() {
+		if err := c.fs.Remove(lockFilePath); err != nil {
+			logerr.WithError(logE, err).WithField("lock_file", lockFilePath).Warn("remove a lock file")
+		}
+	}, nil
+}
+
+// createLockFile creates a lock file exclusively.
+// If the lock file already exists, it returns an error wrapping os.ErrExist.
+func (c *Controller) createLockFile(lockFilePath string, b []byte) error {
+	f, err := c.fs.OpenFile(lockFilePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, filePermission)
+	if err != nil {
+		return fmt.Errorf("create a lock file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(b); err != nil {
+		return fmt.Errorf("write a lock file: %w", err)
+	}
+	return nil
+}
+
+// readLockFile returns the content of the lock file.
+// If the lock file can't be read, e.g. it's being written, it returns nil.
+func (c *Controller) readLockFile(lockFilePath string) *lockFile {
+	b, err := afero.ReadFile(c.fs, lockFilePath)
+	if err != nil {
+		return nil
+	}
+	l := &lockFile{}
+	if err := json.Unmarshal(b, l); err != nil {
+		return nil
+	}
+	return l
+}
+
+// isStaleLock returns true if the lock file is left by a process which isn't running.
+// A lock file of a running process isn't stale even if it's old, because a run may take long in a large repository.
+func isStaleLock(logE *logrus.Entry, l *lockFile, now time.Time) bool {
+	if l.PID > 0 {
+		exist, err := processExists(l.PID)
+		if err == nil {
+			return !exist
+		}
+		logerr.WithError(logE, err).WithField("pid", l.PID).Debug("check if the process of the lock file is running")
+	}
+	return now.Sub(l.CreatedAt) > staleLockAge
+}
@@ -0,0 +1,68 @@
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_lock(t *testing.T) {
+	t.Parallel()
+	now, err := time.Parse(time.RFC3339, "2024-06-01T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := afero.NewMemMapFs()
+	if err := fs.MkdirAll("/repo/.git", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	logE := logrus.NewEntry(logrus.New())
+	ctrl := NewController(nil, fs)
+	if p := ctrl.getLockFilePath("/repo"); p != "/repo/.git/pinact.lock" {
+		t.Fatalf("the lock file must be created in .git: %s", p)
+	}
+
+	unlock, err := ctrl.lock(logE, "/repo", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctrl.lock(logE, "/repo", now.Add(time.Minute)); !errors.Is(err, errLocked) {
+		t.Fatalf("the second lock must fail: %v", err)
+	}
+	unlock()
+	unlock, err = ctrl.lock(logE, "/repo", now)
+	if err != nil {
+		t.Fatalf("the lock must be released: %v", err)
+	}
+	defer unlock()
+
+	// The lock file of a running process isn't replaced even if it's old.
+	if _, err := ctrl.lock(logE, "/repo", now.Add(staleLockAge+time.Minute)); !errors.Is(err, errLocked) {
+		t.Fatalf("the lock file of a running process must not be replaced: %v", err)
+	}
+
+	// The lock file left by a killed process is replaced.
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(&lockFile{
+		PID:       cmd.Process.Pid,
+		CreatedAt: now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(fs, "/repo/.git/pinact.lock", b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctrl.lock(logE, "/repo", now.Add(time.Minute)); err != nil {
+		t.Fatalf("a stale lock file must be replaced: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+type Mirror struct {
+	From string `json:"from" jsonschema:"description=A regular expression of action names. The matched part is replaced with to. e.g. ^actions/"`
+	To   string `json:"to" jsonschema:"description=A replacement of the matched part of action names. Capture groups such as $1 are available. e.g. mycorp-mirror/"`
+	from *regexp.Regexp
+}
+
+func (m *Mirror) init() error {
+	p, err := regexp.Compile(m.From)
+	if err != nil {
+		return fmt.Errorf("parse mirrors[].from as a regular expression: %w", err)
+	}
+	m.from = p
+	return nil
+}
+
+// mirrorAction replaces the action with the mirror if a rule of mirrors matches.
+// The mirror is used only if the tag of the mirror points to the same commit as the upstream,
+// which guarantees that the mirror isn't tampered with.
+// If the mirror isn't used, the action is returned as is.
+func (c *Controller) mirrorAction(ctx context.Context, logE *logrus.Entry, cfg *Config, action *Action, sha, tag string) *Action {
+	for i, mirror := range cfg.Mirrors {
+		if !mirror.from.MatchString(action.Name) {
+			continue
+		}
+		rule := fmt.Sprintf("mirrors[%d]", i)
+		a := *action
+		a.Name = mirror.from.ReplaceAllString(action.Name, mirror.To)
+		logE := logE.WithFields(logrus.Fields{
+			"rule":   rule,
+			"mirror": a.Name,
+			"tag":    tag,
+		})
+		if !c.parseActionName(&a) {
+			logE.Warn("the repository owner and name can't be extracted from the mirror")
+			return action
+		}
+		mirrorSHA, _, err := c.repositoriesService.GetCommitSHA1(ctx, a.RepoOwner, a.RepoName, tag, "")
+		if err != nil {
+			logerr.WithError(logE, err).Warn("get the commit hash of the mirror. The upstream is used")
+			return action
+		}
+		if !strings.EqualFold(mirrorSHA, sha) {
+			logE.WithFields(logrus.Fields{
+				"upstream_sha": sha,
+				"mirror_sha":   mirrorSHA,
+				"help_docs":    "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/011.md",
+			}).Warn("the tag of the mirror doesn't point to the same commit as the upstream. The upstream is used")
+			return action
+		}
+		return &a
+	}
+	return action
+}
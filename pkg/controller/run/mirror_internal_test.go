@@ -0,0 +1,67 @@
+package run
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_mirrorAction(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name    string
+		line    string
+		mirrors []*Mirror
+		tag     string
+		exp     string
+	}{
+		{
+			name:    "mirror",
+			line:    "  - uses: actions/checkout@v4",
+			mirrors: []*Mirror{{From: "^actions/", To: "mycorp-mirror/"}},
+			tag:     "v4.2.2",
+			exp:     "  - uses: mycorp-mirror/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		},
+		{
+			name:    "the mirror isn't synchronized",
+			line:    "  - uses: actions/checkout@v4",
+			mirrors: []*Mirror{{From: "^actions/", To: "mycorp-mirror/"}},
+			tag:     "v4.2.1",
+			exp:     "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.1",
+		},
+		{
+			name:    "no match",
+			line:    "  - uses: suzuki-shunsuke/foo@v1",
+			mirrors: []*Mirror{{From: "^actions/", To: "mycorp-mirror/"}},
+			tag:     "v4.2.2",
+			exp:     "  - uses: suzuki-shunsuke/foo@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		},
+	}
+	ctrl := NewController(&RepositoriesServiceImpl{
+		commits: map[string]*GetCommitSHA1Result{
+			"mycorp-mirror/checkout/v4.2.2": {SHA: "11bd71901bbe5b1630ceea73d27597364c9af683"},
+			"mycorp-mirror/checkout/v4.2.1": {SHA: "9a9194f87191a7e9055e3e9b95b8cfb13023bb08"},
+		},
+	}, afero.NewMemMapFs())
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &Config{
+				Mirrors: d.mirrors,
+			}
+			if err := cfg.Init(); err != nil {
+				t.Fatal(err)
+			}
+			action := parseAction(d.line)
+			if !ctrl.parseActionName(action) {
+				t.Fatal("failed to parse the action name")
+			}
+			if got := ctrl.patchVersion(context.Background(), logE, cfg, action, "11bd71901bbe5b1630ceea73d27597364c9af683", d.tag); got != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package run
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+// notFoundTTL is how long a missing reference is cached.
+// A missing branch or tag may be created soon, e.g. a tag being released, so the TTL is short.
+const notFoundTTL = 10 * time.Minute
+
+// newNotFoundError returns an error of GitHub API that a reference isn't found.
+// It's used for missing references restored from the cache so that github.IsNotFound works as the original error.
+func newNotFoundError(key string) error {
+	return &github.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Request: &http.Request{
+				Method: http.MethodGet,
+				URL:    &url.URL{Path: key},
+			},
+		},
+		Message: "Not Found (cached)",
+	}
+}
+
+// isExpired returns true if the result is a cached missing reference whose TTL has passed.
+func (a *GetCommitSHA1Result) isExpired(now time.Time) bool {
+	return !a.notFoundAt.IsZero() && now.Sub(a.notFoundAt) > notFoundTTL
+}
+
+func (r *RepositoriesServiceImpl) getNow() time.Time {
+	if r.now == nil {
+		return time.Now()
+	}
+	return r.now()
+}
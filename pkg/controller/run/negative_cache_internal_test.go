@@ -0,0 +1,68 @@
+package run
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+// testNotFoundService is a RepositoriesService whose references are always missing.
+type testNotFoundService struct {
+	RepositoriesService
+	calls int
+}
+
+func (s *testNotFoundService) GetCommitSHA1(_ context.Context, _, _, _, _ string) (string, *github.Response, error) {
+	s.calls++
+	return "", nil, &github.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: http.StatusNotFound,
+			Request:    &http.Request{Method: http.MethodGet},
+		},
+	}
+}
+
+func TestRepositoriesServiceImpl_GetCommitSHA1_notFound(t *testing.T) {
+	t.Parallel()
+	now, err := time.Parse(time.RFC3339, "2024-06-01T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testNotFoundService{}
+	r := newRepositoriesServiceImpl(s)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+	for range 3 {
+		if _, _, err := r.GetCommitSHA1(ctx, "actions", "checkout", "v100", ""); !github.IsNotFound(err) {
+			t.Fatalf("the error must be not found: %v", err)
+		}
+	}
+	if s.calls != 1 {
+		t.Fatalf("the missing reference must be cached: %d calls", s.calls)
+	}
+
+	// The missing reference is persisted in the cache file.
+	loaded := newRepositoriesServiceImpl(s)
+	loaded.now = r.now
+	loaded.load(r.dump(now))
+	if _, _, err := loaded.GetCommitSHA1(ctx, "actions", "checkout", "v100", ""); !github.IsNotFound(err) {
+		t.Fatalf("the error restored from the cache must be not found: %v", err)
+	}
+	if s.calls != 1 {
+		t.Fatalf("the missing reference must be restored from the cache: %d calls", s.calls)
+	}
+
+	now = now.Add(notFoundTTL + time.Minute)
+	if _, _, err := r.GetCommitSHA1(ctx, "actions", "checkout", "v100", ""); !github.IsNotFound(err) {
+		t.Fatalf("the error must be not found: %v", err)
+	}
+	if s.calls != 2 {
+		t.Fatalf("the missing reference must be retried after the TTL: %d calls", s.calls)
+	}
+	if refs := r.dump(now.Add(notFoundTTL + time.Minute)).MissingRefs; len(refs) != 0 {
+		t.Fatalf("expired missing references must not be persisted: %v", refs)
+	}
+}
@@ -0,0 +1,121 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/offline"
+)
+
+// maxNotificationItems is the maximum number of items of each list in the text of notifications.
+// Long messages are hard to read in chat tools.
+const maxNotificationItems = 10
+
+// Notification is a summary of a run posted to a webhook.
+// text is formatted for Slack incoming webhooks, and other fields are for other webhooks.
+type Notification struct {
+	Text           string   `json:"text"`
+	ChangedFiles   []string `json:"changed_files"`
+	UpdatedActions []string `json:"updated_actions"`
+	Failures       []string `json:"failures"`
+	Stats          *Stats   `json:"stats"`
+}
+
+// newNotification converts findings to a notification.
+// File paths are converted to paths relative to pwd.
+func newNotification(pwd string, stats *Stats, findings []*Finding) *Notification {
+	n := &Notification{
+		ChangedFiles:   []string{},
+		UpdatedActions: []string{},
+		Failures:       []string{},
+		Stats:          stats,
+	}
+	for _, finding := range findings {
+		path := finding.File
+		if filepath.IsAbs(path) {
+			if rel, err := filepath.Rel(pwd, path); err == nil {
+				path = rel
+			}
+		}
+		path = filepath.ToSlash(path)
+		switch finding.Kind {
+		case FindingKindChanged:
+			if !slices.Contains(n.ChangedFiles, path) {
+				n.ChangedFiles = append(n.ChangedFiles, path)
+			}
+			before := parseAction(finding.Before)
+			after := parseAction(finding.After)
+			if before == nil || after == nil {
+				continue
+			}
+			s := fmt.Sprintf("%s %s -> %s", after.Name, getReportVersion(before), getReportVersion(after))
+			if !slices.Contains(n.UpdatedActions, s) {
+				n.UpdatedActions = append(n.UpdatedActions, s)
+			}
+		case FindingKindError:
+			n.Failures = append(n.Failures, fmt.Sprintf("%s:%d: %s", path, finding.Line, getFindingMessage(finding)))
+		}
+	}
+	n.Text = n.text()
+	return n
+}
+
+func (n *Notification) text() string {
+	lines := []string{
+		fmt.Sprintf("*pinact*: %d changed files, %d updated actions, %d failures", len(n.ChangedFiles), len(n.UpdatedActions), len(n.Failures)),
+	}
+	for _, list := range []struct {
+		title string
+		items []string
+	}{
+		{title: "Changed files", items: n.ChangedFiles},
+		{title: "Updated actions", items: n.UpdatedActions},
+		{title: "Failures", items: n.Failures},
+	} {
+		if len(list.items) == 0 {
+			continue
+		}
+		lines = append(lines, "", "*"+list.title+"*")
+		for _, item := range list.items[:min(len(list.items), maxNotificationItems)] {
+			lines = append(lines, "• `"+item+"`")
+		}
+		if len(list.items) > maxNotificationItems {
+			lines = append(lines, fmt.Sprintf("and %d more", len(list.items)-maxNotificationItems))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// notify posts a notification to the webhook as JSON.
+func notify(ctx context.Context, webhookURL string, n *Notification) error {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("encode a notification as JSON: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create a request to send a notification: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := offline.HTTPClient(http.DefaultClient).Do(req)
+	if err != nil {
+		// The error includes the webhook URL, which is a secret.
+		if urlErr := (&url.Error{}); errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("send a notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("send a notification: status code %d", resp.StatusCode)
+	}
+	return nil
+}
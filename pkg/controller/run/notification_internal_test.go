@@ -0,0 +1,58 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_newNotification(t *testing.T) {
+	t.Parallel()
+	stats := &Stats{}
+	findings := []*Finding{
+		{
+			Kind:   FindingKindChanged,
+			File:   "/home/foo/repo/.github/workflows/test.yaml",
+			Line:   4,
+			Before: "      - uses: actions/checkout@v2",
+			After:  "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+		},
+		{
+			Kind:   FindingKindChanged,
+			File:   "/home/foo/repo/.github/workflows/test.yaml",
+			Line:   8,
+			Before: "      - uses: actions/checkout@v2",
+			After:  "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+		},
+		{
+			Kind:    FindingKindError,
+			File:    "/home/foo/repo/.github/workflows/release.yaml",
+			Line:    10,
+			Message: "get a commit hash",
+		},
+		{
+			Kind: FindingKindDuplicateVersions,
+			File: "/home/foo/repo/.github/workflows/release.yaml",
+			Line: 12,
+		},
+	}
+	exp := &Notification{
+		Text: "*pinact*: 1 changed files, 1 updated actions, 1 failures\n" +
+			"\n" +
+			"*Changed files*\n" +
+			"• `.github/workflows/test.yaml`\n" +
+			"\n" +
+			"*Updated actions*\n" +
+			"• `actions/checkout v2 -> v2.7.0`\n" +
+			"\n" +
+			"*Failures*\n" +
+			"• `.github/workflows/release.yaml:10: get a commit hash`",
+		ChangedFiles:   []string{".github/workflows/test.yaml"},
+		UpdatedActions: []string{"actions/checkout v2 -> v2.7.0"},
+		Failures:       []string{".github/workflows/release.yaml:10: get a commit hash"},
+		Stats:          stats,
+	}
+	if diff := cmp.Diff(exp, newNotification("/home/foo/repo", stats, findings)); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,24 @@
+package run
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// isOwnerExcluded returns the rule excluding the repository owner.
+// If the owner isn't excluded, it returns an empty string.
+// Owners are compared exactly and case-insensitively like GitHub, so users don't have to write anchored regular expressions.
+func (c *Config) isOwnerExcluded(owner string) string {
+	match := func(o string) bool {
+		return strings.EqualFold(o, owner)
+	}
+	if len(c.IncludeOwners) != 0 && !slices.ContainsFunc(c.IncludeOwners, match) {
+		return "include_owners"
+	}
+	if i := slices.IndexFunc(c.ExcludeOwners, match); i != -1 {
+		c.useRule(fmt.Sprintf("exclude_owners[%d]", i))
+		return "exclude_owners"
+	}
+	return ""
+}
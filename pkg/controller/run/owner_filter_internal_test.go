@@ -0,0 +1,49 @@
+package run
+
+import "testing"
+
+func TestConfig_isOwnerExcluded(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name  string
+		cfg   *Config
+		owner string
+		exp   string
+	}{
+		{
+			name:  "no filter",
+			cfg:   &Config{},
+			owner: "actions",
+		},
+		{
+			name:  "included",
+			cfg:   &Config{IncludeOwners: []string{"actions"}},
+			owner: "Actions",
+		},
+		{
+			name:  "not included",
+			cfg:   &Config{IncludeOwners: []string{"actions"}},
+			owner: "suzuki-shunsuke",
+			exp:   "include_owners",
+		},
+		{
+			name:  "excluded",
+			cfg:   &Config{ExcludeOwners: []string{"actions"}},
+			owner: "actions",
+			exp:   "exclude_owners",
+		},
+		{
+			name:  "substrings aren't matched",
+			cfg:   &Config{ExcludeOwners: []string{"actions"}},
+			owner: "my-actions",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if got := d.cfg.isOwnerExcluded(d.owner); got != d.exp {
+				t.Fatalf("wanted %q, got %q", d.exp, got)
+			}
+		})
+	}
+}
@@ -13,7 +13,11 @@ import (
 )
 
 var (
-	usesPattern          = regexp.MustCompile(`^( +(?:- )?['"]?uses['"]? *: +)(['"]?)(.*?)@([^ '"]+)['"]?(?:( +# +(?:tag=)?)(v?\d+[^ ]*)(.*))?`)
+	// Tabs are allowed as whitespace and the indentation may be empty such as uses at the root of a document.
+	// Whitespace is captured as is so that patchLine preserves it.
+	// A comment is treated as a version annotation only if the version is followed by whitespace or the end of the line,
+	// so comments such as "# v1.${{ matrix.minor }}" are kept as is.
+	usesPattern          = regexp.MustCompile(`^([ \t]*(?:-[ \t]+)?['"]?uses['"]?[ \t]*:[ \t]+)(['"]?)(.*?)@([^ \t'"]+)(['"]?)(?:([ \t]+#[ \t]+(?:tag=)?)(v?\d+[^ \t$]*)((?:[ \t].*)?)$|([ \t]+#.*))?`)
 	fullCommitSHAPattern = regexp.MustCompile(`\b[0-9a-f]{40}\b`)
 	semverPattern        = regexp.MustCompile(`^v?\d+\.\d+\.\d+[^ ]*$`)
 	shortTagPattern      = regexp.MustCompile(`^v\d+$`)
@@ -62,77 +66,171 @@ func parseAction(line string) *Action {
 	if matches == nil {
 		return nil
 	}
+	if matches[5] != matches[2] {
+		// Quotes are mismatched such as 'actions/checkout@v4". The line isn't valid YAML.
+		return nil
+	}
+	// Characters after the match such as trailing spaces are kept in Suffix so that patchLine doesn't drop them.
 	return &Action{
-		Uses:                matches[1], // " - uses: "
-		Quote:               matches[2], // empty, ', "
-		Name:                matches[3], // local action is excluded by the regular expression because local action doesn't have version @
-		Version:             matches[4], // full commit hash, main, v3, v3.0.0
-		VersionTagSeparator: matches[5], // empty, " # ", " # tag="
-		Tag:                 matches[6], // empty, v1, v3.0.0
-		Suffix:              matches[7],
+		Uses:                matches[1],                                       // " - uses: "
+		Quote:               matches[2],                                       // empty, ', "
+		Name:                matches[3],                                       // local action is excluded by the regular expression because local action doesn't have version @
+		Version:             matches[4],                                       // full commit hash, main, v3, v3.0.0
+		VersionTagSeparator: matches[6],                                       // empty, " # ", " # tag="
+		Tag:                 matches[7],                                       // empty, v1, v3.0.0
+		Suffix:              matches[8] + matches[9] + line[len(matches[0]):], // " - pinned for reasons" of "# v3 - pinned for reasons", or the whole comment " # pinned for reasons" if there is no version annotation
 	}
 }
 
 func (c *Controller) parseLine(ctx context.Context, logE *logrus.Entry, line string, cfg *Config) (string, error) {
+	if parseAction(line) == nil {
+		// e.g. steps: [{uses: actions/checkout@v4}, {uses: actions/setup-go@v5}]
+		return c.parseFlowLine(ctx, logE, line, cfg)
+	}
+	action := c.getTargetAction(logE, line, cfg)
+	if action == nil {
+		return line, nil
+	}
+	logE = logE.WithField("action", action.Name)
+
+	parse := c.parseLineByTag
+	if cfg.AnnotateOnly {
+		parse = c.annotateOnly
+	}
+	l, err := parse(ctx, logE, line, cfg, action)
+	if err != nil && github.IsNotFound(err) {
+		c.logResolveError(logE, cfg, action, err, "parse a line")
+		return line, nil
+	}
+	return l, err
+}
+
+// getTargetAction parses a line and returns the action.
+// If the line doesn't use an action or the action is ignored, it returns nil.
+func (c *Controller) getTargetAction(logE *logrus.Entry, line string, cfg *Config) *Action {
 	action := parseAction(line)
 	if action == nil {
 		// Ignore a line if the line doesn't use an action.
 		logE.WithField("line", line).Debug("unmatch")
-		return line, nil
+		return nil
 	}
 
 	logE = logE.WithField("action", action.Name)
 
-	for _, ignoreAction := range cfg.IgnoreActions {
-		if action.Name == ignoreAction.Name {
-			logE.WithFields(logrus.Fields{
-				"line": line,
-			}).Debug("ignore the action")
-			return line, nil
+	if isDockerAction(action.Name) {
+		cfg.logIgnored(logE.WithFields(logrus.Fields{
+			"line": line,
+			"rule": "defaults.ignore_docker",
+		}), "ignore the Docker image")
+		return nil
+	}
+
+	if isIgnoredInline(logE, line, cfg) {
+		return nil
+	}
+
+	for i, ignoreAction := range cfg.IgnoreActions {
+		if ignoreAction.match(action) {
+			cfg.useRule(fmt.Sprintf("ignore_actions[%d]", i))
+			cfg.logIgnored(logE.WithFields(logrus.Fields{
+				"line":      line,
+				"rule":      fmt.Sprintf("ignore_actions[%d]", i),
+				"rule_name": ignoreAction.Name,
+			}), "ignore the action")
+			return nil
 		}
 	}
 
+	if name := cfg.matchRenovateIgnore(action); name != "" {
+		cfg.logIgnored(logE.WithFields(logrus.Fields{
+			"line":      line,
+			"rule":      "interop.renovate",
+			"rule_name": name,
+		}), "ignore the action as it's ignored by Renovate")
+		return nil
+	}
+
 	if f := c.parseActionName(action); !f {
-		logE.WithField("line", line).Debug("ignore line")
-		return line, nil
+		cfg.logIgnored(logE.WithFields(logrus.Fields{
+			"line":   line,
+			"reason": "the repository owner and name can't be extracted from the action name",
+		}), "ignore line")
+		return nil
+	}
+
+	if rule := cfg.isActionExcluded(action.Name); rule != "" {
+		cfg.logIgnored(logE.WithFields(logrus.Fields{
+			"line": line,
+			"rule": rule,
+		}), "ignore the action")
+		return nil
+	}
+
+	if rule := cfg.isOwnerExcluded(action.RepoOwner); rule != "" {
+		cfg.logIgnored(logE.WithFields(logrus.Fields{
+			"line":  line,
+			"rule":  rule,
+			"owner": action.RepoOwner,
+		}), "ignore the action")
+		return nil
 	}
+	return action
+}
 
+func (c *Controller) parseLineByTag(ctx context.Context, logE *logrus.Entry, line string, cfg *Config, action *Action) (string, error) {
 	switch getVersionType(action.Tag) {
 	case Empty:
-		return c.parseNoTagLine(ctx, logE, line, action)
+		return c.parseNoTagLine(ctx, logE, line, cfg, action)
 	case Semver:
 		// @xxx # v3.0.0
 		return c.parseSemverTagLine(ctx, logE, line, cfg, action)
 	case Shortsemver:
 		// @xxx # v3
 		// @<full commit hash> # v3
-		return c.parseShortSemverTagLine(ctx, logE, line, action)
+		return c.parseShortSemverTagLine(ctx, logE, line, cfg, action)
 	default:
 		return line, nil
 	}
 }
 
-func (c *Controller) parseNoTagLine(ctx context.Context, logE *logrus.Entry, line string, action *Action) (string, error) {
+// logResolveError logs an error that occurred when resolving an action's version.
+// If the repository isn't found, the action may be private and the access token may not have the permission.
+// In that case, the error is logged with a dedicated help document, or ignored if the owner is configured by ignore_not_found.
+func (c *Controller) logResolveError(logE *logrus.Entry, cfg *Config, action *Action, err error, msg string) {
+	if !github.IsNotFound(err) {
+		logerr.WithError(logE, err).Warn(msg)
+		return
+	}
+	if i := cfg.matchIgnoreNotFound(action.RepoOwner); i != -1 {
+		cfg.logIgnored(logerr.WithError(logE, err).WithFields(logrus.Fields{
+			"rule":       fmt.Sprintf("ignore_not_found[%d]", i),
+			"rule_owner": cfg.IgnoreNotFound[i].Owner,
+		}), "ignore the action as it isn't found")
+		return
+	}
+	logerr.WithError(logE, err).WithFields(logrus.Fields{
+		"help_docs": "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/002.md",
+	}).Warn("the action isn't found. The action may be private and the access token may not have the permission")
+}
+
+func (c *Controller) parseNoTagLine(ctx context.Context, logE *logrus.Entry, line string, cfg *Config, action *Action) (string, error) {
 	typ := getVersionType(action.Version)
 	switch typ {
 	case Shortsemver, Semver:
+	case FullCommitSHA:
+		if branch := getPinnedBranch(action); branch != "" {
+			// @<commit hash> # main (2024-06-01)
+			return c.updateBranch(ctx, logE, line, cfg, action, branch), nil
+		}
+		// @<commit hash> without a version annotation
+		return c.annotateSHA(ctx, logE, line, cfg, action)
 	default:
-		return line, nil
+		// @main
+		return c.pinBranch(ctx, logE, line, cfg, action), nil
 	}
 	// @xxx
-	if c.update {
-		// get the latest version
-		lv, err := c.getLatestVersion(ctx, logE, action.RepoOwner, action.RepoName)
-		if err != nil {
-			logerr.WithError(logE, err).Warn("get the latest version")
-			return line, nil
-		}
-		sha, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, lv, "")
-		if err != nil {
-			logerr.WithError(logE, err).Warn("get a reference")
-			return line, nil
-		}
-		return patchLine(action, sha, lv), nil
+	if c.shouldUpdate(ctx, logE, cfg, action) {
+		return c.updateToLatest(ctx, logE, line, cfg, action), nil
 	}
 
 	// Get commit hash from tag
@@ -140,12 +238,12 @@ func (c *Controller) parseNoTagLine(ctx context.Context, logE *logrus.Entry, lin
 	// > The :ref in the URL must be formatted as heads/<branch name> for branches and tags/<tag name> for tags. If the :ref doesn't match an existing ref, a 404 is returned.
 	sha, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, action.Version, "")
 	if err != nil {
-		logerr.WithError(logE, err).Warn("get a reference")
+		c.logResolveError(logE, cfg, action, err, "get a reference")
 		return line, nil
 	}
 	longVersion := action.Version
 	if typ == Shortsemver {
-		v, err := c.getLongVersionFromSHA(ctx, action, sha)
+		v, err := c.getLongVersionFromSHA(ctx, logE, action, sha)
 		if err != nil {
 			return "", err
 		}
@@ -154,29 +252,31 @@ func (c *Controller) parseNoTagLine(ctx context.Context, logE *logrus.Entry, lin
 		}
 	}
 	// @yyy # longVersion
-	return patchLine(action, sha, longVersion), nil
+	return c.patchVersion(ctx, logE, cfg, action, sha, longVersion), nil
 }
 
 func (c *Controller) parseSemverTagLine(ctx context.Context, logE *logrus.Entry, line string, cfg *Config, action *Action) (string, error) {
 	// @xxx # v3.0.0
-	if c.update {
+	if c.shouldUpdate(ctx, logE, cfg, action) {
+		upstream := c.forkUpstream(ctx, logE, cfg, action)
 		// get the latest version
-		lv, err := c.getLatestVersion(ctx, logE, action.RepoOwner, action.RepoName)
+		lv, err := c.getLatestVersion(ctx, logE, cfg, upstream.RepoOwner, upstream.RepoName)
 		if err != nil {
-			logerr.WithError(logE, err).Warn("get the latest version")
+			c.logResolveError(logE, cfg, upstream, err, "get the latest version")
 			return line, nil
 		}
 		if action.Tag != lv {
+			// Even if the repository is a fork, the action is kept and only the version is taken from the parent.
 			sha, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, lv, "")
 			if err != nil {
-				logerr.WithError(logE, err).Warn("get a reference")
+				c.logResolveError(logE, cfg, action, err, "get a reference")
 				return line, nil
 			}
-			return patchLine(action, sha, lv), nil
+			return c.patchVersion(ctx, logE, cfg, action, sha, lv), nil
 		}
 	}
 	// verify commit hash
-	if !cfg.IsVerify {
+	if !cfg.isVerified(action) {
 		return line, nil
 	}
 	// @xxx # v3.0.0
@@ -190,35 +290,85 @@ func (c *Controller) parseSemverTagLine(ctx context.Context, logE *logrus.Entry,
 	return line, nil
 }
 
-func (c *Controller) parseShortSemverTagLine(ctx context.Context, logE *logrus.Entry, line string, action *Action) (string, error) {
+func (c *Controller) parseShortSemverTagLine(ctx context.Context, logE *logrus.Entry, line string, cfg *Config, action *Action) (string, error) {
 	// @xxx # v3
 	// @<full commit hash> # v3
 	if FullCommitSHA != getVersionType(action.Version) {
 		return line, nil
 	}
-	if c.update {
-		lv, err := c.getLatestVersion(ctx, logE, action.RepoOwner, action.RepoName)
+	if c.shouldUpdate(ctx, logE, cfg, action) {
+		upstream := c.forkUpstream(ctx, logE, cfg, action)
+		lv, err := c.getLatestVersion(ctx, logE, cfg, upstream.RepoOwner, upstream.RepoName)
 		if err != nil {
-			logerr.WithError(logE, err).Warn("get the latest version")
+			c.logResolveError(logE, cfg, upstream, err, "get the latest version")
 			return line, nil
 		}
+		// Even if the repository is a fork, the action is kept and only the version is taken from the parent.
 		sha, _, err := c.repositoriesService.GetCommitSHA1(ctx, action.RepoOwner, action.RepoName, lv, "")
 		if err != nil {
-			logerr.WithError(logE, err).Warn("get a reference")
+			c.logResolveError(logE, cfg, action, err, "get a reference")
 			return line, nil
 		}
-		return patchLine(action, sha, lv), nil
+		return c.patchVersion(ctx, logE, cfg, action, sha, lv), nil
 	}
 	// replace Shortsemer to Semver
-	longVersion, err := c.getLongVersionFromSHA(ctx, action, action.Version)
+	longVersion, err := c.getLongVersionFromSHA(ctx, logE, action, action.Version)
 	if err != nil {
 		return "", err
 	}
 	if longVersion == "" {
+		if cfg.FloatingTags {
+			return c.updateFloatingTag(ctx, logE, line, cfg, action)
+		}
 		logE.Debug("failed to get a long tag")
 		return line, nil
 	}
-	return patchLine(action, action.Version, longVersion), nil
+	return c.patchVersion(ctx, logE, cfg, action, action.Version, longVersion), nil
+}
+
+// shouldUpdate returns true if the action should be updated to the latest version.
+func (c *Controller) shouldUpdate(ctx context.Context, logE *logrus.Entry, cfg *Config, action *Action) bool {
+	if !c.update {
+		return false
+	}
+	if i := cfg.matchFreezeWindow(action.Name); i != -1 {
+		cfg.logIgnored(logE.WithFields(logrus.Fields{
+			"rule":      fmt.Sprintf("freeze_windows[%d]", i),
+			"rule_name": cfg.FreezeWindows[i].Name,
+			"rule_cron": cfg.FreezeWindows[i].Cron,
+		}), "skip updating the action during the freeze window")
+		cfg.skipUpdate(skipReasonFreezeWindow)
+		return false
+	}
+	if c.isRecentlyPinned(ctx, logE, cfg, action) {
+		return false
+	}
+	if cfg.SkipArchived && c.isArchived(ctx, logE, cfg, action) {
+		return false
+	}
+	if cfg.SecurityOnly && !c.isVulnerable(ctx, logE, cfg, action) {
+		cfg.logIgnored(logE, "skip updating the action as the current version has no known vulnerability")
+		return false
+	}
+	return true
+}
+
+// patchVersion patches a line with a commit hash and a version annotation like Config.patchVersion.
+// If a rule of mirrors matches, the action is replaced with the mirror.
+// If provenance is enabled, the host resolving the commit hash is appended to the annotation.
+// If immutable_releases is enabled, " (immutable)" is appended to the annotation if the upstream release of the tag is immutable.
+func (c *Controller) patchVersion(ctx context.Context, logE *logrus.Entry, cfg *Config, action *Action, sha, tag string) string {
+	a := *c.mirrorAction(ctx, logE, cfg, action, sha, tag)
+	if cfg.Provenance {
+		a.Suffix = c.setProvenance(a.Suffix)
+	}
+	if cfg.ImmutableReleases {
+		a.Suffix = strings.TrimPrefix(a.Suffix, immutableSuffix)
+		if c.isImmutableRelease(ctx, logE, action, tag) {
+			a.Suffix = immutableSuffix + a.Suffix
+		}
+	}
+	return cfg.patchVersion(&a, sha, tag)
 }
 
 func patchLine(action *Action, version, tag string) string {
@@ -229,42 +379,87 @@ func patchLine(action *Action, version, tag string) string {
 	return action.Uses + action.Quote + action.Name + "@" + version + action.Quote + sep + tag + action.Suffix
 }
 
-func (c *Controller) getLongVersionFromSHA(ctx context.Context, action *Action, sha string) (string, error) {
+// maxTagPages is the maximum number of pages of tags scanned to get a long tag from a commit hash.
+const maxTagPages = 10
+
+func (c *Controller) getLongVersionFromSHA(ctx context.Context, logE *logrus.Entry, action *Action, sha string) (string, error) {
 	opts := &github.ListOptions{
 		PerPage: 100, //nolint:mnd
 	}
 	// Get long tag from commit hash
-	for range 10 {
+	for page := range maxTagPages {
 		tags, resp, err := c.repositoriesService.ListTags(ctx, action.RepoOwner, action.RepoName, opts)
 		if err != nil {
 			return "", fmt.Errorf("list tags: %w", err)
 		}
-		for _, tag := range tags {
-			if sha != tag.GetCommit().GetSHA() {
-				continue
-			}
-			tagName := tag.GetName()
-			if action.Tag == "" {
-				if action.Version == tagName {
-					continue
-				}
-			} else {
-				if action.Tag == tagName {
-					continue
-				}
-			}
-			if strings.HasPrefix(tagName, action.Tag) {
-				return tagName, nil
-			}
+		if tagName := findLongTag(action, sha, tags); tagName != "" {
+			return tagName, nil
 		}
 		if resp.NextPage == 0 {
 			return "", nil
 		}
+		if page == 0 {
+			// The repository has many tags, so narrow tags down by the prefix instead of scanning all tags.
+			tagName, err := c.getLongVersionFromMatchingTags(ctx, logE, action, sha)
+			if err != nil {
+				logerr.WithError(logE, err).Debug("list tags matching the prefix")
+			} else if tagName != "" {
+				return tagName, nil
+			}
+		}
 		opts.Page = resp.NextPage
 	}
+	logE.WithFields(logrus.Fields{
+		"max_pages": maxTagPages,
+		"help_docs": "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/005.md",
+	}).Warn("the repository has too many tags. pinact gave up getting a long tag from the commit hash")
 	return "", nil
 }
 
+// getLongVersionFromMatchingTags gets a long tag from a commit hash using only tags whose names start with the short tag.
+func (c *Controller) getLongVersionFromMatchingTags(ctx context.Context, logE *logrus.Entry, action *Action, sha string) (string, error) {
+	prefix := action.Tag
+	if prefix == "" {
+		prefix = action.Version
+	}
+	if prefix == "" {
+		return "", nil
+	}
+	tags, _, err := c.repositoriesService.ListMatchingTags(ctx, action.RepoOwner, action.RepoName, prefix)
+	if errors.Is(err, errTooManyAnnotatedTags) {
+		logE.WithFields(logrus.Fields{
+			"prefix":             prefix,
+			"max_annotated_tags": maxAnnotatedTags,
+		}).Warn("too many annotated tags match the prefix. Annotated tags exceeding the limit are ignored")
+	} else if err != nil {
+		return "", fmt.Errorf("list matching tags: %w", err)
+	}
+	return findLongTag(action, sha, tags), nil
+}
+
+// findLongTag returns a tag which points to the commit hash and whose name starts with the short tag.
+func findLongTag(action *Action, sha string, tags []*github.RepositoryTag) string {
+	for _, tag := range tags {
+		if sha != tag.GetCommit().GetSHA() {
+			continue
+		}
+		tagName := tag.GetName()
+		if action.Tag == "" {
+			if action.Version == tagName {
+				continue
+			}
+		} else {
+			if action.Tag == tagName {
+				continue
+			}
+		}
+		if strings.HasPrefix(tagName, action.Tag) {
+			return tagName
+		}
+	}
+	return ""
+}
+
 // parseActionName returns true if the action is a target.
 // Otherwise, it returns false.
 func (c *Controller) parseActionName(action *Action) bool {
@@ -286,7 +481,7 @@ func (c *Controller) verify(ctx context.Context, action *Action) error {
 	if action.Version == sha {
 		return nil
 	}
-	return logerr.WithFields(errors.New("action_version must be equal to commit_hash_of_version_annotation"), logrus.Fields{ //nolint:wrapcheck
+	return logerr.WithFields(errAnnotationMismatch, logrus.Fields{ //nolint:wrapcheck
 		"action":                            action.Name,
 		"action_version":                    action.Version,
 		"version_annotation":                action.Tag,
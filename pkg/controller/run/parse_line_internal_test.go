@@ -2,10 +2,14 @@ package run
 
 import (
 	"context"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/spf13/afero"
 	"github.com/suzuki-shunsuke/pinact/pkg/github"
 	"github.com/suzuki-shunsuke/pinact/pkg/util"
@@ -33,6 +37,18 @@ func Test_parseAction(t *testing.T) { //nolint:funlen
 				Tag:                 "v3",
 			},
 		},
+		{
+			name: "provenance",
+			line: "  - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3 via ghes.example.com",
+			exp: &Action{
+				Uses:                "  - uses: ",
+				Name:                "actions/checkout",
+				Version:             "8e5e7e5ab8b370d6c329ec480221332ada57f0ab",
+				VersionTagSeparator: " # ",
+				Tag:                 "v3",
+				Suffix:              " via ghes.example.com",
+			},
+		},
 		{
 			name: "checkout v2",
 			line: "  uses: actions/checkout@v2",
@@ -100,6 +116,110 @@ func Test_parseAction(t *testing.T) { //nolint:funlen
 				Quote:               "",
 			},
 		},
+		{
+			name: "extra text after the annotation",
+			line: `      - uses: actions/checkout@83b7061638ee4956cf7545a6f7efe594e5ad0247 # v3 - pinned for reasons`,
+			exp: &Action{
+				Uses:                `      - uses: `,
+				Name:                "actions/checkout",
+				Version:             "83b7061638ee4956cf7545a6f7efe594e5ad0247",
+				VersionTagSeparator: " # ",
+				Tag:                 "v3",
+				Suffix:              " - pinned for reasons",
+			},
+		},
+		{
+			name: "comment without annotation",
+			line: `      - uses: actions/checkout@v3 # pinned for reasons`,
+			exp: &Action{
+				Uses:    `      - uses: `,
+				Name:    "actions/checkout",
+				Version: "v3",
+				Suffix:  " # pinned for reasons",
+			},
+		},
+		{
+			name: "comment with an expression",
+			line: `      - uses: actions/checkout@v3 # ${{ matrix.os }}`,
+			exp: &Action{
+				Uses:    `      - uses: `,
+				Name:    "actions/checkout",
+				Version: "v3",
+				Suffix:  " # ${{ matrix.os }}",
+			},
+		},
+		{
+			name: "comment with an expression following a version",
+			line: `      - uses: actions/checkout@v3 # v1.${{ matrix.minor }}`,
+			exp: &Action{
+				Uses:    `      - uses: `,
+				Name:    "actions/checkout",
+				Version: "v3",
+				Suffix:  " # v1.${{ matrix.minor }}",
+			},
+		},
+		{
+			name: "expression after the annotation",
+			line: `      - uses: 'actions/checkout@83b7061638ee4956cf7545a6f7efe594e5ad0247' # v3 # ${{ matrix.os }}`,
+			exp: &Action{
+				Uses:                `      - uses: `,
+				Quote:               "'",
+				Name:                "actions/checkout",
+				Version:             "83b7061638ee4956cf7545a6f7efe594e5ad0247",
+				VersionTagSeparator: " # ",
+				Tag:                 "v3",
+				Suffix:              " # ${{ matrix.os }}",
+			},
+		},
+		{
+			name: "trailing spaces",
+			line: `      - uses: actions/checkout@v3  `,
+			exp: &Action{
+				Uses:    `      - uses: `,
+				Name:    "actions/checkout",
+				Version: "v3",
+				Suffix:  "  ",
+			},
+		},
+		{
+			name: "mismatched quotes",
+			line: `      - uses: 'actions/checkout@v3"`,
+		},
+		{
+			name: "unclosed quote",
+			line: `      - uses: "actions/checkout@v3`,
+		},
+		{
+			name: "tabs",
+			line: "\t\t-\tuses:\tactions/checkout@83b7061638ee4956cf7545a6f7efe594e5ad0247\t#\tv3",
+			exp: &Action{
+				Uses:                "\t\t-\tuses:\t",
+				Name:                "actions/checkout",
+				Version:             "83b7061638ee4956cf7545a6f7efe594e5ad0247",
+				VersionTagSeparator: "\t#\t",
+				Tag:                 "v3",
+			},
+		},
+		{
+			name: "no indentation",
+			line: `uses: actions/checkout@v3`,
+			exp: &Action{
+				Uses:    `uses: `,
+				Name:    "actions/checkout",
+				Version: "v3",
+			},
+		},
+		{
+			name: "extra spaces",
+			line: `    -   uses  :   actions/checkout@v3   #   v3.0.0`,
+			exp: &Action{
+				Uses:                `    -   uses  :   `,
+				Name:                "actions/checkout",
+				Version:             "v3",
+				VersionTagSeparator: "   #   ",
+				Tag:                 "v3.0.0",
+			},
+		},
 	}
 	for _, d := range data {
 		t.Run(d.name, func(t *testing.T) {
@@ -150,6 +270,81 @@ func TestController_parseLine(t *testing.T) { //nolint:funlen
 			line: `  "uses": 'actions/checkout@v2'`,
 			exp:  `  "uses": 'actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5' # v2.7.0`,
 		},
+		{
+			name: "extra text after the annotation",
+			line: "  - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3 - pinned for reasons",
+			exp:  "  - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2 - pinned for reasons",
+		},
+		{
+			name: "comment without annotation",
+			line: "  uses: actions/checkout@v2 # pinned for reasons",
+			exp:  "  uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0 # pinned for reasons",
+		},
+		{
+			name: "comment with an expression",
+			line: "  uses: actions/checkout@v2 # ${{ matrix.os }}",
+			exp:  "  uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0 # ${{ matrix.os }}",
+		},
+		{
+			name: "comment with an expression following a version",
+			line: "  uses: actions/checkout@v2 # v1.${{ matrix.minor }}",
+			exp:  "  uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0 # v1.${{ matrix.minor }}",
+		},
+		{
+			name: "expression after the annotation",
+			line: "  - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3 # ${{ matrix.os }}",
+			exp:  "  - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2 # ${{ matrix.os }}",
+		},
+		{
+			name: "not found",
+			line: "  uses: suzuki-shunsuke/private-action@v1",
+			exp:  "  uses: suzuki-shunsuke/private-action@v1",
+		},
+		{
+			name: "commit hash without annotation",
+			line: "  - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab",
+			exp:  "  - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2",
+		},
+		{
+			name: "commit hash with a comment without annotation",
+			line: "  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # pinned for reasons",
+			exp:  "  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0 # pinned for reasons",
+		},
+		{
+			name: "no tag points to the commit hash",
+			line: "  - uses: actions/checkout@0000000000000000000000000000000000000000",
+			exp:  "  - uses: actions/checkout@0000000000000000000000000000000000000000",
+		},
+		{
+			name: "reusable workflow",
+			line: "    uses: actions/checkout/.github/workflows/test.yaml@v2",
+			exp:  "    uses: actions/checkout/.github/workflows/test.yaml@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+		},
+		{
+			name: "reusable workflow (quote)",
+			line: `    "uses": "actions/checkout/.github/workflows/test.yaml@v2" # comment`,
+			exp:  `    "uses": "actions/checkout/.github/workflows/test.yaml@ee0669bd1cc54295c223e0bb666b733df41de1c5" # v2.7.0 # comment`,
+		},
+		{
+			name: "flow mappings",
+			line: "    steps: [{uses: actions/checkout@v2}, {uses: actions/checkout@v3}]",
+			exp:  "    steps: [{uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5}, {uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab}] # v2.7.0, v3.5.2",
+		},
+		{
+			name: "flow mappings with annotations",
+			line: "    steps: [{uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5}, {uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab}] # v2, v3",
+			exp:  "    steps: [{uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5}, {uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab}] # v2.7.0, v3.5.2",
+		},
+		{
+			name: "flow mapping with a comment",
+			line: "  - {name: checkout, uses: 'actions/checkout@v2'} # pinned for reasons",
+			exp:  "  - {name: checkout, uses: 'actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5'} # pinned for reasons # v2.7.0",
+		},
+		{
+			name: "flow mappings with a not found action",
+			line: "    steps: [{uses: suzuki-shunsuke/private-action@v1}, {uses: actions/checkout@v2}]",
+			exp:  "    steps: [{uses: suzuki-shunsuke/private-action@v1}, {uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5}]",
+		},
 	}
 	ctx := context.Background()
 	logE := logrus.NewEntry(logrus.New())
@@ -195,6 +390,13 @@ func TestController_parseLine(t *testing.T) { //nolint:funlen
 					"actions/checkout/v2": {
 						SHA: "ee0669bd1cc54295c223e0bb666b733df41de1c5",
 					},
+					"suzuki-shunsuke/private-action/v1": {
+						err: &github.ErrorResponse{
+							Response: &http.Response{
+								StatusCode: http.StatusNotFound,
+							},
+						},
+					},
 				},
 			}, afero.NewMemMapFs())
 			line, err := ctrl.parseLine(ctx, logE, d.line, &Config{})
@@ -211,6 +413,91 @@ func TestController_parseLine(t *testing.T) { //nolint:funlen
 	}
 }
 
+func TestController_parseLine_ignoreNotFound(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name           string
+		line           string
+		ignoreNotFound []*IgnoreNotFound
+		warned         bool
+	}{
+		{
+			name:   "not ignored",
+			line:   "  uses: suzuki-shunsuke/private-action@v1",
+			warned: true,
+		},
+		{
+			name: "ignored",
+			line: "  uses: suzuki-shunsuke/private-action@v1",
+			ignoreNotFound: []*IgnoreNotFound{
+				{Owner: "^suzuki-shunsuke$"},
+			},
+		},
+		{
+			name: "another owner isn't ignored",
+			line: "  uses: suzuki-shunsuke/private-action@v1",
+			ignoreNotFound: []*IgnoreNotFound{
+				{Owner: "^suzuki-shunsuke-org$"},
+			},
+			warned: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			logger, hook := test.NewNullLogger()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				commits: map[string]*GetCommitSHA1Result{
+					"suzuki-shunsuke/private-action/v1": {
+						err: &github.ErrorResponse{
+							Response: &http.Response{
+								StatusCode: http.StatusNotFound,
+							},
+						},
+					},
+				},
+			}, afero.NewMemMapFs())
+			reporter := &testReporter{}
+			ctrl.SetReporter(reporter)
+			cfg := &Config{
+				IgnoreNotFound: d.ignoreNotFound,
+			}
+			if err := cfg.Init(); err != nil {
+				t.Fatal(err)
+			}
+			line, err := ctrl.parseLine(context.Background(), logrus.NewEntry(logger), d.line, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line != d.line {
+				t.Fatalf("the line must not be changed: %s", line)
+			}
+			if len(reporter.findings) != 0 {
+				t.Fatalf("no finding must be reported: %d", len(reporter.findings))
+			}
+			warnings := []logrus.Fields{}
+			for _, entry := range hook.AllEntries() {
+				if entry.Level <= logrus.WarnLevel {
+					warnings = append(warnings, logrus.Fields{
+						"message":   entry.Message,
+						"help_docs": entry.Data["help_docs"],
+					})
+				}
+			}
+			exp := []logrus.Fields{}
+			if d.warned {
+				exp = append(exp, logrus.Fields{
+					"message":   "the action isn't found. The action may be private and the access token may not have the permission",
+					"help_docs": "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/002.md",
+				})
+			}
+			if diff := cmp.Diff(exp, warnings); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
 func Test_patchLine(t *testing.T) {
 	t.Parallel()
 	data := []struct {
@@ -255,3 +542,394 @@ func Test_patchLine(t *testing.T) {
 		})
 	}
 }
+
+// FuzzPatchLine checks that patchLine round-trips lines parsed by parseAction.
+// Patching a line must keep the prefix, quotes, the action name, and the suffix.
+func FuzzPatchLine(f *testing.F) {
+	for _, line := range []string{
+		"  - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3",
+		"  uses: actions/checkout@v2",
+		`  - "uses": 'actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab' # v3`,
+		`  'uses': "actions/checkout@v2"`,
+		"      - uses: actions/checkout@83b7061638ee4956cf7545a6f7efe594e5ad0247 # tag=v3",
+		"      - uses: actions/checkout@83b7061638ee4956cf7545a6f7efe594e5ad0247 # v3 - pinned for reasons",
+		"      - uses: actions/checkout@v3 # pinned for reasons",
+		"      - uses: actions/checkout@v3  ",
+		"    uses: suzuki-shunsuke/foo/.github/workflows/test.yaml@main",
+		"\t- uses:\tactions/checkout@v3\t# v3",
+		"uses: actions/checkout@v3",
+	} {
+		f.Add(line)
+	}
+	const sha = "ee0669bd1cc54295c223e0bb666b733df41de1c5"
+	f.Fuzz(func(t *testing.T, line string) {
+		action := parseAction(line)
+		if action == nil {
+			return
+		}
+		if action.VersionTagSeparator != "" {
+			if got := patchLine(action, action.Version, action.Tag); got != line {
+				t.Fatalf("the line isn't round-tripped: wanted %q, got %q", line, got)
+			}
+		}
+		if action.Suffix != "" && !strings.HasPrefix(action.Suffix, " ") {
+			// Characters following the version without spaces such as a closing quote of YAML flow scalars
+			// would be concatenated with the new annotation.
+			return
+		}
+		patched := patchLine(action, sha, "v1.2.3")
+		got := parseAction(patched)
+		if got == nil {
+			t.Fatalf("the patched line can't be parsed: %q", patched)
+		}
+		exp := *action
+		exp.Version = sha
+		exp.Tag = "v1.2.3"
+		if exp.VersionTagSeparator == "" {
+			exp.VersionTagSeparator = " # "
+		}
+		if diff := cmp.Diff(&exp, got); diff != "" {
+			t.Fatalf("the patched line %q is corrupted: %s", patched, diff)
+		}
+	})
+}
+
+func TestController_shouldUpdate(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		update bool
+		cfg    *Config
+		action *Action
+		exp    bool
+	}{
+		{
+			name:   "not update",
+			cfg:    &Config{},
+			action: &Action{RepoOwner: "actions", RepoName: "checkout"},
+		},
+		{
+			name:   "update",
+			update: true,
+			cfg:    &Config{},
+			action: &Action{RepoOwner: "suzuki-shunsuke", RepoName: "archived-action"},
+			exp:    true,
+		},
+		{
+			name:   "skip archived",
+			update: true,
+			cfg:    &Config{SkipArchived: true},
+			action: &Action{RepoOwner: "suzuki-shunsuke", RepoName: "archived-action"},
+		},
+		{
+			name:   "not archived",
+			update: true,
+			cfg:    &Config{SkipArchived: true},
+			action: &Action{RepoOwner: "actions", RepoName: "checkout"},
+			exp:    true,
+		},
+	}
+	ctx := context.Background()
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				repos: map[string]*GetRepositoryResult{
+					"actions/checkout": {
+						Repository: &github.Repository{},
+					},
+					"suzuki-shunsuke/archived-action": {
+						Repository: &github.Repository{
+							Archived: util.BoolP(true),
+						},
+					},
+				},
+			}, afero.NewMemMapFs())
+			ctrl.update = d.update
+			if f := ctrl.shouldUpdate(ctx, logE, d.cfg, d.action); f != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, f)
+			}
+		})
+	}
+}
+
+func TestController_parseLine_floatingTags(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		line string
+		exp  string
+	}{
+		{
+			name: "pin a floating tag",
+			line: "  uses: suzuki-shunsuke/floating-action@v1",
+			exp:  "  uses: suzuki-shunsuke/floating-action@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v1 (2024-06-01)",
+		},
+		{
+			name: "the floating tag has moved",
+			line: "  uses: suzuki-shunsuke/floating-action@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v1 (2024-05-01)",
+			exp:  "  uses: suzuki-shunsuke/floating-action@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v1 (2024-06-01)",
+		},
+		{
+			name: "the floating tag hasn't moved",
+			line: "  uses: suzuki-shunsuke/floating-action@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v1 (2024-05-01)",
+			exp:  "  uses: suzuki-shunsuke/floating-action@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v1 (2024-05-01)",
+		},
+	}
+	ctx := context.Background()
+	logE := logrus.NewEntry(logrus.New())
+	now, err := time.Parse(time.RFC3339, "2024-06-01T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				tags: map[string]*ListTagsResult{
+					"suzuki-shunsuke/floating-action/0": {
+						Tags: []*github.RepositoryTag{
+							{
+								Name: util.StrP("v1"),
+								Commit: &github.Commit{
+									SHA: util.StrP("ee0669bd1cc54295c223e0bb666b733df41de1c5"),
+								},
+							},
+						},
+						Response: &github.Response{},
+					},
+				},
+				commits: map[string]*GetCommitSHA1Result{
+					"suzuki-shunsuke/floating-action/v1": {
+						SHA: "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+					},
+				},
+			}, afero.NewMemMapFs())
+			line, err := ctrl.parseLine(ctx, logE, d.line, &Config{
+				FloatingTags: true,
+				Now:          now,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line != d.exp {
+				t.Fatalf(`wanted %s, got %s`, d.exp, line)
+			}
+		})
+	}
+}
+
+func TestController_parseLine_annotateOnly(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		line string
+		exp  string
+	}{
+		{
+			name: "add an annotation",
+			line: "  uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab",
+			exp:  "  uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2",
+		},
+		{
+			name: "repair a wrong annotation",
+			line: "  uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v2.7.0 - pinned for reasons",
+			exp:  "  uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2 - pinned for reasons",
+		},
+		{
+			name: "expand a short annotation",
+			line: "  uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3",
+			exp:  "  uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2",
+		},
+		{
+			name: "correct annotation",
+			line: "  uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2",
+			exp:  "  uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2",
+		},
+		{
+			name: "not pinned",
+			line: "  uses: actions/checkout@v3",
+			exp:  "  uses: actions/checkout@v3",
+		},
+	}
+	ctx := context.Background()
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				tags: map[string]*ListTagsResult{
+					"actions/checkout/0": {
+						Tags: []*github.RepositoryTag{
+							{
+								Name: util.StrP("v3"),
+								Commit: &github.Commit{
+									SHA: util.StrP("8e5e7e5ab8b370d6c329ec480221332ada57f0ab"),
+								},
+							},
+							{
+								Name: util.StrP("v3.5.2"),
+								Commit: &github.Commit{
+									SHA: util.StrP("8e5e7e5ab8b370d6c329ec480221332ada57f0ab"),
+								},
+							},
+						},
+						Response: &github.Response{},
+					},
+				},
+				commits: map[string]*GetCommitSHA1Result{
+					"actions/checkout/v2.7.0": {
+						SHA: "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+					},
+					"actions/checkout/v3.5.2": {
+						SHA: "8e5e7e5ab8b370d6c329ec480221332ada57f0ab",
+					},
+				},
+			}, afero.NewMemMapFs())
+			line, err := ctrl.parseLine(ctx, logE, d.line, &Config{
+				AnnotateOnly: true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line != d.exp {
+				t.Fatalf(`wanted %s, got %s`, d.exp, line)
+			}
+		})
+	}
+}
+
+func Test_isIgnoredInline(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		line string
+		exp  bool
+	}{
+		{
+			name: "no comment",
+			line: "  uses: actions/checkout@v4",
+		},
+		{
+			name: "ignore",
+			line: "  uses: actions/checkout@v4 # pinact:ignore",
+			exp:  true,
+		},
+		{
+			name: "not expired",
+			line: "  uses: actions/checkout@v4 # pinact:ignore until=2024-06-01",
+			exp:  true,
+		},
+		{
+			name: "expired",
+			line: "  uses: actions/checkout@v4 # pinact:ignore until=2024-05-31",
+		},
+		{
+			name: "invalid date",
+			line: "  uses: actions/checkout@v4 # pinact:ignore until=tomorrow",
+		},
+	}
+	logE := logrus.NewEntry(logrus.New())
+	now, err := time.Parse(time.RFC3339, "2024-06-01T12:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if f := isIgnoredInline(logE, d.line, &Config{Now: now}); f != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, f)
+			}
+		})
+	}
+}
+
+func TestController_getLongVersionFromSHA(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		action *Action
+		sha    string
+		exp    string
+	}{
+		{
+			name: "first page",
+			action: &Action{
+				RepoOwner: "kubernetes",
+				RepoName:  "kubernetes",
+				Version:   "8e5e7e5ab8b370d6c329ec480221332ada57f0ab",
+				Tag:       "v1.30",
+			},
+			sha: "8e5e7e5ab8b370d6c329ec480221332ada57f0ab",
+			exp: "v1.30.1",
+		},
+		{
+			name: "matching tags",
+			action: &Action{
+				RepoOwner: "kubernetes",
+				RepoName:  "kubernetes",
+				Version:   "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+				Tag:       "v1.29",
+			},
+			sha: "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+			exp: "v1.29.5",
+		},
+	}
+	ctx := context.Background()
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				tags: map[string]*ListTagsResult{
+					"kubernetes/kubernetes/0": {
+						Tags: []*github.RepositoryTag{
+							{
+								Name: util.StrP("v1.30"),
+								Commit: &github.Commit{
+									SHA: util.StrP("8e5e7e5ab8b370d6c329ec480221332ada57f0ab"),
+								},
+							},
+							{
+								Name: util.StrP("v1.30.1"),
+								Commit: &github.Commit{
+									SHA: util.StrP("8e5e7e5ab8b370d6c329ec480221332ada57f0ab"),
+								},
+							},
+						},
+						Response: &github.Response{
+							NextPage: 2,
+						},
+					},
+				},
+				matchingTags: map[string]*ListMatchingTagsResult{
+					"kubernetes/kubernetes/v1.29": {
+						Tags: []*github.RepositoryTag{
+							{
+								Name: util.StrP("v1.29"),
+								Commit: &github.Commit{
+									SHA: util.StrP("ee0669bd1cc54295c223e0bb666b733df41de1c5"),
+								},
+							},
+							{
+								Name: util.StrP("v1.29.5"),
+								Commit: &github.Commit{
+									SHA: util.StrP("ee0669bd1cc54295c223e0bb666b733df41de1c5"),
+								},
+							},
+						},
+						Response: &github.Response{},
+					},
+				},
+			}, afero.NewMemMapFs())
+			v, err := ctrl.getLongVersionFromSHA(ctx, logE, d.action, d.sha)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+// parseMaxPinAge parses --max-pin-age and --since.
+// In addition to the format of time.ParseDuration, days such as 365d are supported.
+func parseMaxPinAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("parse the number of days: %w", err)
+		}
+		if n <= 0 {
+			return 0, fmt.Errorf("the number of days must be positive: %s", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil //nolint:mnd
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("parse a duration: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("the duration must be positive: %s", s)
+	}
+	return d, nil
+}
+
+// checkPinAge outputs findings of actions pinned to commits older than --max-pin-age.
+// The age is the duration since the pinned commit was committed.
+func (c *Controller) checkPinAge(ctx context.Context, logE *logrus.Entry, workflowFilePath string, lines []string, cfg *Config) {
+	for i, line := range lines {
+		action := parseAction(line)
+		if action == nil || getVersionType(action.Version) != FullCommitSHA || !c.parseActionName(action) {
+			continue
+		}
+		logE := logE.WithFields(logrus.Fields{
+			"action":      action.Name,
+			"line_number": i + 1,
+		})
+		if isIgnoredInline(logE, line, cfg) {
+			continue
+		}
+		committedAt, _, err := c.repositoriesService.GetCommitDate(ctx, action.RepoOwner, action.RepoName, action.Version)
+		if err != nil {
+			logerr.WithError(logE, err).Warn("get the date of the pinned commit")
+			continue
+		}
+		age := cfg.Now.Sub(committedAt)
+		days := int(age.Hours() / 24) //nolint:mnd
+		logE.WithFields(logrus.Fields{
+			"committed_at": committedAt.Format(time.RFC3339),
+			"age_days":     days,
+		}).Debug("check the age of the pinned commit")
+		if age <= cfg.MaxPinAge {
+			continue
+		}
+		version := action.Tag
+		if version == "" {
+			version = action.Version
+		}
+		logE.WithFields(logrus.Fields{
+			"version":      version,
+			"committed_at": committedAt.Format(time.RFC3339),
+			"age_days":     days,
+			"help_docs":    "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/009.md",
+		}).Warn("the pinned version is older than --max-pin-age")
+		c.reporter.OnFinding(&Finding{
+			Kind:    FindingKindOldPin,
+			File:    workflowFilePath,
+			Line:    i + 1,
+			Before:  line,
+			After:   line,
+			Message: fmt.Sprintf("%s is pinned to a commit committed %d days ago", version, days),
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+func Test_parseMaxPinAge(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name  string
+		s     string
+		exp   time.Duration
+		isErr bool
+	}{
+		{
+			name: "days",
+			s:    "365d",
+			exp:  365 * 24 * time.Hour,
+		},
+		{
+			name: "hours",
+			s:    "720h",
+			exp:  720 * time.Hour,
+		},
+		{
+			name:  "invalid days",
+			s:     "1.5d",
+			isErr: true,
+		},
+		{
+			name:  "zero",
+			s:     "0d",
+			isErr: true,
+		},
+		{
+			name:  "negative",
+			s:     "-1h",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			age, err := parseMaxPinAge(d.s)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if age != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, age)
+			}
+		})
+	}
+}
+
+func TestController_checkPinAge(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctrl := NewController(&RepositoriesServiceImpl{
+		commitDates: map[string]*GetCommitDateResult{
+			"actions/checkout/11bd71901bbe5b1630ceea73d27597364c9af683": {
+				Date: now.AddDate(0, -3, 0),
+			},
+			"actions/setup-go/0c52d547c9bc32b1aa3301fd7a9cb496313a4491": {
+				Date: now.AddDate(-2, 0, 0),
+			},
+		},
+	}, afero.NewMemMapFs())
+	reporter := &testReporter{}
+	ctrl.SetReporter(reporter)
+	lines := []string{
+		"      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		"      - uses: actions/setup-go@0c52d547c9bc32b1aa3301fd7a9cb496313a4491 # v5.0.0",
+		"      - uses: actions/setup-go@0c52d547c9bc32b1aa3301fd7a9cb496313a4491 # v5.0.0 # pinact:ignore",
+		"      - uses: actions/cache@v4",
+	}
+	cfg := &Config{
+		Now:       now,
+		MaxPinAge: 365 * 24 * time.Hour,
+	}
+	ctrl.checkPinAge(context.Background(), logrus.NewEntry(logrus.New()), "test.yaml", lines, cfg)
+	if len(reporter.findings) != 1 {
+		t.Fatalf("wanted 1 finding, got %d", len(reporter.findings))
+	}
+	finding := reporter.findings[0]
+	if finding.Kind != FindingKindOldPin {
+		t.Fatalf("wanted %s, got %s", FindingKindOldPin, finding.Kind)
+	}
+	if finding.Line != 2 {
+		t.Fatalf("wanted 2, got %d", finding.Line)
+	}
+}
+
+// commitDateRecorder records repositories passed to GetCommitDate.
+type commitDateRecorder struct {
+	RepositoriesService
+	repos []string
+}
+
+func (r *commitDateRecorder) GetCommitDate(_ context.Context, owner, repo, _ string) (time.Time, *github.Response, error) {
+	r.repos = append(r.repos, owner+"/"+repo)
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), nil, nil
+}
+
+func TestController_checkPinAge_repository(t *testing.T) {
+	t.Parallel()
+	recorder := &commitDateRecorder{}
+	ctrl := NewController(recorder, afero.NewMemMapFs())
+	lines := []string{
+		"      - uses: actions/cache/restore@0c45773b623bea8c8e75f6c82b208c3cf94ea4f9 # v4.0.2",
+		"      - uses: suzuki-shunsuke/foo/.github/workflows/test.yaml@11bd71901bbe5b1630ceea73d27597364c9af683 # v1.0.0",
+	}
+	cfg := &Config{
+		Now:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		MaxPinAge: 365 * 24 * time.Hour,
+	}
+	ctrl.checkPinAge(context.Background(), logrus.NewEntry(logrus.New()), "test.yaml", lines, cfg)
+	exp := []string{"actions/cache", "suzuki-shunsuke/foo"}
+	if diff := cmp.Diff(exp, recorder.repos); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,365 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+const (
+	GroupByAll    = "all"
+	GroupByAction = "action"
+	GroupByMajor  = "major"
+	branchPrefix  = "pinact/"
+)
+
+type GitService interface {
+	GetRef(ctx context.Context, owner, repo, ref string) (*github.Reference, *github.Response, error)
+	GetCommit(ctx context.Context, owner, repo, sha string) (*github.Commit, *github.Response, error)
+	CreateTree(ctx context.Context, owner, repo, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error)
+	CreateCommit(ctx context.Context, owner, repo string, commit *github.Commit, opts *github.CreateCommitOptions) (*github.Commit, *github.Response, error)
+	CreateRef(ctx context.Context, owner, repo string, ref *github.Reference) (*github.Reference, *github.Response, error)
+	UpdateRef(ctx context.Context, owner, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error)
+}
+
+type ParamPR struct {
+	WorkflowFilePaths []string
+	ConfigFilePath    string
+	PWD               string
+	Owner             string
+	Repo              string
+	// Base is a base branch of pull requests. If this is empty, the default branch is used.
+	Base    string
+	GroupBy string
+	DryRun  bool
+	Now     time.Time
+}
+
+// actionUpdate is an update of a line.
+type actionUpdate struct {
+	File   string
+	Line   int
+	Before string
+	After  string
+	Action string
+	// From and To are versions before and after the update. From is empty if the action isn't pinned with a version.
+	From string
+	To   string
+}
+
+// updateGroup is a set of updates landing in a pull request.
+type updateGroup struct {
+	Key     string
+	Title   string
+	Updates []*actionUpdate
+}
+
+// CreatePullRequests updates actions and creates pull requests grouped by the strategy.
+// Commits are created via GitHub API, so the local repository isn't modified.
+// If a pull request of the group already exists, its branch is updated.
+func (c *Controller) CreatePullRequests(ctx context.Context, logE *logrus.Entry, param *ParamPR) error {
+	switch param.GroupBy {
+	case GroupByAll, GroupByAction, GroupByMajor:
+	default:
+		return fmt.Errorf("--group-by must be one of %s, %s, and %s: %s", GroupByAll, GroupByAction, GroupByMajor, param.GroupBy)
+	}
+	cfg := &Config{}
+	if err := c.readConfig(logE, param.ConfigFilePath, param.PWD, cfg); err != nil {
+		return err
+	}
+	if err := cfg.Init(); err != nil {
+		return fmt.Errorf("initialize the configuration: %w", err)
+	}
+	cfg.Now = param.Now
+	cfg.setFreezeWindows(param.Now)
+	if cfg.Interop != nil && cfg.Interop.Renovate {
+		if err := c.readRenovateIgnores(logE, param.PWD, cfg); err != nil {
+			return err
+		}
+	}
+	defer c.enableRateLimits(cfg)()
+	tmpls, err := newPRTemplates(cfg.PullRequest)
+	if err != nil {
+		return err
+	}
+	workflowFilePaths, err := c.searchFiles(logE, param.WorkflowFilePaths, cfg, param.PWD)
+	if err != nil {
+		return fmt.Errorf("search target files: %w", err)
+	}
+	files := map[string][]string{}
+	updates := []*actionUpdate{}
+	for _, workflowFilePath := range workflowFilePaths {
+		logE := logE.WithField("workflow_file", workflowFilePath)
+		path := workflowFilePath
+		if filepath.IsAbs(path) {
+			rel, err := filepath.Rel(param.PWD, path)
+			if err != nil {
+				logerr.WithError(logE, err).Warn("get a relative path")
+				continue
+			}
+			path = rel
+		} else {
+			workflowFilePath = filepath.Join(param.PWD, workflowFilePath)
+		}
+		path = filepath.ToSlash(path)
+		lines, err := c.readWorkflow(workflowFilePath)
+		if err != nil {
+			logerr.WithError(logE, err).Warn("read a workflow")
+			continue
+		}
+		files[path] = lines
+		for i, line := range lines {
+			l, err := c.parseLine(ctx, logE, line, cfg)
+			if err != nil {
+				logerr.WithError(logE, err).Error("parse a line")
+				continue
+			}
+			if l == line {
+				continue
+			}
+			if err := checkSecrets(l); err != nil {
+				return logerr.WithFields(err, logrus.Fields{ //nolint:wrapcheck
+					"workflow_file": path,
+					"line_number":   i + 1,
+				})
+			}
+			updates = append(updates, newActionUpdate(path, i, line, l))
+		}
+	}
+	groups := groupUpdates(updates, param.GroupBy)
+	if len(groups) == 0 {
+		logE.Info("no update")
+		return nil
+	}
+	if param.DryRun {
+		for _, group := range groups {
+			title, _, _, err := tmpls.render(group)
+			if err != nil {
+				return err
+			}
+			logE.WithFields(logrus.Fields{
+				"branch":  branchPrefix + group.Key,
+				"title":   title,
+				"updates": len(group.Updates),
+			}).Info("a pull request would be created")
+		}
+		return nil
+	}
+	base := param.Base
+	if base == "" {
+		repo, _, err := c.repositoriesService.Get(ctx, param.Owner, param.Repo)
+		if err != nil {
+			return fmt.Errorf("get a repository to get the default branch: %w", err)
+		}
+		base = repo.GetDefaultBranch()
+	}
+	for _, group := range groups {
+		logE := logE.WithField("branch", branchPrefix+group.Key)
+		if err := c.createPullRequest(ctx, logE, param, base, files, group, tmpls); err != nil {
+			logerr.WithError(logE, err).Error("create a pull request")
+		}
+	}
+	return nil
+}
+
+func newActionUpdate(path string, index int, before, after string) *actionUpdate {
+	u := &actionUpdate{
+		File:   path,
+		Line:   index + 1,
+		Before: before,
+		After:  after,
+	}
+	if action := parseAction(before); action != nil {
+		u.Action = action.Name
+		u.From = getActionVersion(action)
+	}
+	if action := parseAction(after); action != nil {
+		u.To = getActionVersion(action)
+	}
+	return u
+}
+
+// getActionVersion returns a version of an action.
+// If the action is pinned with a commit hash, the version annotation is returned.
+func getActionVersion(action *Action) string {
+	if getVersionType(action.Version) == FullCommitSHA {
+		return action.Tag
+	}
+	return action.Version
+}
+
+// getMajorVersion returns the major version. If the version can't be parsed, it returns an empty string.
+func getMajorVersion(v string) string {
+	ver, err := version.NewVersion(v)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("v%d", ver.Segments()[0])
+}
+
+// isMajorUpdate returns true if the major version is changed.
+// If either version can't be parsed, the update is regarded as a major update to be on the safe side.
+func isMajorUpdate(u *actionUpdate) bool {
+	from, to := getMajorVersion(u.From), getMajorVersion(u.To)
+	if from == "" || to == "" {
+		return u.From != u.To
+	}
+	return from != to
+}
+
+var branchNamePattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func groupUpdates(updates []*actionUpdate, groupBy string) []*updateGroup {
+	groups := []*updateGroup{}
+	indexes := map[string]int{}
+	for _, u := range updates {
+		var key, title string
+		switch groupBy {
+		case GroupByAction:
+			key = u.Action
+			title = "Update " + u.Action
+		case GroupByMajor:
+			if isMajorUpdate(u) {
+				key = u.Action + "-" + getMajorVersion(u.To)
+				title = fmt.Sprintf("Update %s to %s", u.Action, getMajorVersion(u.To))
+			} else {
+				key = "minor-and-patch"
+				title = "Update GitHub Actions (minor and patch)"
+			}
+		default:
+			key = GroupByAll
+			title = "Update GitHub Actions"
+		}
+		key = strings.Trim(branchNamePattern.ReplaceAllString(key, "-"), "-")
+		if i, ok := indexes[key]; ok {
+			groups[i].Updates = append(groups[i].Updates, u)
+			continue
+		}
+		indexes[key] = len(groups)
+		groups = append(groups, &updateGroup{
+			Key:     key,
+			Title:   title,
+			Updates: []*actionUpdate{u},
+		})
+	}
+	return groups
+}
+
+func (c *Controller) createPullRequest(ctx context.Context, logE *logrus.Entry, param *ParamPR, base string, files map[string][]string, group *updateGroup, tmpls *prTemplates) error {
+	title, body, commitMessage, err := tmpls.render(group)
+	if err != nil {
+		return err
+	}
+	if err := checkSecrets(title, body, commitMessage); err != nil {
+		return err
+	}
+	baseRef, _, err := c.gitService.GetRef(ctx, param.Owner, param.Repo, "heads/"+base)
+	if err != nil {
+		return fmt.Errorf("get the base branch: %w", err)
+	}
+	baseSHA := baseRef.GetObject().GetSHA()
+	baseCommit, _, err := c.gitService.GetCommit(ctx, param.Owner, param.Repo, baseSHA)
+	if err != nil {
+		return fmt.Errorf("get the base commit: %w", err)
+	}
+	tree, _, err := c.gitService.CreateTree(ctx, param.Owner, param.Repo, baseCommit.GetTree().GetSHA(), getTreeEntries(files, group))
+	if err != nil {
+		return fmt.Errorf("create a tree: %w", err)
+	}
+	commit, _, err := c.gitService.CreateCommit(ctx, param.Owner, param.Repo, &github.Commit{
+		Message: util.StrP(commitMessage),
+		Tree:    &github.Tree{SHA: tree.SHA},
+		Parents: []*github.Commit{{SHA: util.StrP(baseSHA)}},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("create a commit: %w", err)
+	}
+	branch := branchPrefix + group.Key
+	ref := &github.Reference{
+		Ref:    util.StrP("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: commit.SHA},
+	}
+	if _, _, err := c.gitService.GetRef(ctx, param.Owner, param.Repo, "heads/"+branch); err != nil {
+		if !github.IsNotFound(err) {
+			return fmt.Errorf("get a branch: %w", err)
+		}
+		if _, _, err := c.gitService.CreateRef(ctx, param.Owner, param.Repo, ref); err != nil {
+			return fmt.Errorf("create a branch: %w", err)
+		}
+	} else if _, _, err := c.gitService.UpdateRef(ctx, param.Owner, param.Repo, ref, true); err != nil {
+		return fmt.Errorf("update a branch: %w", err)
+	}
+	prs, _, err := c.pullRequestsService.List(ctx, param.Owner, param.Repo, &github.PullRequestListOptions{
+		State: "open",
+		Head:  param.Owner + ":" + branch,
+		Base:  base,
+	})
+	if err != nil {
+		return fmt.Errorf("list pull requests: %w", err)
+	}
+	if len(prs) != 0 {
+		logE.WithField("pull_request", prs[0].GetHTMLURL()).Info("update a pull request")
+		return nil
+	}
+	pr, _, err := c.pullRequestsService.Create(ctx, param.Owner, param.Repo, &github.NewPullRequest{
+		Title: util.StrP(title),
+		Head:  util.StrP(branch),
+		Base:  util.StrP(base),
+		Body:  util.StrP(body),
+	})
+	if err != nil {
+		return fmt.Errorf("create a pull request: %w", err)
+	}
+	logE.WithField("pull_request", pr.GetHTMLURL()).Info("create a pull request")
+	return nil
+}
+
+// getTreeEntries returns files to which only updates of the group are applied.
+func getTreeEntries(files map[string][]string, group *updateGroup) []*github.TreeEntry {
+	changed := map[string][]string{}
+	for _, u := range group.Updates {
+		lines, ok := changed[u.File]
+		if !ok {
+			lines = slices.Clone(files[u.File])
+			changed[u.File] = lines
+		}
+		lines[u.Line-1] = u.After
+	}
+	paths := make([]string, 0, len(changed))
+	for path := range changed {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	entries := make([]*github.TreeEntry, len(paths))
+	for i, path := range paths {
+		entries[i] = &github.TreeEntry{
+			Path:    util.StrP(path),
+			Mode:    util.StrP("100644"),
+			Type:    util.StrP("blob"),
+			Content: util.StrP(strings.Join(changed[path], "\n") + "\n"),
+		}
+	}
+	return entries
+}
+
+func getPullRequestBody(group *updateGroup) string {
+	lines := []string{"This pull request is created by [pinact](https://github.com/suzuki-shunsuke/pinact).", ""}
+	for _, u := range group.Updates {
+		from := u.From
+		if from == "" {
+			from = "(unpinned)"
+		}
+		lines = append(lines, fmt.Sprintf("- `%s`: %s => %s (%s:%d)", u.Action, from, u.To, u.File, u.Line))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,88 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_groupUpdates(t *testing.T) {
+	t.Parallel()
+	checkout := newActionUpdate(".github/workflows/test.yaml", 3,
+		"      - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2",
+		"      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2")
+	setupGo := newActionUpdate(".github/workflows/test.yaml", 4,
+		"      - uses: actions/setup-go@v5.0.0",
+		"      - uses: actions/setup-go@f111f3307d8850f501ac008e886eec1fd1932a34 # v5.3.0")
+	cache := newActionUpdate(".github/workflows/build.yaml", 5,
+		"      - uses: actions/cache@v4",
+		"      - uses: actions/cache@1bd1e32a3bdc45362d1e726936510720a7c30a57 # v4.2.0")
+	updates := []*actionUpdate{checkout, setupGo, cache}
+	data := []struct {
+		name    string
+		groupBy string
+		exp     []*updateGroup
+	}{
+		{
+			name:    "all",
+			groupBy: GroupByAll,
+			exp: []*updateGroup{
+				{Key: "all", Title: "Update GitHub Actions", Updates: updates},
+			},
+		},
+		{
+			name:    "action",
+			groupBy: GroupByAction,
+			exp: []*updateGroup{
+				{Key: "actions-checkout", Title: "Update actions/checkout", Updates: []*actionUpdate{checkout}},
+				{Key: "actions-setup-go", Title: "Update actions/setup-go", Updates: []*actionUpdate{setupGo}},
+				{Key: "actions-cache", Title: "Update actions/cache", Updates: []*actionUpdate{cache}},
+			},
+		},
+		{
+			name:    "major",
+			groupBy: GroupByMajor,
+			exp: []*updateGroup{
+				{Key: "actions-checkout-v4", Title: "Update actions/checkout to v4", Updates: []*actionUpdate{checkout}},
+				{Key: "minor-and-patch", Title: "Update GitHub Actions (minor and patch)", Updates: []*actionUpdate{setupGo, cache}},
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(d.exp, groupUpdates(updates, d.groupBy)); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_getTreeEntries(t *testing.T) {
+	t.Parallel()
+	files := map[string][]string{
+		".github/workflows/test.yaml": {
+			"jobs:",
+			"  test:",
+			"    steps:",
+			"      - uses: actions/checkout@v3",
+			"      - uses: actions/setup-go@v5",
+		},
+	}
+	group := &updateGroup{
+		Updates: []*actionUpdate{
+			{File: ".github/workflows/test.yaml", Line: 4, After: "      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2"},
+		},
+	}
+	entries := getTreeEntries(files, group)
+	if len(entries) != 1 {
+		t.Fatalf("wanted 1 entry, got %d", len(entries))
+	}
+	exp := "jobs:\n  test:\n    steps:\n      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2\n      - uses: actions/setup-go@v5\n"
+	if diff := cmp.Diff(exp, entries[0].GetContent()); diff != "" {
+		t.Fatal(diff)
+	}
+	if files[".github/workflows/test.yaml"][3] != "      - uses: actions/checkout@v3" {
+		t.Fatal("the original lines must not be modified")
+	}
+}
@@ -0,0 +1,131 @@
+package run
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+type PullRequestConfig struct {
+	Title         string `json:"title,omitempty" jsonschema:"description=A Go template of pull request titles. Fields are Action and OldVersion and NewVersion and Files and Updates"`
+	Body          string `json:"body,omitempty" jsonschema:"description=A Go template of pull request bodies. Fields are same with title"`
+	CommitMessage string `json:"commit_message,omitempty" yaml:"commit_message" jsonschema:"description=A Go template of commit messages. Fields are same with title. The default value is the title"`
+}
+
+// prTemplates are templates of pull requests.
+// If a template is nil, the default value is used.
+type prTemplates struct {
+	title         *template.Template
+	body          *template.Template
+	commitMessage *template.Template
+}
+
+// prTemplateData is data passed to templates of pull requests.
+// Action, OldVersion, and NewVersion are empty if the group has multiple actions or versions.
+type prTemplateData struct {
+	Action     string
+	OldVersion string
+	NewVersion string
+	Files      []string
+	Updates    []*prTemplateUpdate
+}
+
+type prTemplateUpdate struct {
+	Action     string
+	OldVersion string
+	NewVersion string
+	File       string
+	Line       int
+}
+
+func newPRTemplates(cfg *PullRequestConfig) (*prTemplates, error) {
+	tmpls := &prTemplates{}
+	if cfg == nil {
+		return tmpls, nil
+	}
+	for _, t := range []struct {
+		name string
+		text string
+		tmpl **template.Template
+	}{
+		{name: "title", text: cfg.Title, tmpl: &tmpls.title},
+		{name: "body", text: cfg.Body, tmpl: &tmpls.body},
+		{name: "commit_message", text: cfg.CommitMessage, tmpl: &tmpls.commitMessage},
+	} {
+		if t.text == "" {
+			continue
+		}
+		tmpl, err := template.New(t.name).Option("missingkey=error").Parse(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("parse pull_request.%s as a template: %w", t.name, err)
+		}
+		*t.tmpl = tmpl
+	}
+	return tmpls, nil
+}
+
+func newPRTemplateData(group *updateGroup) *prTemplateData {
+	data := &prTemplateData{
+		Updates: make([]*prTemplateUpdate, len(group.Updates)),
+	}
+	actions := map[string]struct{}{}
+	oldVersions := map[string]struct{}{}
+	newVersions := map[string]struct{}{}
+	for i, u := range group.Updates {
+		data.Updates[i] = &prTemplateUpdate{
+			Action:     u.Action,
+			OldVersion: u.From,
+			NewVersion: u.To,
+			File:       u.File,
+			Line:       u.Line,
+		}
+		actions[u.Action] = struct{}{}
+		oldVersions[u.From] = struct{}{}
+		newVersions[u.To] = struct{}{}
+		if !slices.Contains(data.Files, u.File) {
+			data.Files = append(data.Files, u.File)
+		}
+	}
+	slices.Sort(data.Files)
+	if len(actions) == 1 {
+		u := group.Updates[0]
+		data.Action = u.Action
+		if len(oldVersions) == 1 {
+			data.OldVersion = u.From
+		}
+		if len(newVersions) == 1 {
+			data.NewVersion = u.To
+		}
+	}
+	return data
+}
+
+func renderPRTemplate(tmpl *template.Template, data *prTemplateData, defaultValue string) (string, error) {
+	if tmpl == nil {
+		return defaultValue, nil
+	}
+	buf := &strings.Builder{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", fmt.Errorf("render pull_request.%s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+// render returns the title, body, and commit message of a pull request.
+func (t *prTemplates) render(group *updateGroup) (string, string, string, error) {
+	data := newPRTemplateData(group)
+	title, err := renderPRTemplate(t.title, data, group.Title)
+	if err != nil {
+		return "", "", "", err
+	}
+	body, err := renderPRTemplate(t.body, data, getPullRequestBody(group))
+	if err != nil {
+		return "", "", "", err
+	}
+	commitMessage, err := renderPRTemplate(t.commitMessage, data, title)
+	if err != nil {
+		return "", "", "", err
+	}
+	return title, body, commitMessage, nil
+}
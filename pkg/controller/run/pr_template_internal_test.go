@@ -0,0 +1,81 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_prTemplates_render(t *testing.T) {
+	t.Parallel()
+	group := &updateGroup{
+		Key:   "actions-checkout",
+		Title: "Update actions/checkout",
+		Updates: []*actionUpdate{
+			{Action: "actions/checkout", From: "v3.5.2", To: "v4.2.2", File: ".github/workflows/test.yaml", Line: 4},
+			{Action: "actions/checkout", From: "v3.5.2", To: "v4.2.2", File: ".github/workflows/build.yaml", Line: 10},
+		},
+	}
+	data := []struct {
+		name          string
+		cfg           *PullRequestConfig
+		title         string
+		commitMessage string
+		isErr         bool
+	}{
+		{
+			name:          "default",
+			title:         "Update actions/checkout",
+			commitMessage: "Update actions/checkout",
+		},
+		{
+			name: "template",
+			cfg: &PullRequestConfig{
+				Title:         "chore(deps): update {{.Action}} to {{.NewVersion}}",
+				CommitMessage: "chore(deps): update {{.Action}} from {{.OldVersion}} ({{len .Files}} files)",
+			},
+			title:         "chore(deps): update actions/checkout to v4.2.2",
+			commitMessage: "chore(deps): update actions/checkout from v3.5.2 (2 files)",
+		},
+		{
+			name: "commit message defaults to the title",
+			cfg: &PullRequestConfig{
+				Title: "chore(deps): update {{.Action}}",
+			},
+			title:         "chore(deps): update actions/checkout",
+			commitMessage: "chore(deps): update actions/checkout",
+		},
+		{
+			name: "invalid template",
+			cfg: &PullRequestConfig{
+				Title: "{{.Action",
+			},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			tmpls, err := newPRTemplates(d.cfg)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			title, _, commitMessage, err := tmpls.render(group)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(d.title, title); diff != "" {
+				t.Fatal(diff)
+			}
+			if diff := cmp.Diff(d.commitMessage, commitMessage); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+package run
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// processExists returns true if the process is running.
+// Sending the signal 0 checks the existence of the process without affecting it.
+func processExists(pid int) (bool, error) {
+	err := syscall.Kill(pid, 0)
+	switch {
+	case err == nil, errors.Is(err, syscall.EPERM):
+		// EPERM means the process exists but is owned by another user.
+		return true, nil
+	case errors.Is(err, syscall.ESRCH):
+		return false, nil
+	default:
+		return false, fmt.Errorf("send the signal 0 to the process: %w", err)
+	}
+}
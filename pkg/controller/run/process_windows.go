@@ -0,0 +1,19 @@
+//go:build windows
+// +build windows
+
+package run
+
+import (
+	"os"
+)
+
+// processExists returns true if the process is running.
+// On Windows, os.FindProcess opens the process and fails if the process doesn't exist.
+func processExists(pid int) (bool, error) {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false, nil //nolint:nilerr
+	}
+	p.Release() //nolint:errcheck
+	return true, nil
+}
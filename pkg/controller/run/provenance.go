@@ -0,0 +1,47 @@
+package run
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// defaultHost is the host resolving versions if neither --github-api-url nor git_ssh is set.
+const defaultHost = "github.com"
+
+// provenancePattern matches markers such as " (immutable)" and the provenance appended to a version annotation.
+// The provenance is optional so that it's added to annotations without provenance.
+// e.g. " (immutable) via ghes.example.com" of "# v4.2.1 (immutable) via ghes.example.com"
+var provenancePattern = regexp.MustCompile(`^((?: \([^)]*\))*)(?: via [^ \t]+)?`)
+
+// getAPIHost returns the host of the GitHub API URL.
+// api.github.com is normalized to github.com, and api.<subdomain>.ghe.com is normalized to <subdomain>.ghe.com.
+func getAPIHost(apiURL string) string {
+	if apiURL == "" {
+		return defaultHost
+	}
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" || u.Host == "api.github.com" {
+		return defaultHost
+	}
+	if isGHEComAPIHost(u.Host) {
+		return strings.TrimPrefix(u.Host, "api.")
+	}
+	return u.Host
+}
+
+// getHost returns the host resolving versions.
+func (c *Controller) getHost() string {
+	if c.host == "" {
+		return defaultHost
+	}
+	return c.host
+}
+
+// setProvenance replaces the provenance of the suffix with the host resolving versions.
+// Markers preceding the provenance such as " (immutable)" are kept.
+// e.g. " (immutable) via github.com - foo" => " (immutable) via ghes.example.com - foo"
+func (c *Controller) setProvenance(suffix string) string {
+	m := provenancePattern.FindStringSubmatch(suffix)
+	return m[1] + " via " + c.getHost() + suffix[len(m[0]):]
+}
@@ -0,0 +1,83 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func Test_getAPIHost(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		apiURL string
+		exp    string
+	}{
+		{
+			name: "default",
+			exp:  "github.com",
+		},
+		{
+			name:   "github.com",
+			apiURL: "https://api.github.com/",
+			exp:    "github.com",
+		},
+		{
+			name:   "ghes",
+			apiURL: "https://ghes.example.com/api/v3/",
+			exp:    "ghes.example.com",
+		},
+		{
+			name:   "ghe.com",
+			apiURL: "https://api.octocorp.ghe.com/",
+			exp:    "octocorp.ghe.com",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if got := getAPIHost(d.apiURL); got != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, got)
+			}
+		})
+	}
+}
+
+func TestController_setProvenance(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		suffix string
+		exp    string
+	}{
+		{
+			name: "empty",
+			exp:  " via ghes.example.com",
+		},
+		{
+			name:   "comment",
+			suffix: " - pinned for reasons",
+			exp:    " via ghes.example.com - pinned for reasons",
+		},
+		{
+			name:   "replace",
+			suffix: " via github.com # comment",
+			exp:    " via ghes.example.com # comment",
+		},
+		{
+			name:   "markers",
+			suffix: " (2024-06-01) (immutable) via github.com",
+			exp:    " (2024-06-01) (immutable) via ghes.example.com",
+		},
+	}
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	ctrl.host = "ghes.example.com"
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ctrl.setProvenance(d.suffix); got != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+type RateLimit struct {
+	Owner       string `json:"owner" jsonschema:"description=A regular expression of repository owners. API calls for owners matching the same rule share the limit"`
+	Delay       string `json:"delay,omitempty" jsonschema:"description=A minimum interval between API calls. The format is Go's time.Duration. e.g. 500ms"`
+	Concurrency int    `json:"concurrency,omitempty" jsonschema:"description=The maximum number of concurrent API calls. The default value is unlimited"`
+	owner       *regexp.Regexp
+	delay       time.Duration
+}
+
+func (r *RateLimit) init() error {
+	p, err := regexp.Compile(r.Owner)
+	if err != nil {
+		return fmt.Errorf("parse rate_limits[].owner as a regular expression: %w", err)
+	}
+	r.owner = p
+	if r.Delay != "" {
+		d, err := time.ParseDuration(r.Delay)
+		if err != nil {
+			return fmt.Errorf("parse rate_limits[].delay: %w", err)
+		}
+		r.delay = d
+	}
+	if r.Concurrency < 0 {
+		return fmt.Errorf("rate_limits[].concurrency must not be negative: %d", r.Concurrency)
+	}
+	return nil
+}
+
+// enableRateLimits enables rate_limits and returns a function to restore the service.
+// Only API calls are throttled. Cached responses are returned without delay.
+func (c *Controller) enableRateLimits(cfg *Config) func() {
+	impl, ok := c.repositoriesService.(*RepositoriesServiceImpl)
+	if !ok || len(cfg.RateLimits) == 0 {
+		return func() {}
+	}
+	repoService := impl.RepositoriesService
+	impl.RepositoriesService = NewThrottledRepositoriesService(repoService, cfg.RateLimits)
+	return func() {
+		impl.RepositoriesService = repoService
+	}
+}
+
+// throttle limits API calls by a rate limit rule.
+type throttle struct {
+	delay time.Duration
+	sem   chan struct{}
+	mutex sync.Mutex
+	next  time.Time
+}
+
+func newThrottle(rateLimit *RateLimit) *throttle {
+	t := &throttle{
+		delay: rateLimit.delay,
+	}
+	if rateLimit.Concurrency > 0 {
+		t.sem = make(chan struct{}, rateLimit.Concurrency)
+	}
+	return t
+}
+
+// acquire waits until an API call is allowed and returns a function to release it.
+func (t *throttle) acquire(ctx context.Context) (func(), error) {
+	if t.sem != nil {
+		select {
+		case t.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err() //nolint:wrapcheck
+		}
+	}
+	release := func() {
+		if t.sem != nil {
+			<-t.sem
+		}
+	}
+	// Reserve the next slot so that calls are spaced by the delay even if they wait concurrently.
+	t.mutex.Lock()
+	now := time.Now()
+	start := t.next
+	if start.Before(now) {
+		start = now
+	}
+	t.next = start.Add(t.delay)
+	t.mutex.Unlock()
+	if wait := time.Until(start); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err() //nolint:wrapcheck
+		}
+	}
+	return release, nil
+}
+
+// ThrottledRepositoriesService limits API calls per repository owner by rate_limits.
+// It avoids secondary rate limits of fragile servers such as GitHub Enterprise Server.
+// It should be wrapped by RepositoriesServiceImpl so that cached responses aren't delayed.
+type ThrottledRepositoriesService struct {
+	RepositoriesService RepositoriesService
+	rateLimits          []*RateLimit
+	throttles           []*throttle
+}
+
+func NewThrottledRepositoriesService(repoService RepositoriesService, rateLimits []*RateLimit) *ThrottledRepositoriesService {
+	throttles := make([]*throttle, len(rateLimits))
+	for i, rateLimit := range rateLimits {
+		throttles[i] = newThrottle(rateLimit)
+	}
+	return &ThrottledRepositoriesService{
+		RepositoriesService: repoService,
+		rateLimits:          rateLimits,
+		throttles:           throttles,
+	}
+}
+
+// wait waits for the first rule matching with the owner.
+// If no rule matches, it returns immediately.
+func (s *ThrottledRepositoriesService) wait(ctx context.Context, owner string) (func(), error) {
+	for i, rateLimit := range s.rateLimits {
+		if rateLimit.owner.MatchString(owner) {
+			return s.throttles[i].acquire(ctx)
+		}
+	}
+	return func() {}, nil
+}
+
+func (s *ThrottledRepositoriesService) ListTags(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.RepositoryTag, *github.Response, error) {
+	release, err := s.wait(ctx, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	return s.RepositoriesService.ListTags(ctx, owner, repo, opts) //nolint:wrapcheck
+}
+
+func (s *ThrottledRepositoriesService) GetCommitSHA1(ctx context.Context, owner, repo, ref, lastSHA string) (string, *github.Response, error) {
+	release, err := s.wait(ctx, owner)
+	if err != nil {
+		return "", nil, err
+	}
+	defer release()
+	return s.RepositoriesService.GetCommitSHA1(ctx, owner, repo, ref, lastSHA) //nolint:wrapcheck
+}
+
+func (s *ThrottledRepositoriesService) ListReleases(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.RepositoryRelease, *github.Response, error) {
+	release, err := s.wait(ctx, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	return s.RepositoriesService.ListReleases(ctx, owner, repo, opts) //nolint:wrapcheck
+}
+
+func (s *ThrottledRepositoriesService) Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	release, err := s.wait(ctx, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	return s.RepositoriesService.Get(ctx, owner, repo) //nolint:wrapcheck
+}
+
+func (s *ThrottledRepositoriesService) ListMatchingTags(ctx context.Context, owner, repo, prefix string) ([]*github.RepositoryTag, *github.Response, error) {
+	release, err := s.wait(ctx, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	return s.RepositoriesService.ListMatchingTags(ctx, owner, repo, prefix) //nolint:wrapcheck
+}
+
+func (s *ThrottledRepositoriesService) GetFileContent(ctx context.Context, owner, repo, path, ref string) (string, *github.Response, error) {
+	release, err := s.wait(ctx, owner)
+	if err != nil {
+		return "", nil, err
+	}
+	defer release()
+	return s.RepositoriesService.GetFileContent(ctx, owner, repo, path, ref) //nolint:wrapcheck
+}
+
+func (s *ThrottledRepositoriesService) GetCommitDate(ctx context.Context, owner, repo, sha string) (time.Time, *github.Response, error) {
+	release, err := s.wait(ctx, owner)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	defer release()
+	return s.RepositoriesService.GetCommitDate(ctx, owner, repo, sha) //nolint:wrapcheck
+}
+
+func (s *ThrottledRepositoriesService) IsImmutableRelease(ctx context.Context, owner, repo, tag string) (bool, *github.Response, error) {
+	release, err := s.wait(ctx, owner)
+	if err != nil {
+		return false, nil, err
+	}
+	defer release()
+	return s.RepositoriesService.IsImmutableRelease(ctx, owner, repo, tag) //nolint:wrapcheck
+}
+
+func (s *ThrottledRepositoriesService) ListAttestations(ctx context.Context, owner, repo, subjectDigest string, opts *github.ListOptions) (*github.AttestationsResponse, *github.Response, error) {
+	release, err := s.wait(ctx, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	return s.RepositoriesService.ListAttestations(ctx, owner, repo, subjectDigest, opts) //nolint:wrapcheck
+}
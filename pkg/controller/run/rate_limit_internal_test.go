@@ -0,0 +1,65 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestThrottledRepositoriesService_wait(t *testing.T) {
+	t.Parallel()
+	rateLimit := &RateLimit{
+		Owner:       "^ghes-org$",
+		Delay:       "20ms",
+		Concurrency: 1,
+	}
+	if err := rateLimit.init(); err != nil {
+		t.Fatal(err)
+	}
+	s := NewThrottledRepositoriesService(nil, []*RateLimit{rateLimit})
+	ctx := context.Background()
+	start := time.Now()
+	for range 3 {
+		release, err := s.wait(ctx, "ghes-org")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("API calls must be spaced by the delay: %s", elapsed)
+	}
+	start = time.Now()
+	for range 3 {
+		release, err := s.wait(ctx, "suzuki-shunsuke")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed >= 20*time.Millisecond {
+		t.Fatalf("API calls of unmatched owners must not be delayed: %s", elapsed)
+	}
+}
+
+func TestThrottledRepositoriesService_wait_canceled(t *testing.T) {
+	t.Parallel()
+	rateLimit := &RateLimit{
+		Owner:       ".*",
+		Concurrency: 1,
+	}
+	if err := rateLimit.init(); err != nil {
+		t.Fatal(err)
+	}
+	s := NewThrottledRepositoriesService(nil, []*RateLimit{rateLimit})
+	release, err := s.wait(context.Background(), "suzuki-shunsuke")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.wait(ctx, "suzuki-shunsuke"); err == nil {
+		t.Fatal("an error must be returned if the context is canceled while waiting for the concurrency limit")
+	}
+}
@@ -0,0 +1,133 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// FormatRDJSON outputs findings in reviewdog Diagnostic Format (RDFormat) instead of modifying files.
+// https://github.com/reviewdog/reviewdog/tree/master/proto/rdf
+const FormatRDJSON = "rdjson"
+
+type rdjsonResult struct {
+	Source      *rdjsonSource       `json:"source"`
+	Diagnostics []*rdjsonDiagnostic `json:"diagnostics"`
+}
+
+type rdjsonSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type rdjsonDiagnostic struct {
+	Message     string              `json:"message"`
+	Location    *rdjsonLocation     `json:"location"`
+	Severity    string              `json:"severity"`
+	Code        *rdjsonCode         `json:"code"`
+	Suggestions []*rdjsonSuggestion `json:"suggestions,omitempty"`
+}
+
+type rdjsonLocation struct {
+	Path  string       `json:"path"`
+	Range *rdjsonRange `json:"range,omitempty"`
+}
+
+// rdjsonRange is a range of a file.
+// Lines and columns are 1-based, columns are counted in bytes, and the end is exclusive.
+type rdjsonRange struct {
+	Start *rdjsonPosition `json:"start"`
+	End   *rdjsonPosition `json:"end,omitempty"`
+}
+
+type rdjsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+type rdjsonCode struct {
+	Value string `json:"value"`
+}
+
+type rdjsonSuggestion struct {
+	Range *rdjsonRange `json:"range"`
+	Text  string       `json:"text"`
+}
+
+// rdjsonReporter collects findings and outputs them in RDFormat.
+// reviewdog resolves file paths from the current directory, so they're converted to paths relative to pwd.
+type rdjsonReporter struct {
+	*findingCollector
+	pwd     string
+	version string
+}
+
+func newRDJSONReporter(next Reporter, pwd, version string) *rdjsonReporter {
+	return &rdjsonReporter{
+		findingCollector: newFindingCollector(next),
+		pwd:              pwd,
+		version:          version,
+	}
+}
+
+// getRDJSONSourceURL returns the URL of the source.
+// RDFormat doesn't have a field of the version, so the URL of the release is used to tell the version of pinact.
+func getRDJSONSourceURL(version string) string {
+	if getVersionType(version) != Semver {
+		return "https://github.com/suzuki-shunsuke/pinact"
+	}
+	return "https://github.com/suzuki-shunsuke/pinact/releases/tag/" + version
+}
+
+func newRDJSONResult(pwd, version string, findings []*Finding) *rdjsonResult {
+	result := &rdjsonResult{
+		Source: &rdjsonSource{
+			Name: "pinact",
+			URL:  getRDJSONSourceURL(version),
+		},
+		Diagnostics: make([]*rdjsonDiagnostic, 0, len(findings)),
+	}
+	for _, finding := range findings {
+		diagnostic := &rdjsonDiagnostic{
+			Message: getFindingMessage(finding),
+			Location: &rdjsonLocation{
+				Path: getRelPath(pwd, finding.File),
+			},
+			Severity: "WARNING",
+			Code: &rdjsonCode{
+				Value: finding.Kind,
+			},
+		}
+		if getAnnotationLevel(finding) == "failure" {
+			diagnostic.Severity = "ERROR"
+		}
+		if finding.Line != 0 {
+			diagnostic.Location.Range = &rdjsonRange{
+				Start: &rdjsonPosition{Line: finding.Line},
+			}
+		}
+		if finding.Kind == FindingKindChanged && finding.Line != 0 {
+			// Replace the whole line.
+			diagnostic.Suggestions = []*rdjsonSuggestion{
+				{
+					Range: &rdjsonRange{
+						Start: &rdjsonPosition{Line: finding.Line, Column: 1},
+						End:   &rdjsonPosition{Line: finding.Line, Column: len(finding.Before) + 1},
+					},
+					Text: finding.After,
+				},
+			}
+		}
+		result.Diagnostics = append(result.Diagnostics, diagnostic)
+	}
+	return result
+}
+
+func (r *rdjsonReporter) write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(newRDJSONResult(r.pwd, r.version, r.findings)); err != nil {
+		return fmt.Errorf("output a report in RDFormat: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_rdjsonReporter_write(t *testing.T) {
+	t.Parallel()
+	reporter := newRDJSONReporter(&nopReporter{}, "/home/foo/repo", "v3.0.0")
+	reporter.OnFinding(&Finding{
+		Kind:   FindingKindChanged,
+		File:   "/home/foo/repo/.github/workflows/test.yaml",
+		Line:   4,
+		Before: "      - uses: actions/checkout@v2",
+		After:  "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+	})
+	reporter.OnFinding(&Finding{
+		Kind:    FindingKindCompromisedAction,
+		File:    "/home/foo/repo/.github/workflows/test.yaml",
+		Line:    10,
+		Message: "the action is compromised",
+	})
+	buf := &bytes.Buffer{}
+	if err := reporter.write(buf); err != nil {
+		t.Fatal(err)
+	}
+	result := &rdjsonResult{}
+	if err := json.Unmarshal(buf.Bytes(), result); err != nil {
+		t.Fatal(err)
+	}
+	exp := []*rdjsonDiagnostic{
+		{
+			Message:  lintMessage("      - uses: actions/checkout@v2", "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0"),
+			Location: &rdjsonLocation{Path: ".github/workflows/test.yaml", Range: &rdjsonRange{Start: &rdjsonPosition{Line: 4}}},
+			Severity: "WARNING",
+			Code:     &rdjsonCode{Value: FindingKindChanged},
+			Suggestions: []*rdjsonSuggestion{
+				{
+					Range: &rdjsonRange{
+						Start: &rdjsonPosition{Line: 4, Column: 1},
+						End:   &rdjsonPosition{Line: 4, Column: 34},
+					},
+					Text: "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+				},
+			},
+		},
+		{
+			Message:  "the action is compromised",
+			Location: &rdjsonLocation{Path: ".github/workflows/test.yaml", Range: &rdjsonRange{Start: &rdjsonPosition{Line: 10}}},
+			Severity: "ERROR",
+			Code:     &rdjsonCode{Value: FindingKindCompromisedAction},
+		},
+	}
+	if diff := cmp.Diff(exp, result.Diagnostics); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := cmp.Diff(&rdjsonSource{Name: "pinact", URL: "https://github.com/suzuki-shunsuke/pinact/releases/tag/v3.0.0"}, result.Source); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_getRDJSONSourceURL(t *testing.T) {
+	t.Parallel()
+	data := map[string]string{
+		"v3.0.0":  "https://github.com/suzuki-shunsuke/pinact/releases/tag/v3.0.0",
+		"(devel)": "https://github.com/suzuki-shunsuke/pinact",
+		"":        "https://github.com/suzuki-shunsuke/pinact",
+	}
+	for version, exp := range data {
+		if u := getRDJSONSourceURL(version); u != exp {
+			t.Fatalf("%s: wanted %s, got %s", version, exp, u)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+type Interop struct {
+	Renovate bool `json:"renovate,omitempty" jsonschema:"description=Ignore actions disabled by ignoreDeps and packageRules of Renovate configuration so that pinact and Renovate don't update the same lines"`
+}
+
+// renovateConfigPaths are paths of Renovate configuration files searched in order.
+// JSON5 isn't supported.
+var renovateConfigPaths = []string{"renovate.json", ".github/renovate.json", ".gitlab/renovate.json", ".renovaterc", ".renovaterc.json"}
+
+// renovateManager is the name of the Renovate manager of GitHub Actions.
+const renovateManager = "github-actions"
+
+type renovateConfig struct {
+	IgnoreDeps   []string               `json:"ignoreDeps"`
+	PackageRules []*renovatePackageRule `json:"packageRules"`
+}
+
+type renovatePackageRule struct {
+	MatchManagers        []string `json:"matchManagers"`
+	MatchDepNames        []string `json:"matchDepNames"`
+	MatchPackageNames    []string `json:"matchPackageNames"`
+	MatchDepPatterns     []string `json:"matchDepPatterns"`
+	MatchPackagePatterns []string `json:"matchPackagePatterns"`
+	Enabled              *bool    `json:"enabled"`
+}
+
+// isIgnore returns true if the rule disables updates of GitHub Actions.
+func (r *renovatePackageRule) isIgnore() bool {
+	if r.Enabled == nil || *r.Enabled {
+		return false
+	}
+	return len(r.MatchManagers) == 0 || slices.Contains(r.MatchManagers, renovateManager)
+}
+
+// renovateIgnore is a name of actions ignored by Renovate.
+// Renovate supports glob patterns and regular expressions enclosed by slashes such as /^actions\//.
+type renovateIgnore struct {
+	Name    string
+	pattern *regexp.Regexp
+}
+
+func newRenovateIgnore(name string, isPattern bool) (*renovateIgnore, error) {
+	ignore := &renovateIgnore{
+		Name: name,
+	}
+	if !isPattern && len(name) > 1 && strings.HasPrefix(name, "/") && strings.HasSuffix(name, "/") {
+		name = name[1 : len(name)-1]
+		isPattern = true
+	}
+	if isPattern {
+		p, err := regexp.Compile(name)
+		if err != nil {
+			return nil, fmt.Errorf("parse a pattern of Renovate as a regular expression: %w", err)
+		}
+		ignore.pattern = p
+		return ignore, nil
+	}
+	if _, err := path.Match(name, ""); err != nil {
+		return nil, fmt.Errorf("parse a name of Renovate as a glob pattern: %w", err)
+	}
+	return ignore, nil
+}
+
+// match returns true if the action is ignored.
+// Renovate's package names of actions are owner/repo, so both the action name and the repository are compared.
+func (r *renovateIgnore) match(action *Action) bool {
+	names := []string{action.Name}
+	if owner, repo, ok := strings.Cut(action.Name, "/"); ok {
+		repo, _, _ = strings.Cut(repo, "/")
+		names = append(names, owner+"/"+repo)
+	}
+	for _, name := range names {
+		if r.pattern != nil {
+			if r.pattern.MatchString(name) {
+				return true
+			}
+			continue
+		}
+		if f, err := path.Match(r.Name, name); err == nil && f {
+			return true
+		}
+	}
+	return false
+}
+
+// getRenovateIgnores returns names of actions ignored by the Renovate configuration.
+// Negated names such as !actions/checkout aren't supported, so they're skipped.
+func getRenovateIgnores(cfg *renovateConfig) ([]*renovateIgnore, error) {
+	type entry struct {
+		name      string
+		isPattern bool
+	}
+	entries := make([]*entry, 0, len(cfg.IgnoreDeps))
+	for _, name := range cfg.IgnoreDeps {
+		entries = append(entries, &entry{name: name})
+	}
+	for _, rule := range cfg.PackageRules {
+		if !rule.isIgnore() {
+			continue
+		}
+		for _, name := range slices.Concat(rule.MatchDepNames, rule.MatchPackageNames) {
+			entries = append(entries, &entry{name: name})
+		}
+		for _, name := range slices.Concat(rule.MatchDepPatterns, rule.MatchPackagePatterns) {
+			entries = append(entries, &entry{name: name, isPattern: true})
+		}
+	}
+	ignores := make([]*renovateIgnore, 0, len(entries))
+	for _, e := range entries {
+		if e.name == "" || strings.HasPrefix(e.name, "!") {
+			continue
+		}
+		ignore, err := newRenovateIgnore(e.name, e.isPattern)
+		if err != nil {
+			return nil, err
+		}
+		ignores = append(ignores, ignore)
+	}
+	return ignores, nil
+}
+
+// readRenovateIgnores reads the Renovate configuration and sets actions ignored by Renovate to the configuration.
+// If no Renovate configuration is found, nothing is ignored.
+func (c *Controller) readRenovateIgnores(logE *logrus.Entry, pwd string, cfg *Config) error {
+	for _, p := range renovateConfigPaths {
+		p = filepath.Join(pwd, p)
+		b, err := afero.ReadFile(c.fs, p)
+		if err != nil {
+			continue
+		}
+		renovate := &renovateConfig{}
+		if err := json.Unmarshal(b, renovate); err != nil {
+			return fmt.Errorf("decode a Renovate configuration file as JSON: %w", err)
+		}
+		ignores, err := getRenovateIgnores(renovate)
+		if err != nil {
+			return err
+		}
+		cfg.renovateIgnores = ignores
+		logE.WithFields(logrus.Fields{
+			"renovate_config": p,
+			"ignored_actions": len(ignores),
+		}).Debug("read a Renovate configuration file")
+		return nil
+	}
+	logE.Debug("no Renovate configuration file is found")
+	return nil
+}
+
+// matchRenovateIgnore returns the name ignored by Renovate matching with the action.
+// If no name matches, it returns an empty string.
+func (c *Config) matchRenovateIgnore(action *Action) string {
+	for _, ignore := range c.renovateIgnores {
+		if ignore.match(action) {
+			return ignore.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_readRenovateIgnores(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "/repo/.github/renovate.json", []byte(`{
+  "ignoreDeps": ["actions/checkout"],
+  "packageRules": [
+    {
+      "matchManagers": ["github-actions"],
+      "matchPackageNames": ["suzuki-shunsuke/*", "/^aquaproj\\//", "!actions/cache"],
+      "enabled": false
+    },
+    {
+      "matchManagers": ["npm"],
+      "matchPackageNames": ["actions/setup-go"],
+      "enabled": false
+    },
+    {
+      "matchPackagePatterns": ["^int128/"],
+      "enabled": false
+    },
+    {
+      "matchPackageNames": ["actions/setup-node"],
+      "automerge": true
+    }
+  ]
+}`), filePermission); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := NewController(nil, fs)
+	cfg := &Config{}
+	if err := ctrl.readRenovateIgnores(logrus.NewEntry(logrus.New()), "/repo", cfg); err != nil {
+		t.Fatal(err)
+	}
+	data := []struct {
+		action string
+		exp    string
+	}{
+		{action: "actions/checkout", exp: "actions/checkout"},
+		{action: "actions/cache/restore"},
+		{action: "suzuki-shunsuke/pinact-action", exp: "suzuki-shunsuke/*"},
+		{action: "suzuki-shunsuke/go-ci/.github/workflows/test.yaml", exp: "suzuki-shunsuke/*"},
+		{action: "aquaproj/aqua-installer", exp: `/^aquaproj\//`},
+		{action: "int128/ghcp", exp: "^int128/"},
+		{action: "actions/setup-go"},
+		{action: "actions/setup-node"},
+	}
+	for _, d := range data {
+		if name := cfg.matchRenovateIgnore(&Action{Name: d.action}); name != d.exp {
+			t.Fatalf("%s: wanted %s, got %s", d.action, d.exp, name)
+		}
+	}
+}
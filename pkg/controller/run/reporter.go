@@ -0,0 +1,93 @@
+package run
+
+import (
+	"io"
+	"path/filepath"
+)
+
+// Reporter receives events of a run.
+// It enables embedders and output formats such as HTML reports, pull request comments, and metrics to subscribe to findings without modifying the controller.
+type Reporter interface {
+	// OnFileStart is called before a file is processed.
+	OnFileStart(path string)
+	// OnFinding is called for each finding in a file.
+	OnFinding(finding *Finding)
+	// OnFileEnd is called after a file is processed.
+	OnFileEnd(path string, changed bool)
+}
+
+// formatReporter collects findings and outputs them in the format of --format.
+type formatReporter interface {
+	Reporter
+	sort(key string)
+	write(w io.Writer) error
+}
+
+const (
+	// FindingKindChanged means a line is changed, such as pinning or updating an action.
+	FindingKindChanged = "changed"
+	// FindingKindError means pinact failed to process a line.
+	FindingKindError = "error"
+	// FindingKindDuplicateVersions means the same action is used at multiple versions in a job.
+	FindingKindDuplicateVersions = "duplicate-versions"
+	// FindingKindCompromisedAction means a known compromised action is used.
+	FindingKindCompromisedAction = "compromised-action"
+	// FindingKindVulnerability means an action version has a known vulnerability.
+	FindingKindVulnerability = "vulnerability"
+	// FindingKindUnpinnedTransitiveAction means a reusable workflow uses an unpinned action.
+	// Before and After are the line of the called reusable workflow.
+	FindingKindUnpinnedTransitiveAction = "unpinned-transitive-action"
+	// FindingKindOldPin means an action is pinned to a commit older than --max-pin-age.
+	FindingKindOldPin = "old-pin"
+	// FindingKindMissingProvenance means the pinned commit of an action doesn't have SLSA provenance.
+	FindingKindMissingProvenance = "missing-provenance"
+	// FindingKindDynamicRef means an action is referenced by a branch or another ref which pinact can't pin.
+	FindingKindDynamicRef = "dynamic-ref"
+	// FindingKindInconsistentAnnotation means the same commit hash is annotated with different tags across files.
+	FindingKindInconsistentAnnotation = "inconsistent-annotation"
+	// FindingKindBranchPin means an action is pinned to a commit hash of a branch such as main instead of a release.
+	FindingKindBranchPin = "branch-pin"
+	// FindingKindDeprecatedAction means an action's repository is archived.
+	FindingKindDeprecatedAction = "deprecated-action"
+)
+
+type Finding struct {
+	Kind string
+	File string
+	// Line is a 1-based line number. It's 0 if the finding isn't related to a specific line.
+	Line    int
+	Before  string
+	After   string
+	Message string
+	// Severity is the severity of the check configured by checks. It's empty if the check isn't configured.
+	Severity string
+	// Replacement is an alternative of a deprecated action configured by replacements. It's empty if it isn't configured.
+	Replacement string
+}
+
+// SetReporter sets a reporter. If it's nil, events aren't reported.
+func (c *Controller) SetReporter(reporter Reporter) {
+	if reporter == nil {
+		reporter = &nopReporter{}
+	}
+	c.reporter = reporter
+}
+
+type nopReporter struct{}
+
+func (r *nopReporter) OnFileStart(string) {}
+
+func (r *nopReporter) OnFinding(*Finding) {}
+
+func (r *nopReporter) OnFileEnd(string, bool) {}
+
+// getRelPath converts a file path to a slash-separated path relative to pwd.
+// If the path can't be converted, it's returned as is.
+func getRelPath(pwd, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(pwd, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
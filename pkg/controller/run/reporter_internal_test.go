@@ -0,0 +1,98 @@
+package run
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+type testReporter struct {
+	events   []string
+	findings []*Finding
+}
+
+func (r *testReporter) OnFileStart(string) {
+	r.events = append(r.events, "start")
+}
+
+func (r *testReporter) OnFinding(finding *Finding) {
+	r.events = append(r.events, "finding")
+	r.findings = append(r.findings, finding)
+}
+
+func (r *testReporter) OnFileEnd(string, bool) {
+	r.events = append(r.events, "end")
+}
+
+func TestController_runWorkflow_reporter(t *testing.T) {
+	t.Parallel()
+	workflowFilePath := filepath.Join(t.TempDir(), "test.yaml")
+	content := `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v2
+      - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2
+`
+	if err := os.WriteFile(workflowFilePath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := NewController(&RepositoriesServiceImpl{
+		tags: map[string]*ListTagsResult{
+			"actions/checkout/0": {
+				Tags: []*github.RepositoryTag{
+					{
+						Name: util.StrP("v2.7.0"),
+						Commit: &github.Commit{
+							SHA: util.StrP("ee0669bd1cc54295c223e0bb666b733df41de1c5"),
+						},
+					},
+				},
+				Response: &github.Response{},
+			},
+		},
+		commits: map[string]*GetCommitSHA1Result{
+			"actions/checkout/v2": {
+				SHA: "ee0669bd1cc54295c223e0bb666b733df41de1c5",
+			},
+		},
+	}, afero.NewMemMapFs())
+	reporter := &testReporter{}
+	ctrl.SetReporter(reporter)
+	cfg := &Config{
+		CheckDuplicateVersions: true,
+	}
+	if _, err := ctrl.runWorkflow(context.Background(), logrus.NewEntry(logrus.New()), workflowFilePath, cfg, &Stats{}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"start", "finding", "finding", "end"}, reporter.events); diff != "" {
+		t.Fatal(diff)
+	}
+	// Duplicate versions are checked before the file is patched, so versions in the file are reported.
+	exps := []*Finding{
+		{
+			Kind:    FindingKindDuplicateVersions,
+			File:    workflowFilePath,
+			Line:    5,
+			Before:  "      - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2",
+			After:   "      - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2",
+			Message: "actions/checkout is used at multiple versions in the job test: v2, v3.5.2",
+		},
+		{
+			Kind:   FindingKindChanged,
+			File:   workflowFilePath,
+			Line:   4,
+			Before: "      - uses: actions/checkout@v2",
+			After:  "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+		},
+	}
+	if diff := cmp.Diff(exps, reporter.findings); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,167 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+const (
+	resolverMethodGetCommitSHA1 = "get_commit_sha1"
+	resolverMethodListTags      = "list_tags"
+)
+
+// ResolverCommandService resolves tags and commit hashes by an external command.
+// It's used to integrate bespoke artifact catalogs without forking pinact.
+//
+// The command receives a request as JSON via the standard input and outputs a response as JSON to the standard output.
+// If the command fails, it must exit with a non-zero code.
+//
+//	{"method": "get_commit_sha1", "owner": "actions", "repo": "checkout", "ref": "v4"} => {"sha": "..."}
+//	{"method": "list_tags", "owner": "actions", "repo": "checkout"} => {"tags": [{"name": "v4.2.2", "sha": "..."}]}
+type ResolverCommandService struct {
+	command string
+	dir     string
+}
+
+// NewResolverCommandService returns a service running the command.
+// A relative command path is resolved from dir.
+func NewResolverCommandService(command, dir string) *ResolverCommandService {
+	return &ResolverCommandService{
+		command: command,
+		dir:     dir,
+	}
+}
+
+type resolverRequest struct {
+	Method string `json:"method"`
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Ref    string `json:"ref,omitempty"`
+}
+
+type resolverResponse struct {
+	SHA  string         `json:"sha,omitempty"`
+	Tags []*resolverTag `json:"tags,omitempty"`
+}
+
+type resolverTag struct {
+	Name string `json:"name"`
+	SHA  string `json:"sha"`
+}
+
+func (s *ResolverCommandService) call(ctx context.Context, req *resolverRequest) (*resolverResponse, error) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encode a request to the resolver command as JSON: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, s.command)
+	cmd.Dir = s.dir
+	cmd.Stdin = bytes.NewReader(b)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("execute the resolver command: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	resp := &resolverResponse{}
+	if err := json.Unmarshal(out, resp); err != nil {
+		return nil, fmt.Errorf("decode a response of the resolver command as JSON: %w", err)
+	}
+	return resp, nil
+}
+
+func (s *ResolverCommandService) ListTags(ctx context.Context, owner string, repo string, _ *github.ListOptions) ([]*github.RepositoryTag, *github.Response, error) {
+	resp, err := s.call(ctx, &resolverRequest{
+		Method: resolverMethodListTags,
+		Owner:  owner,
+		Repo:   repo,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	tags := make([]*github.RepositoryTag, 0, len(resp.Tags))
+	for _, tag := range resp.Tags {
+		tags = append(tags, &github.RepositoryTag{
+			Name: util.StrP(tag.Name),
+			Commit: &github.Commit{
+				SHA: util.StrP(tag.SHA),
+			},
+		})
+	}
+	// The command returns all tags at once, so there is no next page.
+	return tags, &github.Response{}, nil
+}
+
+func (s *ResolverCommandService) ListMatchingTags(ctx context.Context, owner, repo, prefix string) ([]*github.RepositoryTag, *github.Response, error) {
+	tags, resp, err := s.ListTags(ctx, owner, repo, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	matched := make([]*github.RepositoryTag, 0, len(tags))
+	for _, tag := range tags {
+		if strings.HasPrefix(tag.GetName(), prefix) {
+			matched = append(matched, tag)
+		}
+	}
+	return matched, resp, nil
+}
+
+func (s *ResolverCommandService) GetCommitSHA1(ctx context.Context, owner, repo, ref, _ string) (string, *github.Response, error) {
+	if fullCommitSHAPattern.MatchString(ref) {
+		return ref, &github.Response{}, nil
+	}
+	resp, err := s.call(ctx, &resolverRequest{
+		Method: resolverMethodGetCommitSHA1,
+		Owner:  owner,
+		Repo:   repo,
+		Ref:    ref,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if resp.SHA == "" {
+		return "", nil, fmt.Errorf("ref isn't found: %s", ref)
+	}
+	return resp.SHA, &github.Response{}, nil
+}
+
+func (s *ResolverCommandService) ListReleases(context.Context, string, string, *github.ListOptions) ([]*github.RepositoryRelease, *github.Response, error) {
+	// Releases aren't supported by the protocol, so the latest version is resolved from tags.
+	return nil, &github.Response{}, nil
+}
+
+func (s *ResolverCommandService) GetFileContent(context.Context, string, string, string, string) (string, *github.Response, error) {
+	return "", nil, errors.New("file contents can't be got via resolver_command")
+}
+
+func (s *ResolverCommandService) IsImmutableRelease(context.Context, string, string, string) (bool, *github.Response, error) {
+	// Releases aren't supported by the protocol, so no release is immutable.
+	return false, &github.Response{}, nil
+}
+
+func (s *ResolverCommandService) ListAttestations(context.Context, string, string, string, *github.ListOptions) (*github.AttestationsResponse, *github.Response, error) {
+	return nil, nil, errors.New("attestations can't be got via resolver_command")
+}
+
+func (s *ResolverCommandService) GetCommitDate(context.Context, string, string, string) (time.Time, *github.Response, error) {
+	return time.Time{}, nil, errors.New("commit dates can't be got via resolver_command")
+}
+
+func (s *ResolverCommandService) Get(_ context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	// Repository metadata such as archived isn't supported by the protocol.
+	return &github.Repository{
+		Owner: &github.User{
+			Login: util.StrP(owner),
+		},
+		Name: util.StrP(repo),
+	}, &github.Response{}, nil
+}
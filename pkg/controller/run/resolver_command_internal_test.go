@@ -0,0 +1,99 @@
+package run
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testResolverCommand = `#!/bin/sh
+req=$(cat)
+case "$req" in
+  *'"method":"get_commit_sha1"'*'"ref":"v4"'*)
+    echo '{"sha": "11bd71901bbe5b1630ceea73d27597364c9af683"}';;
+  *'"method":"get_commit_sha1"'*)
+    echo '{}';;
+  *'"method":"list_tags"'*)
+    echo '{"tags": [{"name": "v4.2.2", "sha": "11bd71901bbe5b1630ceea73d27597364c9af683"}, {"name": "v3.6.0", "sha": "f43a0e5ff2bd294095638e18286ca9a3d1956744"}]}';;
+  *)
+    echo "unknown request" >&2
+    exit 1;;
+esac
+`
+
+func newTestResolverCommandService(t *testing.T) *ResolverCommandService {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the test resolver command is a shell script")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "resolver"), []byte(testResolverCommand), 0o755); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	return NewResolverCommandService("./resolver", dir)
+}
+
+func TestResolverCommandService_GetCommitSHA1(t *testing.T) {
+	t.Parallel()
+	s := newTestResolverCommandService(t)
+	data := []struct {
+		name  string
+		ref   string
+		exp   string
+		isErr bool
+	}{
+		{
+			name: "tag",
+			ref:  "v4",
+			exp:  "11bd71901bbe5b1630ceea73d27597364c9af683",
+		},
+		{
+			name: "commit hash",
+			ref:  "f43a0e5ff2bd294095638e18286ca9a3d1956744",
+			exp:  "f43a0e5ff2bd294095638e18286ca9a3d1956744",
+		},
+		{
+			name:  "not found",
+			ref:   "v100",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			sha, _, err := s.GetCommitSHA1(context.Background(), "actions", "checkout", d.ref, "")
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if sha != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, sha)
+			}
+		})
+	}
+}
+
+func TestResolverCommandService_ListMatchingTags(t *testing.T) {
+	t.Parallel()
+	s := newTestResolverCommandService(t)
+	tags, _, err := s.ListMatchingTags(context.Background(), "actions", "checkout", "v4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.GetName() + "@" + tag.GetCommit().GetSHA()
+	}
+	if diff := cmp.Diff([]string{"v4.2.2@11bd71901bbe5b1630ceea73d27597364c9af683"}, names); diff != "" {
+		t.Fatal(diff)
+	}
+}
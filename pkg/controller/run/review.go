@@ -0,0 +1,237 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/util"
+)
+
+type PullRequestsService interface {
+	ListFiles(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error)
+	CreateReview(ctx context.Context, owner, repo string, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error)
+	List(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	Create(ctx context.Context, owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
+}
+
+type ParamReview struct {
+	Owner  string
+	Repo   string
+	Number int
+	// HeadOwner and HeadRepo are the repository of the head branch. They differ from Owner and Repo if the pull request is created from a fork.
+	HeadOwner string
+	HeadRepo  string
+	HeadSHA   string
+	IsVerify  bool
+	Now       time.Time
+	// CheckRun creates a check run with annotations instead of a review.
+	// A check run doesn't require the permission to write pull requests.
+	CheckRun bool
+}
+
+// ReviewPullRequest checks workflow files changed in a pull request and posts suggestions as a review.
+// Only lines changed in the pull request are commented because GitHub doesn't allow to comment on other lines.
+// The configuration file is read from the head commit of the pull request.
+func (c *Controller) ReviewPullRequest(ctx context.Context, logE *logrus.Entry, param *ParamReview) error {
+	cfg, err := c.readRemoteConfig(ctx, logE, param.HeadOwner, param.HeadRepo, param.HeadSHA)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Init(); err != nil {
+		return fmt.Errorf("initialize the configuration: %w", err)
+	}
+	cfg.Now = param.Now
+	cfg.setFreezeWindows(param.Now)
+	cfg.IsVerify = param.IsVerify
+	defer c.enableRateLimits(cfg)()
+	patterns, err := compileFilePatterns(cfg)
+	if err != nil {
+		return err
+	}
+	tmpls, err := newReviewTemplates(cfg.Review)
+	if err != nil {
+		return err
+	}
+	linter := &Linter{
+		controller: c,
+		cfg:        cfg,
+	}
+	files, err := c.listPullRequestFiles(ctx, param.Owner, param.Repo, param.Number)
+	if err != nil {
+		return err
+	}
+	comments := []*github.DraftReviewComment{}
+	findings := []*Finding{}
+	for _, file := range files {
+		filePath := file.GetFilename()
+		if file.GetStatus() == "removed" || !isReviewTarget(patterns, filePath) {
+			continue
+		}
+		logE := logE.WithField("workflow_file", filePath)
+		changedLines := getChangedLines(file.GetPatch())
+		if len(changedLines) == 0 {
+			continue
+		}
+		content, _, err := c.repositoriesService.GetFileContent(ctx, param.HeadOwner, param.HeadRepo, filePath, param.HeadSHA)
+		if err != nil {
+			logerr.WithError(logE, err).Warn("get a file content")
+			continue
+		}
+		for _, finding := range linter.Lint(ctx, logE, filePath, content) {
+			if finding.Kind != FindingKindChanged || !changedLines[finding.Line] {
+				continue
+			}
+			body, err := tmpls.renderComment(finding)
+			if err != nil {
+				return err
+			}
+			if err := checkSecrets(finding.After, body); err != nil {
+				return logerr.WithFields(err, logrus.Fields{ //nolint:wrapcheck
+					"line_number": finding.Line,
+				})
+			}
+			findings = append(findings, finding)
+			comments = append(comments, &github.DraftReviewComment{
+				Path: util.StrP(filePath),
+				Line: util.IntP(finding.Line),
+				Side: util.StrP("RIGHT"),
+				Body: util.StrP(body),
+			})
+		}
+	}
+	if param.CheckRun {
+		return c.createCheckRun(ctx, logE, &ParamCheckRun{
+			Owner:   param.Owner,
+			Repo:    param.Repo,
+			HeadSHA: param.HeadSHA,
+		}, "", findings)
+	}
+	logE = logE.WithField("comments", len(comments))
+	if len(comments) == 0 {
+		logE.Info("no suggestion")
+		return nil
+	}
+	body, err := tmpls.renderBody(findings)
+	if err != nil {
+		return err
+	}
+	if err := checkSecrets(body); err != nil {
+		return err
+	}
+	if _, _, err := c.pullRequestsService.CreateReview(ctx, param.Owner, param.Repo, param.Number, &github.PullRequestReviewRequest{
+		CommitID: util.StrP(param.HeadSHA),
+		Body:     util.StrP(body),
+		Event:    util.StrP("COMMENT"),
+		Comments: comments,
+	}); err != nil {
+		return fmt.Errorf("create a review: %w", err)
+	}
+	logE.Info("create a review")
+	return nil
+}
+
+// readRemoteConfig reads a configuration file from the repository via GitHub API.
+// If no configuration file is found, the default configuration is returned.
+func (c *Controller) readRemoteConfig(ctx context.Context, logE *logrus.Entry, owner, repo, ref string) (*Config, error) {
+	cfg := &Config{}
+	for _, configFilePath := range configFilePaths {
+		content, _, err := c.repositoriesService.GetFileContent(ctx, owner, repo, configFilePath, ref)
+		if err != nil {
+			if github.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("get a configuration file: %w", err)
+		}
+		if err := parseConfig(logE.WithField("config_file", configFilePath), []byte(content), cfg); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	}
+	return cfg, nil
+}
+
+func (c *Controller) listPullRequestFiles(ctx context.Context, owner, repo string, number int) ([]*github.CommitFile, error) {
+	opts := &github.ListOptions{
+		PerPage: 100, //nolint:mnd
+	}
+	files := []*github.CommitFile{}
+	for {
+		arr, resp, err := c.pullRequestsService.ListFiles(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list files of a pull request: %w", err)
+		}
+		files = append(files, arr...)
+		if resp == nil || resp.NextPage == 0 {
+			return files, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// isReviewTarget returns true if the file is a target.
+// If files[].pattern isn't set, workflow files and actions are targets.
+func isReviewTarget(patterns []*regexp.Regexp, filePath string) bool {
+	if len(patterns) != 0 {
+		for _, pattern := range patterns {
+			if pattern.MatchString(filePath) {
+				return true
+			}
+		}
+		return false
+	}
+	switch path.Base(filePath) {
+	case "action.yaml", "action.yml":
+		return true
+	}
+	dir, ext := path.Dir(filePath), path.Ext(filePath)
+	return dir == ".github/workflows" && (ext == ".yaml" || ext == ".yml")
+}
+
+// getChangedLines returns line numbers of added lines in the new file from a unified diff.
+func getChangedLines(patch string) map[int]bool {
+	lines := map[int]bool{}
+	lineNumber := 0
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			// @@ -1,4 +1,5 @@
+			lineNumber = parseHunkStart(line)
+			continue
+		}
+		if lineNumber == 0 {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "+"):
+			lines[lineNumber] = true
+			lineNumber++
+		case strings.HasPrefix(line, "-"), strings.HasPrefix(line, `\`):
+		default:
+			lineNumber++
+		}
+	}
+	return lines
+}
+
+// parseHunkStart returns the start line number of the new file in a hunk header.
+// If the header is invalid, it returns 0.
+func parseHunkStart(header string) int {
+	_, s, ok := strings.Cut(header, " +")
+	if !ok {
+		return 0
+	}
+	s, _, _ = strings.Cut(s, " ")
+	s, _, _ = strings.Cut(s, ",")
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
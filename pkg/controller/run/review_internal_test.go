@@ -0,0 +1,99 @@
+package run
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_getChangedLines(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name  string
+		patch string
+		exp   map[int]bool
+	}{
+		{
+			name:  "empty",
+			patch: "",
+			exp:   map[int]bool{},
+		},
+		{
+			name: "normal",
+			patch: `@@ -1,4 +1,5 @@
+ jobs:
+   test:
+     steps:
+-      - uses: actions/checkout@v2
++      - uses: actions/checkout@v3
++      - uses: actions/setup-go@v5
+@@ -10,2 +11,3 @@ jobs:
+   foo:
++    runs-on: ubuntu-latest
+ 
+\ No newline at end of file`,
+			exp: map[int]bool{
+				4:  true,
+				5:  true,
+				12: true,
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(d.exp, getChangedLines(d.patch)); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_isReviewTarget(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name     string
+		patterns []*regexp.Regexp
+		path     string
+		exp      bool
+	}{
+		{
+			name: "workflow",
+			path: ".github/workflows/test.yaml",
+			exp:  true,
+		},
+		{
+			name: "nested file in workflows",
+			path: ".github/workflows/foo/test.yaml",
+		},
+		{
+			name: "action",
+			path: "foo/action.yml",
+			exp:  true,
+		},
+		{
+			name: "other",
+			path: "README.md",
+		},
+		{
+			name:     "pattern",
+			patterns: []*regexp.Regexp{regexp.MustCompile(`^ci/.*\.yaml$`)},
+			path:     "ci/test.yaml",
+			exp:      true,
+		},
+		{
+			name:     "unmatched pattern",
+			patterns: []*regexp.Regexp{regexp.MustCompile(`^ci/.*\.yaml$`)},
+			path:     ".github/workflows/test.yaml",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if got := isReviewTarget(d.patterns, d.path); got != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package run
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+type ReviewConfig struct {
+	Body    string `json:"body,omitempty" jsonschema:"description=A Go template of review bodies. Fields are Comments and Files"`
+	Comment string `json:"comment,omitempty" jsonschema:"description=A Go template of review comments. Fields are Action and OldVersion and NewVersion and ReleaseURL and File and Line and Message and Before and After and Suggestion"`
+}
+
+const defaultReviewBody = "pinact found actions which should be pinned or updated."
+
+// reviewTemplates are templates of reviews.
+// If a template is nil, the default value is used.
+type reviewTemplates struct {
+	body    *template.Template
+	comment *template.Template
+}
+
+// reviewBodyData is data passed to the template of review bodies.
+type reviewBodyData struct {
+	Comments int
+	Files    []string
+}
+
+// reviewCommentData is data passed to the template of review comments.
+// Suggestion is a suggested change block replacing the line with After.
+// ReleaseURL is empty if the new version isn't a tag.
+type reviewCommentData struct {
+	Action     string
+	OldVersion string
+	NewVersion string
+	ReleaseURL string
+	File       string
+	Line       int
+	Message    string
+	Before     string
+	After      string
+	Suggestion string
+}
+
+func newReviewTemplates(cfg *ReviewConfig) (*reviewTemplates, error) {
+	tmpls := &reviewTemplates{}
+	if cfg == nil {
+		return tmpls, nil
+	}
+	for _, t := range []struct {
+		name string
+		text string
+		tmpl **template.Template
+	}{
+		{name: "body", text: cfg.Body, tmpl: &tmpls.body},
+		{name: "comment", text: cfg.Comment, tmpl: &tmpls.comment},
+	} {
+		if t.text == "" {
+			continue
+		}
+		tmpl, err := template.New(t.name).Option("missingkey=error").Parse(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("parse review.%s as a template: %w", t.name, err)
+		}
+		*t.tmpl = tmpl
+	}
+	return tmpls, nil
+}
+
+// getAnnotatedVersion returns the version annotation of the action.
+// If the action has no annotation, the version is returned.
+func getAnnotatedVersion(action *Action) string {
+	if action.Tag != "" {
+		return action.Tag
+	}
+	return action.Version
+}
+
+func newReviewCommentData(finding *Finding) *reviewCommentData {
+	data := &reviewCommentData{
+		File:       finding.File,
+		Line:       finding.Line,
+		Message:    finding.Message,
+		Before:     finding.Before,
+		After:      finding.After,
+		Suggestion: "```suggestion\n" + finding.After + "\n```",
+	}
+	if action := parseAction(finding.Before); action != nil {
+		data.Action = action.Name
+		data.OldVersion = getAnnotatedVersion(action)
+	}
+	if action := parseAction(finding.After); action != nil {
+		data.NewVersion = getAnnotatedVersion(action)
+		if owner, rest, ok := strings.Cut(action.Name, "/"); ok && getVersionType(data.NewVersion) != FullCommitSHA {
+			repo, _, _ := strings.Cut(rest, "/")
+			data.ReleaseURL = fmt.Sprintf("https://github.com/%s/%s/releases/tag/%s", owner, repo, data.NewVersion)
+		}
+	}
+	return data
+}
+
+func renderReviewTemplate(tmpl *template.Template, data any, defaultValue string) (string, error) {
+	if tmpl == nil {
+		return defaultValue, nil
+	}
+	buf := &strings.Builder{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", fmt.Errorf("render review.%s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+// renderComment returns the body of a review comment of the finding.
+func (t *reviewTemplates) renderComment(finding *Finding) (string, error) {
+	data := newReviewCommentData(finding)
+	return renderReviewTemplate(t.comment, data, data.Message+"\n\n"+data.Suggestion)
+}
+
+// renderBody returns the body of a review.
+func (t *reviewTemplates) renderBody(findings []*Finding) (string, error) {
+	data := &reviewBodyData{
+		Comments: len(findings),
+	}
+	for _, finding := range findings {
+		if !slices.Contains(data.Files, finding.File) {
+			data.Files = append(data.Files, finding.File)
+		}
+	}
+	slices.Sort(data.Files)
+	return renderReviewTemplate(t.body, data, defaultReviewBody)
+}
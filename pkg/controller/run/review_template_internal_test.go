@@ -0,0 +1,85 @@
+package run
+
+import (
+	"testing"
+)
+
+func Test_reviewTemplates_renderComment(t *testing.T) {
+	t.Parallel()
+	finding := &Finding{
+		Kind:    FindingKindChanged,
+		File:    ".github/workflows/test.yaml",
+		Line:    4,
+		Before:  "      - uses: actions/checkout@v3",
+		After:   "      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		Message: "pinact would update the line",
+	}
+	data := []struct {
+		name  string
+		cfg   *ReviewConfig
+		exp   string
+		isErr bool
+	}{
+		{
+			name: "default",
+			exp:  "pinact would update the line\n\n```suggestion\n      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2\n```",
+		},
+		{
+			name: "template",
+			cfg: &ReviewConfig{
+				Comment: "Update {{.Action}} from {{.OldVersion}} to [{{.NewVersion}}]({{.ReleaseURL}})",
+			},
+			exp: "Update actions/checkout from v3 to [v4.2.2](https://github.com/actions/checkout/releases/tag/v4.2.2)",
+		},
+		{
+			name: "unknown field",
+			cfg: &ReviewConfig{
+				Comment: "{{.Foo}}",
+			},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			tmpls, err := newReviewTemplates(d.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			comment, err := tmpls.renderComment(finding)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if comment != d.exp {
+				t.Fatalf("wanted %q, got %q", d.exp, comment)
+			}
+		})
+	}
+}
+
+func Test_reviewTemplates_renderBody(t *testing.T) {
+	t.Parallel()
+	tmpls, err := newReviewTemplates(&ReviewConfig{
+		Body: "{{.Comments}} suggestions in {{len .Files}} files",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := tmpls.renderBody([]*Finding{
+		{File: "b.yaml"},
+		{File: "a.yaml"},
+		{File: "b.yaml"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "3 suggestions in 2 files"; body != exp {
+		t.Fatalf("wanted %q, got %q", exp, body)
+	}
+}
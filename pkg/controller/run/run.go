@@ -2,74 +2,763 @@ package run
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/trace"
 )
 
 type ParamRun struct {
-	WorkflowFilePaths []string
-	ConfigFilePath    string
-	PWD               string
-	IsVerify          bool
-	Update            bool
+	WorkflowFilePaths      []string
+	ConfigFilePath         string
+	PWD                    string
+	IsVerify               bool
+	VerifyOnly             string
+	Update                 bool
+	CheckDuplicateVersions bool
+	CheckVulnerabilities   bool
+	CheckTransitive        bool
+	VerifyAttestations     bool
+	Why                    bool
+	SkipArchived           bool
+	SecurityOnly           bool
+	AnnotateOnly           bool
+	Jobs                   []string
+	StepName               string
+	IncludeFile            string
+	ExcludeFile            string
+	IncludeOwners          []string
+	ExcludeOwners          []string
+	Includes               []string
+	Excludes               []string
+	ReportUnusedRules      bool
+	MaxPinAge              string
+	Since                  string
+	Now                    time.Time
+	StatsFilePath          string
+	AssumeYes              bool
+	// Stdin is used to ask for confirmation. It's nil if the standard input isn't a terminal.
+	Stdin  io.Reader
+	Stderr io.Writer
+	// workspaceStats is the total stats of repositories in workspace mode. Stats of the run are added to it.
+	workspaceStats *Stats
+	// TokenFromStdin is true if the GitHub access token is read from the standard input by --github-token-file -.
+	// Then the standard input can't be used to ask for confirmation.
+	TokenFromStdin bool
+	// Offline checks if actions are pinned without calling GitHub API. Files aren't modified.
+	Offline bool
+	// FailOnChange returns an error if files are modified, which is what the pre-commit framework expects.
+	FailOnChange bool
+	// Resume skips files processed by the previous run which failed midway.
+	Resume bool
+	// NoState disables the state file for --resume.
+	// The state file is shared by runs in the same working directory, so runs in parallel such as pinact hook must disable it.
+	NoState bool
+	// Format is an output format. If this is set, findings are output to Stdout in the format and files aren't modified unless Fix is true.
+	Format string
+	// Sort is the order of findings in outputs. It's either file, action, or severity. The default value is file.
+	Sort string
+	// Fix modifies files even if Format is set, so that a run can both output findings and fix them.
+	Fix    bool
+	Stdout io.Writer
+	// CheckRun creates a check run with annotations of findings if this is set.
+	CheckRun *ParamCheckRun
+	// ReportHTMLFilePath is a file path where a HTML report is written.
+	ReportHTMLFilePath string
+	// ReportSARIFFilePath is a file path where a SARIF report is written. Files are still modified.
+	ReportSARIFFilePath string
+	// BaselineFilePath is a file of accepted findings. Findings in the baseline are neither reported nor fail the run.
+	BaselineFilePath string
+	// WriteBaselineFilePath is a file path where fingerprints of findings are written as a baseline.
+	// Findings are accepted, so they don't fail the run.
+	WriteBaselineFilePath string
+	// NotificationWebhookURL is a URL where a summary of the run is posted.
+	NotificationWebhookURL string
+	// GitHubOutputFilePath is a file path where results of the run are written in the format of GITHUB_OUTPUT.
+	GitHubOutputFilePath string
+	// CheckAnnotationConsistency warns if the same commit hash is annotated with different tags across files.
+	CheckAnnotationConsistency bool
+	// FixAnnotationConsistency normalizes such annotations to the tag verified by GitHub API.
+	FixAnnotationConsistency bool
+	// Estimate outputs the approximate number of API calls needed by the run to Stdout without calling API. Files aren't modified.
+	Estimate bool
+	// NoLock disables the lock file guarding against simultaneous runs in the same repository.
+	NoLock bool
+}
+
+// defaultConfirmThreshold is the default number of changed files over which pinact asks for confirmation.
+const defaultConfirmThreshold = 100
+
+// workflowChange is a pending change of a workflow file.
+// Changes are written after all files are processed so that users can abort the run before any file is modified.
+type workflowChange struct {
+	Path  string
+	Lines []string
 }
 
 func (c *Controller) Run(ctx context.Context, logE *logrus.Entry, param *ParamRun) error {
 	cfg := &Config{}
-	if err := c.readConfig(param.ConfigFilePath, cfg); err != nil {
+	if err := c.readConfig(logE, param.ConfigFilePath, param.PWD, cfg); err != nil {
 		return err
 	}
+	if err := cfg.Init(); err != nil {
+		return fmt.Errorf("initialize the configuration: %w", err)
+	}
+	cfg.Now = param.Now
+	cfg.setFreezeWindows(param.Now)
 	cfg.IsVerify = param.IsVerify
+	if param.VerifyOnly != "" {
+		p, err := regexp.Compile(param.VerifyOnly)
+		if err != nil {
+			return fmt.Errorf("parse --verify-only as a regular expression: %w", err)
+		}
+		cfg.IsVerify = true
+		cfg.VerifyOnly = p
+	}
+	cfg.CheckDuplicateVersions = param.CheckDuplicateVersions
+	cfg.CheckVulnerabilities = param.CheckVulnerabilities
+	cfg.CheckTransitive = param.CheckTransitive
+	cfg.VerifyAttestations = param.VerifyAttestations
+	cfg.Why = param.Why
+	cfg.SkipArchived = param.SkipArchived
+	cfg.SecurityOnly = param.SecurityOnly
+	cfg.AnnotateOnly = param.AnnotateOnly
+	cfg.Jobs = param.Jobs
+	if param.StepName != "" {
+		p, err := regexp.Compile(param.StepName)
+		if err != nil {
+			return fmt.Errorf("parse --step-name as a regular expression: %w", err)
+		}
+		cfg.StepName = p
+	}
+	if param.IncludeFile != "" {
+		p, err := regexp.Compile(param.IncludeFile)
+		if err != nil {
+			return fmt.Errorf("parse --include-file as a regular expression: %w", err)
+		}
+		cfg.IncludeFile = p
+	}
+	if param.ExcludeFile != "" {
+		p, err := regexp.Compile(param.ExcludeFile)
+		if err != nil {
+			return fmt.Errorf("parse --exclude-file as a regular expression: %w", err)
+		}
+		cfg.ExcludeFile = p
+	}
+	if param.MaxPinAge != "" {
+		d, err := parseMaxPinAge(param.MaxPinAge)
+		if err != nil {
+			return fmt.Errorf("parse --max-pin-age: %w", err)
+		}
+		cfg.MaxPinAge = d
+	}
+	if param.Since != "" {
+		d, err := parseMaxPinAge(param.Since)
+		if err != nil {
+			return fmt.Errorf("parse --since: %w", err)
+		}
+		cfg.Since = d
+	}
+	if cfg.Interop != nil && cfg.Interop.Renovate {
+		if err := c.readRenovateIgnores(logE, param.PWD, cfg); err != nil {
+			return err
+		}
+	}
+	cfg.IncludeOwners = append(cfg.IncludeOwners, param.IncludeOwners...)
+	cfg.ExcludeOwners = append(cfg.ExcludeOwners, param.ExcludeOwners...)
+	cfg.Includes = append(cfg.Includes, param.Includes...)
+	cfg.Excludes = append(cfg.Excludes, param.Excludes...)
+	if err := cfg.initActionFilters(); err != nil {
+		return err
+	}
+	if cfg.GitSSH != nil && cfg.GitSSH.Enabled {
+		// Restore the service after the run because the controller can be shared by multiple repositories in workspace mode.
+		repoService := c.repositoriesService
+		host := c.host
+		gitSSHService := NewGitSSHService(cfg.GitSSH)
+		c.repositoriesService = newRepositoriesServiceImpl(gitSSHService)
+		c.host = gitSSHService.host
+		defer func() {
+			c.repositoriesService = repoService
+			c.host = host
+		}()
+	}
+	if cfg.GHECom != nil {
+		// Restore the service after the run because the controller can be shared by multiple repositories in workspace mode.
+		repoService := c.repositoriesService
+		host := c.host
+		gheComService, err := c.newGHEComRepositoriesService(ctx, cfg.GHECom)
+		if err != nil {
+			return err
+		}
+		c.repositoriesService = gheComService
+		c.host = cfg.GHECom.host()
+		defer func() {
+			c.repositoriesService = repoService
+			c.host = host
+		}()
+	}
+	if cfg.ResolverCommand != "" {
+		// Restore the service after the run because the controller can be shared by multiple repositories in workspace mode.
+		repoService := c.repositoriesService
+		c.repositoriesService = newRepositoriesServiceImpl(NewResolverCommandService(cfg.ResolverCommand, param.PWD))
+		defer func() {
+			c.repositoriesService = repoService
+		}()
+	}
+	defer c.enableRateLimits(cfg)()
 	workflowFilePaths, err := c.searchFiles(logE, param.WorkflowFilePaths, cfg, param.PWD)
 	if err != nil {
 		return fmt.Errorf("search target files: %w", err)
 	}
+	if !cfg.ignoreLocal() {
+		workflowFilePaths = c.addLocalActions(logE, workflowFilePaths, param.PWD, cfg)
+		slices.Sort(workflowFilePaths)
+		workflowFilePaths = slices.Compact(workflowFilePaths)
+	}
+	if param.Estimate {
+		return c.estimateAPICalls(logE, workflowFilePaths, cfg, param.PWD, param.Stdout)
+	}
 
-	for _, workflowFilePath := range workflowFilePaths {
+	advisoryURL := cfg.AdvisoryURL
+	if param.Offline {
+		// Only bundled advisories are used in the offline mode.
+		advisoryURL = ""
+	}
+	advisories, err := loadAdvisories(ctx, advisoryURL, cfg.AdvisoryChecksum)
+	if err != nil {
+		return err
+	}
+	cfg.advisories = advisories
+
+	if param.Offline {
+		allow, err := c.readAllowFile(param.PWD)
+		if err != nil {
+			return err
+		}
+		cfg.allow = allow
+		return c.checkUnpinnedWorkflows(logE, workflowFilePaths, cfg, param.PWD, param.Stderr)
+	}
+
+	if !param.NoLock {
+		unlock, err := c.lock(logE, param.PWD, param.Now)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	reporter := c.reporter
+	defer func() {
+		c.reporter = reporter
+	}()
+	if err := validateSortKey(param.Sort, SortFile, SortAction, SortSeverity); err != nil {
+		return err
+	}
+	var report formatReporter
+	switch param.Format {
+	case "":
+	case FormatJSON:
+		report = newJSONReporter(c.reporter, c.version)
+		c.reporter = report
+	case FormatRDJSON:
+		report = newRDJSONReporter(c.reporter, param.PWD, c.version)
+		c.reporter = report
+	case FormatCheckstyle, FormatJUnit:
+		report = newXMLReporter(c.reporter, param.Format, param.PWD, c.version)
+		c.reporter = report
+	default:
+		return fmt.Errorf("unsupported format: %s", param.Format)
+	}
+	var checkRun *findingCollector
+	if param.CheckRun != nil {
+		checkRun = newFindingCollector(c.reporter)
+		c.reporter = checkRun
+	}
+	var htmlReport *findingCollector
+	if param.ReportHTMLFilePath != "" {
+		htmlReport = newFindingCollector(c.reporter)
+		c.reporter = htmlReport
+	}
+	var sarifReport *findingCollector
+	if param.ReportSARIFFilePath != "" {
+		sarifReport = newFindingCollector(c.reporter)
+		c.reporter = sarifReport
+	}
+	var gitHubOutputFindings *findingCollector
+	if param.GitHubOutputFilePath != "" {
+		gitHubOutputFindings = newFindingCollector(c.reporter)
+		c.reporter = gitHubOutputFindings
+	}
+	var notification *findingCollector
+	if param.NotificationWebhookURL != "" {
+		notification = newFindingCollector(c.reporter)
+		c.reporter = notification
+	}
+	// The baseline filter must wrap all reporters so that suppressed findings aren't passed to them.
+	var baseline *baselineFilter
+	if param.BaselineFilePath != "" {
+		b, err := c.readBaseline(param.BaselineFilePath)
+		if err != nil {
+			return err
+		}
+		baseline = newBaselineFilter(c.reporter, param.PWD, b)
+		c.reporter = baseline
+	}
+	// The baseline writer receives all findings including ones in the current baseline.
+	var baselineWriter *findingCollector
+	if param.WriteBaselineFilePath != "" {
+		baselineWriter = newFindingCollector(c.reporter)
+		c.reporter = baselineWriter
+	}
+
+	stats := &Stats{
+		Time:  param.Now,
+		Files: len(workflowFilePaths),
+	}
+	defer printSummary(param.Stderr, stats)
+	if param.workspaceStats != nil {
+		defer param.workspaceStats.add(stats)
+	}
+	if param.CheckAnnotationConsistency || param.FixAnnotationConsistency {
+		cfg.annotations = newAnnotationIndex()
+	}
+	stateFilePath := getStateFilePath(param.PWD)
+	processed := map[string]*stateFile{}
+	switch {
+	case param.NoState:
+		if param.Resume {
+			return errors.New("--resume can't be used without the state file")
+		}
+	case param.Resume:
+		processed, err = c.readState(stateFilePath)
+		if err != nil {
+			return err
+		}
+		logE.WithField("processed_files", len(processed)).Info("resume the previous run")
+	default:
+		if err := c.resetState(stateFilePath); err != nil {
+			return err
+		}
+	}
+	changes := []*workflowChange{}
+	for i, workflowFilePath := range workflowFilePaths {
+		if err := ctx.Err(); err != nil {
+			return cancelRun(logE, i, len(workflowFilePaths), err)
+		}
 		logE := logE.WithField("workflow_file", workflowFilePath)
-		if err := c.runWorkflow(ctx, logE, workflowFilePath, cfg); err != nil {
+		if !filepath.IsAbs(workflowFilePath) {
+			workflowFilePath = filepath.Join(param.PWD, workflowFilePath)
+		}
+		hash := ""
+		if !param.NoState {
+			hash = hashWorkflowFile(workflowFilePath)
+		}
+		if file, ok := processed[workflowFilePath]; ok && file.isProcessed(hash) {
+			logE.Debug("skip the file processed by the previous run")
+			if len(file.Lines) != 0 {
+				changes = append(changes, &workflowChange{
+					Path:  file.Path,
+					Lines: file.Lines,
+				})
+			}
+			continue
+		}
+		fileCtx, span := trace.Start(ctx, "process a file", trace.String("file", workflowFilePath))
+		change, err := c.runWorkflow(fileCtx, logE, workflowFilePath, cfg, stats)
+		span.SetError(err)
+		span.End()
+		if err != nil {
+			if errors.Is(err, errSecretDetected) {
+				return err
+			}
 			logerr.WithError(logE, err).Warn("update a workflow")
+			stats.Errors++
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			// The file may not be processed correctly, so it isn't recorded as processed.
+			return cancelRun(logE, i, len(workflowFilePaths), err)
+		}
+		if change != nil {
+			changes = append(changes, change)
+		}
+		if param.NoState {
+			continue
+		}
+		file := &stateFile{
+			Path: workflowFilePath,
+			Hash: hash,
+		}
+		if change != nil {
+			file.Lines = change.Lines
+		}
+		if err := c.appendState(stateFilePath, file); err != nil {
+			logerr.WithError(logE, err).Warn("record the progress")
 		}
 	}
+	cfg.setSkippedUpdates(stats)
+	if cfg.annotations != nil {
+		changes = c.checkAnnotationConsistency(ctx, logE, cfg, changes, param.FixAnnotationConsistency, stats)
+	}
+	if param.ReportUnusedRules {
+		cfg.reportUnusedRules(logE, c.update)
+	}
+	// written is changes written to files. If --format is set without --fix, no file is written.
+	written := []*workflowChange{}
+	if report == nil || param.Fix {
+		if err := confirmChanges(param, cfg, len(changes)); err != nil {
+			return err
+		}
+		for i, change := range changes {
+			if err := ctx.Err(); err != nil {
+				// Files are modified atomically, so no file is left half-written.
+				logE.WithFields(logrus.Fields{
+					"modified_files": i,
+					"skipped_files":  len(changes) - i,
+				}).Warn("the run is canceled while modifying files")
+				return fmt.Errorf("the run is canceled. You can continue the run by --resume: %w", err)
+			}
+			if err := writeWorkflow(change); err != nil {
+				logerr.WithError(logE, err).WithField("workflow_file", change.Path).Warn("update a workflow")
+				continue
+			}
+			written = append(written, change)
+		}
+	}
+	if !param.NoState {
+		if err := c.fs.Remove(stateFilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logerr.WithError(logE, err).Warn("remove a state file")
+		}
+	}
+	if param.StatsFilePath != "" {
+		if err := c.appendStats(param.StatsFilePath, stats); err != nil {
+			return err
+		}
+	}
+	for _, collector := range []*findingCollector{checkRun, htmlReport, sarifReport, notification} {
+		if collector != nil {
+			collector.sort(param.Sort)
+		}
+	}
+	if report != nil {
+		report.sort(param.Sort)
+		if err := report.write(param.Stdout); err != nil {
+			return err
+		}
+	}
+	if htmlReport != nil {
+		if err := c.writeHTMLReport(param.ReportHTMLFilePath, param.PWD, stats, htmlReport.findings); err != nil {
+			return err
+		}
+	}
+	if sarifReport != nil {
+		if err := c.writeSARIFReport(param.ReportSARIFFilePath, param.PWD, sarifReport.findings); err != nil {
+			return err
+		}
+	}
+	if notification != nil {
+		if err := notify(ctx, param.NotificationWebhookURL, newNotification(param.PWD, stats, notification.findings)); err != nil {
+			logerr.WithError(logE, err).Warn("send a notification")
+		}
+	}
+	if checkRun != nil {
+		if err := c.createCheckRun(ctx, logE, param.CheckRun, param.PWD, checkRun.findings); err != nil {
+			return err
+		}
+	}
+	if baselineWriter != nil {
+		if err := c.writeBaseline(param.WriteBaselineFilePath, param.PWD, baselineWriter.findings); err != nil {
+			return err
+		}
+		// All findings are accepted by the new baseline.
+		stats.CheckFailures -= countFailures(baselineWriter.findings)
+	} else if baseline != nil {
+		stats.CheckFailures -= baseline.suppressedFailures
+	}
+	if gitHubOutputFindings != nil {
+		out := newGitHubOutput(param.PWD, written, len(changes) != 0, len(gitHubOutputFindings.findings))
+		if err := c.writeGitHubOutput(param.GitHubOutputFilePath, out); err != nil {
+			logerr.WithError(logE, err).Warn("write outputs to GITHUB_OUTPUT")
+		}
+	}
+	if stats.Compromised != 0 {
+		return logerr.WithFields(errors.New("compromised actions are used"), logrus.Fields{ //nolint:wrapcheck
+			"compromised_actions": stats.Compromised,
+		})
+	}
+	if stats.CheckFailures != 0 {
+		return logerr.WithFields(errors.New("checks failed"), logrus.Fields{ //nolint:wrapcheck
+			"check_failures": stats.CheckFailures,
+		})
+	}
+	if param.FailOnChange && len(changes) != 0 {
+		return logerr.WithFields(errors.New("files are modified by pinact"), logrus.Fields{ //nolint:wrapcheck
+			"changed_files": len(changes),
+		})
+	}
+	return nil
+}
+
+// checkUnpinnedWorkflows checks if actions are pinned without calling GitHub API.
+// It returns an error if any action isn't pinned.
+func (c *Controller) checkUnpinnedWorkflows(logE *logrus.Entry, workflowFilePaths []string, cfg *Config, pwd string, stderr io.Writer) error {
+	unpinned := 0
+	stats := &Stats{
+		Files: len(workflowFilePaths),
+	}
+	defer printSummary(stderr, stats)
+	for _, workflowFilePath := range workflowFilePaths {
+		logE := logE.WithField("workflow_file", workflowFilePath)
+		if !filepath.IsAbs(workflowFilePath) {
+			workflowFilePath = filepath.Join(pwd, workflowFilePath)
+		}
+		lines, err := c.readWorkflow(workflowFilePath)
+		if err != nil {
+			logerr.WithError(logE, err).Warn("check a workflow")
+			stats.Errors++
+			continue
+		}
+		inputs := getInputLines(lines)
+		for i, line := range lines {
+			if inputs[i] {
+				continue
+			}
+			action := c.getTargetAction(logE, line, cfg)
+			if action == nil {
+				continue
+			}
+			stats.addLine(line, line)
+			if getVersionType(action.Version) == FullCommitSHA {
+				if action.Tag == "" && getPinnedBranch(action) == "" {
+					logE.WithFields(logrus.Fields{
+						"action":      action.Name,
+						"line_number": i + 1,
+						"help_docs":   "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/008.md",
+					}).Warn("the version annotation is missing. Please run pinact run to add it")
+				}
+				if isShortAnnotation(action) {
+					reportShortAnnotation(logE.WithFields(logrus.Fields{
+						"action":      action.Name,
+						"line_number": i + 1,
+					}), cfg, stats)
+				}
+				continue
+			}
+			severity := cfg.getSeverity(checkUnpinned)
+			logE := logE.WithFields(logrus.Fields{
+				"action":      action.Name,
+				"line_number": i + 1,
+			})
+			if cfg.isAllowed(logE, action) {
+				continue
+			}
+			switch severity {
+			case SeverityOff:
+			case SeverityWarn:
+				logCheck(logE, severity, checkUnpinned, "the action isn't pinned")
+			default:
+				unpinned++
+				logE.Error("the action isn't pinned")
+			}
+		}
+		c.checkBranchPins(logE, workflowFilePath, lines)
+		stats.Compromised += c.checkAdvisories(logE, workflowFilePath, lines, cfg.advisories)
+	}
+	if stats.Compromised != 0 {
+		return logerr.WithFields(errors.New("compromised actions are used"), logrus.Fields{ //nolint:wrapcheck
+			"compromised_actions": stats.Compromised,
+		})
+	}
+	if unpinned != 0 {
+		return logerr.WithFields(errors.New("actions aren't pinned. Please run pinact run to pin them"), logrus.Fields{ //nolint:wrapcheck
+			"unpinned_actions": unpinned,
+		})
+	}
+	if stats.CheckFailures != 0 {
+		return logerr.WithFields(errors.New("version annotations aren't full semver. Please run pinact run to expand them"), logrus.Fields{ //nolint:wrapcheck
+			"short_annotations": stats.CheckFailures,
+		})
+	}
 	return nil
 }
 
-func (c *Controller) runWorkflow(ctx context.Context, logE *logrus.Entry, workflowFilePath string, cfg *Config) error {
+// runWorkflow processes a workflow file and returns the change.
+// Dockerfiles, GitLab CI, and devcontainer files are processed by parsers of their file types.
+// If the file isn't changed, it returns nil.
+func (c *Controller) runWorkflow(ctx context.Context, logE *logrus.Entry, workflowFilePath string, cfg *Config, stats *Stats) (*workflowChange, error) {
+	switch cfg.getFileType(workflowFilePath) {
+	case FileTypeDockerfile:
+		return c.runDockerfile(ctx, logE, workflowFilePath, stats)
+	case FileTypeGitLabCI:
+		return c.runImageFile(ctx, logE, workflowFilePath, gitlabCIImagePattern, stats)
+	case FileTypeDevcontainer:
+		return c.runImageFile(ctx, logE, workflowFilePath, devcontainerImagePattern, stats)
+	}
 	lines, err := c.readWorkflow(workflowFilePath)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	c.reporter.OnFileStart(workflowFilePath)
+	var jobs, stepNames []string
+	if len(cfg.Jobs) != 0 || cfg.StepName != nil {
+		jobs = getJobs(lines)
+		stepNames = getStepNames(lines)
+	}
+	if cfg.CheckDuplicateVersions {
+		// Duplicates are checked before lines are patched so that versions written in the file are reported.
+		c.checkDuplicateVersions(logE, workflowFilePath, lines)
 	}
+	inputs := getInputLines(lines)
 	changed := false
 	for i, line := range lines {
+		if inputs[i] {
+			continue
+		}
+		if jobs != nil && !cfg.isSelected(jobs[i], stepNames[i]) {
+			continue
+		}
 		l, err := c.parseLine(ctx, logE, line, cfg)
+		c.reportArchivedActions(workflowFilePath, i, line, cfg)
 		if err != nil {
-			logerr.WithError(logE, err).Error("parse a line")
+			c.reportLineError(logE, workflowFilePath, i, line, err, cfg, stats)
+			stats.addLine(line, line)
 			continue
 		}
+		if line != l {
+			if err := checkSecrets(l); err != nil {
+				return nil, logerr.WithFields(err, logrus.Fields{ //nolint:wrapcheck
+					"line_number": i + 1,
+				})
+			}
+			l = c.reportChange(logE, workflowFilePath, i, line, l, cfg, stats)
+		}
 		if line != l {
 			changed = true
 		}
+		stats.addLine(line, l)
 		lines[i] = l
 	}
+	stats.CheckFailures += c.checkDynamicRefs(logE, workflowFilePath, lines, cfg)
+	c.checkBranchPins(logE, workflowFilePath, lines)
+	if cfg.annotations != nil {
+		cfg.annotations.add(c, workflowFilePath, lines)
+	}
+	stats.Compromised += c.checkAdvisories(logE, workflowFilePath, lines, cfg.advisories)
+	if !cfg.ignoreDocker() {
+		checkDockerImages(logE, lines)
+	}
+	if cfg.CheckVulnerabilities {
+		c.checkVulnerabilities(ctx, logE, workflowFilePath, lines)
+	}
+	if cfg.CheckTransitive {
+		c.checkTransitiveActions(ctx, logE, workflowFilePath, lines)
+	}
+	if cfg.MaxPinAge != 0 {
+		c.checkPinAge(ctx, logE, workflowFilePath, lines, cfg)
+	}
+	if cfg.VerifyAttestations {
+		c.checkAttestations(ctx, logE, workflowFilePath, lines, cfg)
+	}
+	c.reporter.OnFileEnd(workflowFilePath, changed)
 	if !changed {
+		return nil, nil //nolint:nilnil
+	}
+	stats.ChangedFiles++
+	return &workflowChange{
+		Path:  workflowFilePath,
+		Lines: lines,
+	}, nil
+}
+
+// cancelRun logs the progress of the canceled run and returns an error.
+// No file has been modified yet because files are modified after all files are processed.
+func cancelRun(logE *logrus.Entry, processed, total int, err error) error {
+	logE.WithFields(logrus.Fields{
+		"processed_files": processed,
+		"skipped_files":   total - processed,
+	}).Warn("the run is canceled before modifying files")
+	return fmt.Errorf("the run is canceled. You can continue the run by --resume: %w", err)
+}
+
+// writeWorkflow writes a workflow file atomically.
+// The content is written to a temporary file in the same directory and the file is renamed,
+// so the workflow file isn't left half-written even if pinact is interrupted.
+// If the content is unchanged byte-for-byte, the file isn't written so that the modification time is kept.
+func writeWorkflow(change *workflowChange) error {
+	content := []byte(strings.Join(change.Lines, "\n") + "\n")
+	if b, err := os.ReadFile(change.Path); err == nil && bytes.Equal(b, content) {
 		return nil
 	}
-	f, err := os.Create(workflowFilePath)
+	mode := os.FileMode(0o644) //nolint:mnd
+	if fi, err := os.Stat(change.Path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(change.Path), "."+filepath.Base(change.Path)+".pinact-*")
 	if err != nil {
-		return fmt.Errorf("create a workflow file: %w", err)
+		return fmt.Errorf("create a temporary file: %w", err)
 	}
-	defer f.Close()
-	if _, err := f.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath)
+	if _, err := f.Write(content); err != nil {
+		f.Close()
 		return fmt.Errorf("write a workflow file: %w", err)
 	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close a temporary file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return fmt.Errorf("change the permission of a temporary file: %w", err)
+	}
+	if err := os.Rename(tmpPath, change.Path); err != nil {
+		return fmt.Errorf("replace a workflow file: %w", err)
+	}
 	return nil
 }
 
+// confirmChanges asks users for confirmation if the number of changed files exceeds the threshold.
+// This guards against rewriting a large number of files unexpectedly due to a misconfigured file pattern.
+func confirmChanges(param *ParamRun, cfg *Config, changedFiles int) error {
+	threshold := cfg.ConfirmThreshold
+	if threshold == 0 {
+		threshold = defaultConfirmThreshold
+	}
+	if param.AssumeYes || threshold < 0 || changedFiles <= threshold {
+		return nil
+	}
+	fields := logrus.Fields{
+		"changed_files":     changedFiles,
+		"confirm_threshold": threshold,
+	}
+	if param.TokenFromStdin {
+		return logerr.WithFields(errors.New("confirmation is required because too many files would be modified, but the standard input is used by --github-token-file -. Pass --assume-yes to modify them without confirmation"), fields) //nolint:wrapcheck
+	}
+	if param.Stdin == nil {
+		return logerr.WithFields(errors.New("confirmation is required because too many files would be modified, but the standard input isn't a terminal. Pass --assume-yes to modify them without confirmation"), fields) //nolint:wrapcheck
+	}
+	fmt.Fprintf(param.Stderr, "pinact will modify %d files. Do you want to continue? [y/N]: ", changedFiles)
+	answer, err := bufio.NewReader(param.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read the answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return logerr.WithFields(errors.New("the run is aborted because too many files would be modified. Pass --assume-yes to modify them without confirmation"), fields) //nolint:wrapcheck
+}
+
 func (c *Controller) readWorkflow(workflowFilePath string) ([]string, error) {
 	workflowReadFile, err := os.Open(workflowFilePath)
 	if err != nil {
@@ -86,3 +775,24 @@ func (c *Controller) readWorkflow(workflowFilePath string) ([]string, error) {
 	}
 	return lines, nil
 }
+
+func (c *Controller) checkDuplicateVersions(logE *logrus.Entry, workflowFilePath string, lines []string) {
+	for _, dup := range findDuplicateVersions(lines) {
+		versions := strings.Join(dup.Versions, ", ")
+		logE.WithFields(logrus.Fields{
+			"job":         dup.Job,
+			"action":      dup.Action,
+			"versions":    versions,
+			"line_number": dup.Line,
+			"help_docs":   "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/003.md",
+		}).Warn("the same action is used at multiple versions in a job")
+		c.reporter.OnFinding(&Finding{
+			Kind:    FindingKindDuplicateVersions,
+			File:    workflowFilePath,
+			Line:    dup.Line,
+			Before:  lines[dup.Line-1],
+			After:   lines[dup.Line-1],
+			Message: fmt.Sprintf("%s is used at multiple versions in the job %s: %s", dup.Action, dup.Job, versions),
+		})
+	}
+}
@@ -1,9 +1,17 @@
 package run
 
 import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
 )
 
 func TestController_getConfigPath(t *testing.T) {
@@ -43,7 +51,7 @@ func TestController_getConfigPath(t *testing.T) {
 					t.Fatal(err)
 				}
 			}
-			got, err := getConfigPath(fs)
+			got, err := getConfigPath(fs, "")
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -53,3 +61,392 @@ func TestController_getConfigPath(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkConfigSchema(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name  string
+		cfg   string
+		isErr bool
+	}{
+		{
+			name: "normal",
+			cfg:  "version: 1\n",
+		},
+		{
+			name:  "unsupported version",
+			cfg:   "version: 2\n",
+			isErr: true,
+		},
+		{
+			name: "unsupported version with warn",
+			cfg:  "version: 2\nschema_version_check: warn\n",
+		},
+		{
+			name: "empty",
+			cfg:  "",
+		},
+		{
+			name:  "unknown field",
+			cfg:   "ignore_action:\n  - name: actions/checkout\n",
+			isErr: true,
+		},
+		{
+			name: "unknown field with warn",
+			cfg:  "schema_version_check: warn\nfoo: bar\n",
+		},
+		{
+			name:  "invalid schema_version_check",
+			cfg:   "schema_version_check: ignore\n",
+			isErr: true,
+		},
+	}
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &Config{}
+			if err := yaml.Unmarshal([]byte(d.cfg), cfg); err != nil {
+				t.Fatal(err)
+			}
+			err := checkConfigSchema(logE, []byte(d.cfg), cfg)
+			if d.isErr {
+				if err == nil {
+					t.Fatal("error must be returned")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func Test_findUnknownFields(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		cfg  string
+		exp  []*unknownField
+	}{
+		{
+			name: "no unknown field",
+			cfg:  "ignore_actions:\n  - name: actions/checkout\n",
+		},
+		{
+			name: "typo",
+			cfg:  "ignore_action:\n  - name: actions/checkout\n",
+			exp: []*unknownField{
+				{
+					Line:       "1",
+					Name:       "ignore_action",
+					Suggestion: "ignore_actions",
+				},
+			},
+		},
+		{
+			name: "nested typo",
+			cfg:  "files:\n  - patern: foo\n",
+			exp: []*unknownField{
+				{
+					Line:       "2",
+					Name:       "patern",
+					Suggestion: "pattern",
+				},
+			},
+		},
+		{
+			name: "no suggestion",
+			cfg:  "foo: bar\n",
+			exp: []*unknownField{
+				{
+					Line: "1",
+					Name: "foo",
+				},
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			fields, err := findUnknownFields([]byte(d.cfg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(d.exp, fields); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_confirmChanges(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name         string
+		param        *ParamRun
+		cfg          *Config
+		changedFiles int
+		stdin        string
+		noStdin      bool
+		isErr        bool
+	}{
+		{
+			name:         "under the default threshold",
+			param:        &ParamRun{},
+			cfg:          &Config{},
+			changedFiles: 100,
+		},
+		{
+			name:         "yes",
+			param:        &ParamRun{},
+			cfg:          &Config{ConfirmThreshold: 1},
+			changedFiles: 2,
+			stdin:        "y\n",
+		},
+		{
+			name:         "no",
+			param:        &ParamRun{},
+			cfg:          &Config{ConfirmThreshold: 1},
+			changedFiles: 2,
+			stdin:        "n\n",
+			isErr:        true,
+		},
+		{
+			name:         "closed stdin",
+			param:        &ParamRun{},
+			cfg:          &Config{ConfirmThreshold: 1},
+			changedFiles: 2,
+			isErr:        true,
+		},
+		{
+			name:         "not a terminal",
+			param:        &ParamRun{},
+			cfg:          &Config{ConfirmThreshold: 1},
+			changedFiles: 2,
+			noStdin:      true,
+			isErr:        true,
+		},
+		{
+			name:         "token from stdin",
+			param:        &ParamRun{TokenFromStdin: true},
+			cfg:          &Config{ConfirmThreshold: 1},
+			changedFiles: 2,
+			stdin:        "y\n",
+			isErr:        true,
+		},
+		{
+			name:         "not a terminal with assume yes",
+			param:        &ParamRun{AssumeYes: true},
+			cfg:          &Config{ConfirmThreshold: 1},
+			changedFiles: 2,
+			noStdin:      true,
+		},
+		{
+			name:         "assume yes",
+			param:        &ParamRun{AssumeYes: true},
+			cfg:          &Config{ConfirmThreshold: 1},
+			changedFiles: 2,
+		},
+		{
+			name:         "disabled",
+			param:        &ParamRun{},
+			cfg:          &Config{ConfirmThreshold: -1},
+			changedFiles: 1000,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if !d.noStdin {
+				d.param.Stdin = strings.NewReader(d.stdin)
+			}
+			d.param.Stderr = io.Discard
+			err := confirmChanges(d.param, d.cfg, d.changedFiles)
+			if d.isErr {
+				if err == nil {
+					t.Fatal("error must be returned")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestController_checkUnpinnedWorkflows(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name    string
+		content string
+		allow   *AllowFile
+		checks  *Checks
+		isErr   bool
+	}{
+		{
+			name: "pinned",
+			content: `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2
+      - uses: ./.github/actions/foo
+`,
+		},
+		{
+			name: "unpinned",
+			content: `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v3
+`,
+			isErr: true,
+		},
+		{
+			name: "ignored",
+			content: `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v3 # pinact:ignore
+`,
+		},
+		{
+			name: "allowed",
+			content: `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v3
+`,
+			allow: &AllowFile{
+				Exceptions: []*AllowException{
+					{Name: "actions/checkout", Version: "v3", Expires: "2025-01-31"},
+				},
+			},
+		},
+		{
+			name: "exception expired",
+			content: `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v3
+`,
+			allow: &AllowFile{
+				Exceptions: []*AllowException{
+					{Name: "actions/checkout", Expires: "2024-12-31"},
+				},
+			},
+			isErr: true,
+		},
+		{
+			name: "short annotation",
+			content: `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3
+`,
+		},
+		{
+			name: "short annotation error",
+			content: `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3
+`,
+			checks: &Checks{ShortAnnotation: SeverityError},
+			isErr:  true,
+		},
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "test.yaml"), []byte(d.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if d.allow != nil {
+				for _, e := range d.allow.Exceptions {
+					if err := e.init(); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			ctrl := NewController(nil, afero.NewMemMapFs())
+			err := ctrl.checkUnpinnedWorkflows(logE, []string{"test.yaml"}, &Config{Now: now, allow: d.allow, Checks: d.checks}, dir, nil)
+			if d.isErr {
+				if err == nil {
+					t.Fatal("error must be returned")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func Test_writeWorkflow(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	workflowFilePath := filepath.Join(dir, "test.yaml")
+	if err := os.WriteFile(workflowFilePath, []byte("name: test\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeWorkflow(&workflowChange{
+		Path:  workflowFilePath,
+		Lines: []string{"name: test", "on: push"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(workflowFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("name: test\non: push\n", string(b)); diff != "" {
+		t.Fatal(diff)
+	}
+	fi, err := os.Stat(workflowFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Fatalf("the permission must be kept: %s", fi.Mode().Perm())
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("temporary files must be removed: %d files", len(entries))
+	}
+}
+
+func Test_writeWorkflow_unchanged(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	workflowFilePath := filepath.Join(dir, "test.yaml")
+	if err := os.WriteFile(workflowFilePath, []byte("name: test\non: push\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(workflowFilePath, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeWorkflow(&workflowChange{
+		Path:  workflowFilePath,
+		Lines: []string{"name: test", "on: push"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(workflowFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.ModTime().Equal(mtime) {
+		t.Fatalf("the file must not be written: %s", fi.ModTime())
+	}
+}
@@ -0,0 +1,190 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/spf13/afero"
+)
+
+const sarifVersion = "2.1.0"
+
+// sarifReport is a report of findings in SARIF, which is uploaded to GitHub code scanning.
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifReport struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    *sarifTool     `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver *sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	Version        string       `json:"version,omitempty"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	Level     string           `json:"level"`
+	Message   *sarifMessage    `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+	Fixes     []*sarifFix      `json:"fixes,omitempty"`
+	// PartialFingerprints lets code scanning track findings across line moves.
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion           `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifRegion is a range of a file.
+// Lines and columns are 1-based, and the end column is exclusive.
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifFix struct {
+	Description     *sarifMessage          `json:"description"`
+	ArtifactChanges []*sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []*sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   *sarifRegion  `json:"deletedRegion"`
+	InsertedContent *sarifMessage `json:"insertedContent"`
+}
+
+// newSARIFReport converts findings to a report.
+// File paths are converted to paths relative to pwd because code scanning resolves them from the repository root.
+// version is the version of pinact, which lets code scanning tell which version produced results.
+func newSARIFReport(pwd, version string, findings []*Finding) *sarifReport {
+	run := &sarifRun{
+		Tool: &sarifTool{
+			Driver: &sarifDriver{
+				Name:           "pinact",
+				Version:        version,
+				InformationURI: "https://github.com/suzuki-shunsuke/pinact",
+				Rules:          []*sarifRule{},
+			},
+		},
+		Results: make([]*sarifResult, 0, len(findings)),
+	}
+	kinds := []string{}
+	for _, finding := range findings {
+		if !slices.Contains(kinds, finding.Kind) {
+			kinds = append(kinds, finding.Kind)
+		}
+		artifact := &sarifArtifactLocation{
+			URI: getRelPath(pwd, finding.File),
+		}
+		location := &sarifPhysicalLocation{
+			ArtifactLocation: artifact,
+		}
+		if finding.Line != 0 {
+			location.Region = &sarifRegion{
+				StartLine: finding.Line,
+			}
+		}
+		result := &sarifResult{
+			RuleID: finding.Kind,
+			Level:  getSARIFLevel(finding),
+			Message: &sarifMessage{
+				Text: getFindingMessage(finding),
+			},
+			Locations: []*sarifLocation{
+				{PhysicalLocation: location},
+			},
+			PartialFingerprints: map[string]string{
+				sarifFingerprintKey: getFindingFingerprint(pwd, finding),
+			},
+		}
+		if finding.Kind == FindingKindChanged && finding.Line != 0 {
+			result.Fixes = []*sarifFix{
+				{
+					Description: &sarifMessage{Text: "Replace the line"},
+					ArtifactChanges: []*sarifArtifactChange{
+						{
+							ArtifactLocation: artifact,
+							Replacements: []*sarifReplacement{
+								{
+									DeletedRegion: &sarifRegion{
+										StartLine:   finding.Line,
+										StartColumn: 1,
+										EndLine:     finding.Line,
+										EndColumn:   utf8.RuneCountInString(finding.Before) + 1,
+									},
+									InsertedContent: &sarifMessage{Text: finding.After},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+		run.Results = append(run.Results, result)
+	}
+	slices.Sort(kinds)
+	for _, kind := range kinds {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{ID: kind})
+	}
+	return &sarifReport{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: sarifVersion,
+		Runs:    []*sarifRun{run},
+	}
+}
+
+// getSARIFLevel converts the level of a check run annotation to the level of SARIF.
+func getSARIFLevel(finding *Finding) string {
+	if getAnnotationLevel(finding) == "failure" {
+		return "error"
+	}
+	return "warning"
+}
+
+func (c *Controller) writeSARIFReport(reportFilePath, pwd string, findings []*Finding) error {
+	b, err := json.MarshalIndent(newSARIFReport(pwd, c.version, findings), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode a SARIF report as JSON: %w", err)
+	}
+	if err := afero.WriteFile(c.fs, reportFilePath, b, filePermission); err != nil {
+		return fmt.Errorf("write a SARIF report: %w", err)
+	}
+	return nil
+}
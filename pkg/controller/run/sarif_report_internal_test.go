@@ -0,0 +1,141 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func Test_newSARIFReport(t *testing.T) {
+	t.Parallel()
+	findings := []*Finding{
+		{
+			Kind:   FindingKindChanged,
+			File:   "/home/foo/repo/.github/workflows/test.yaml",
+			Line:   4,
+			Before: "      - uses: actions/checkout@v2",
+			After:  "      - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0",
+		},
+		{
+			Kind:    FindingKindCompromisedAction,
+			File:    ".github/workflows/test.yaml",
+			Line:    10,
+			Message: "the action is compromised",
+		},
+	}
+	report := newSARIFReport("/home/foo/repo", "v3.0.0", findings)
+	artifact := &sarifArtifactLocation{URI: ".github/workflows/test.yaml"}
+	exp := &sarifRun{
+		Tool: &sarifTool{
+			Driver: &sarifDriver{
+				Name:           "pinact",
+				Version:        "v3.0.0",
+				InformationURI: "https://github.com/suzuki-shunsuke/pinact",
+				Rules: []*sarifRule{
+					{ID: FindingKindChanged},
+					{ID: FindingKindCompromisedAction},
+				},
+			},
+		},
+		Results: []*sarifResult{
+			{
+				RuleID:  FindingKindChanged,
+				Level:   "warning",
+				Message: &sarifMessage{Text: lintMessage(findings[0].Before, findings[0].After)},
+				Locations: []*sarifLocation{
+					{PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: artifact, Region: &sarifRegion{StartLine: 4}}},
+				},
+				PartialFingerprints: map[string]string{
+					sarifFingerprintKey: getFindingFingerprint("/home/foo/repo", findings[0]),
+				},
+				Fixes: []*sarifFix{
+					{
+						Description: &sarifMessage{Text: "Replace the line"},
+						ArtifactChanges: []*sarifArtifactChange{
+							{
+								ArtifactLocation: artifact,
+								Replacements: []*sarifReplacement{
+									{
+										DeletedRegion:   &sarifRegion{StartLine: 4, StartColumn: 1, EndLine: 4, EndColumn: 34},
+										InsertedContent: &sarifMessage{Text: findings[0].After},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				RuleID:  FindingKindCompromisedAction,
+				Level:   "error",
+				Message: &sarifMessage{Text: "the action is compromised"},
+				Locations: []*sarifLocation{
+					{PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: artifact, Region: &sarifRegion{StartLine: 10}}},
+				},
+				PartialFingerprints: map[string]string{
+					sarifFingerprintKey: getFindingFingerprint("/home/foo/repo", findings[1]),
+				},
+			},
+		},
+	}
+	if report.Version != sarifVersion {
+		t.Fatalf("wanted %s, got %s", sarifVersion, report.Version)
+	}
+	if diff := cmp.Diff([]*sarifRun{exp}, report.Runs); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestController_writeSARIFReport(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name    string
+		version string
+		exp     string
+	}{
+		{
+			name:    "version",
+			version: "v3.0.0",
+			exp:     `{"name":"pinact","version":"v3.0.0","informationUri":"https://github.com/suzuki-shunsuke/pinact","rules":[]}`,
+		},
+		{
+			name: "unknown version",
+			exp:  `{"name":"pinact","informationUri":"https://github.com/suzuki-shunsuke/pinact","rules":[]}`,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			ctrl := NewController(nil, fs)
+			ctrl.version = d.version
+			if err := ctrl.writeSARIFReport("/repo/pinact.sarif", "/repo", nil); err != nil {
+				t.Fatal(err)
+			}
+			b, err := afero.ReadFile(fs, "/repo/pinact.sarif")
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := struct {
+				Runs []struct {
+					Tool struct {
+						Driver json.RawMessage `json:"driver"`
+					} `json:"tool"`
+				} `json:"runs"`
+			}{}
+			if err := json.Unmarshal(b, &report); err != nil {
+				t.Fatal(err)
+			}
+			buf := &bytes.Buffer{}
+			if err := json.Compact(buf, report.Runs[0].Tool.Driver); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(d.exp, buf.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
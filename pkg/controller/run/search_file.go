@@ -5,33 +5,58 @@ import (
 	"io/fs"
 	"path/filepath"
 	"regexp"
+	"slices"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
 
+// searchFiles returns target files.
+// Files are sorted and deduplicated so that the output is stable between runs.
 func (c *Controller) searchFiles(logE *logrus.Entry, workflowFilePaths []string, cfg *Config, pwd string) ([]string, error) {
+	files, err := c.searchUnsortedFiles(logE, workflowFilePaths, cfg, pwd)
+	if err != nil {
+		return nil, err
+	}
+	files = slices.Clone(files)
+	slices.Sort(files)
+	return filterFiles(cfg, slices.Compact(files), pwd), nil
+}
+
+// filterFiles filters files by --include-file and --exclude-file.
+// Patterns are matched with paths relative to pwd.
+func filterFiles(cfg *Config, files []string, pwd string) []string {
+	if cfg.IncludeFile == nil && cfg.ExcludeFile == nil {
+		return files
+	}
+	return slices.DeleteFunc(files, func(file string) bool {
+		if filepath.IsAbs(file) {
+			if rel, err := filepath.Rel(pwd, file); err == nil {
+				file = rel
+			}
+		}
+		file = filepath.ToSlash(file)
+		if cfg.IncludeFile != nil && !cfg.IncludeFile.MatchString(file) {
+			return true
+		}
+		return cfg.ExcludeFile != nil && cfg.ExcludeFile.MatchString(file)
+	})
+}
+
+func (c *Controller) searchUnsortedFiles(logE *logrus.Entry, workflowFilePaths []string, cfg *Config, pwd string) ([]string, error) {
 	if len(workflowFilePaths) != 0 {
 		return workflowFilePaths, nil
 	}
 	if len(cfg.Files) > 0 {
 		return c.searchFilesByConfig(logE, cfg, pwd)
 	}
-	return listWorkflows()
+	return c.listWorkflows(pwd, cfg)
 }
 
 func (c *Controller) searchFilesByConfig(logE *logrus.Entry, cfg *Config, pwd string) ([]string, error) {
-	patterns := make([]*regexp.Regexp, 0, len(cfg.Files))
-	for _, file := range cfg.Files {
-		if file.Pattern == "" {
-			// ignore
-			continue
-		}
-		p, err := regexp.Compile(file.Pattern)
-		if err != nil {
-			return nil, fmt.Errorf("parse files[].pattern as a regular expression: %w", err)
-		}
-		patterns = append(patterns, p)
+	patterns, err := compileFilePatterns(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	files := []string{}
@@ -64,3 +89,19 @@ func (c *Controller) searchFilesByConfig(logE *logrus.Entry, cfg *Config, pwd st
 
 	return files, nil
 }
+
+func compileFilePatterns(cfg *Config) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(cfg.Files))
+	for _, file := range cfg.Files {
+		if file.Pattern == "" {
+			// ignore
+			continue
+		}
+		p, err := regexp.Compile(file.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("parse files[].pattern as a regular expression: %w", err)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,116 @@
+package run
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_searchFiles(t *testing.T) {
+	t.Parallel()
+	f := false
+	data := []struct {
+		name  string
+		args  []string
+		files []string
+		cfg   *Config
+		exp   []string
+	}{
+		{
+			name: "arguments",
+			args: []string{"foo.yaml", "bar.yaml", "foo.yaml"},
+			cfg:  &Config{},
+			exp:  []string{"bar.yaml", "foo.yaml"},
+		},
+		{
+			name: "default",
+			files: []string{
+				"/src/.github/workflows/test.yaml",
+				"/src/.github/workflows/build.yml",
+				"/src/.github/actions/foo/action.yaml",
+				"/src/.github/actions/bar/action.yml",
+				"/src/.github/actions/bar/README.md",
+				"/src/action.yaml",
+			},
+			cfg: &Config{},
+			exp: []string{
+				".github/actions/bar/action.yml",
+				".github/actions/foo/action.yaml",
+				".github/workflows/build.yml",
+				".github/workflows/test.yaml",
+			},
+		},
+		{
+			name: "default without actions",
+			files: []string{
+				"/src/.github/workflows/test.yaml",
+				"/src/.github/actions/foo/action.yaml",
+			},
+			cfg: &Config{
+				Defaults: &Defaults{IncludeActions: &f},
+			},
+			exp: []string{".github/workflows/test.yaml"},
+		},
+		{
+			name: "config",
+			files: []string{
+				"/src/.github/workflows/test.yaml",
+				"/src/.github/workflows/build.yml",
+				"/src/action.yaml",
+				"/src/README.md",
+			},
+			cfg: &Config{
+				Files: []*File{
+					{
+						Pattern: `^\.github/workflows/.*\.ya?ml$`,
+					},
+					{
+						Pattern: `^(.*/)?action\.ya?ml$`,
+					},
+				},
+			},
+			exp: []string{".github/workflows/build.yml", ".github/workflows/test.yaml", "action.yaml"},
+		},
+		{
+			name: "include and exclude files",
+			files: []string{
+				"/src/.github/workflows/deploy-dev.yaml",
+				"/src/.github/workflows/deploy-prod.yaml",
+				"/src/.github/workflows/test.yaml",
+			},
+			cfg: &Config{
+				Files: []*File{
+					{
+						Pattern: `^\.github/workflows/.*\.ya?ml$`,
+					},
+				},
+				IncludeFile: regexp.MustCompile(`deploy`),
+				ExcludeFile: regexp.MustCompile(`-dev\.yaml$`),
+			},
+			exp: []string{".github/workflows/deploy-prod.yaml"},
+		},
+	}
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			fs := afero.NewMemMapFs()
+			for _, file := range d.files {
+				if err := afero.WriteFile(fs, file, []byte(""), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			ctrl := NewController(nil, fs)
+			files, err := ctrl.searchFiles(logE, d.args, d.cfg, "/src")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(d.exp, files); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package run
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+// errSecretDetected means a string pinact would write or post looks like a secret.
+var errSecretDetected = errors.New("a secret-looking string is found in the content pinact would write. The content isn't written")
+
+type secretPattern struct {
+	Name    string
+	pattern *regexp.Regexp
+}
+
+// secretPatterns are patterns of secrets blocked by push protection of GitHub and other secret scanners.
+// pinact never writes secrets intentionally, but templates of comments and resolver commands are configurable,
+// so this is a defense in depth against leaking secrets into files and comments.
+var secretPatterns = []*secretPattern{
+	{Name: "github_token", pattern: regexp.MustCompile(`\b(?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}\b`)},
+	{Name: "github_fine_grained_token", pattern: regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{82}\b`)},
+	{Name: "aws_access_key_id", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "google_api_key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{Name: "slack_token", pattern: regexp.MustCompile(`\bxox[abprs]-[0-9A-Za-z-]{10,}`)},
+	{Name: "slack_webhook_url", pattern: regexp.MustCompile(`https://hooks\.slack\.com/services/[A-Za-z0-9/]+`)},
+	{Name: "private_key", pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+}
+
+// detectSecret returns the name of the pattern matching with the text.
+// If no pattern matches, it returns an empty string.
+func detectSecret(text string) string {
+	for _, p := range secretPatterns {
+		if p.pattern.MatchString(text) {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// checkSecrets returns an error if any text looks like a secret.
+// The matched string isn't included in the error so that the secret isn't leaked to logs.
+func checkSecrets(texts ...string) error {
+	for _, text := range texts {
+		if name := detectSecret(text); name != "" {
+			return logerr.WithFields(errSecretDetected, logrus.Fields{ //nolint:wrapcheck
+				"secret_type": name,
+				"help_docs":   "https://github.com/suzuki-shunsuke/pinact/blob/main/docs/codes/014.md",
+			})
+		}
+	}
+	return nil
+}
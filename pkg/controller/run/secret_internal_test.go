@@ -0,0 +1,59 @@
+package run
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_detectSecret(t *testing.T) {
+	t.Parallel()
+	// Secrets are built at runtime so that secret scanners don't detect test data.
+	data := []struct {
+		name string
+		text string
+		exp  string
+	}{
+		{
+			name: "pinned action",
+			text: "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+		},
+		{
+			name: "github token",
+			text: "  - uses: actions/checkout@v4 # " + "ghp_" + strings.Repeat("a", 36),
+			exp:  "github_token",
+		},
+		{
+			name: "too short github token",
+			text: "ghp_" + strings.Repeat("a", 20),
+		},
+		{
+			name: "aws access key id",
+			text: "AKIA" + strings.Repeat("A", 16),
+			exp:  "aws_access_key_id",
+		},
+		{
+			name: "private key",
+			text: "-----BEGIN " + "RSA PRIVATE KEY-----",
+			exp:  "private_key",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if name := detectSecret(d.text); name != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, name)
+			}
+		})
+	}
+}
+
+func Test_checkSecrets(t *testing.T) {
+	t.Parallel()
+	if err := checkSecrets("foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSecrets("foo", "xoxb-"+strings.Repeat("0", 12)); !errors.Is(err, errSecretDetected) {
+		t.Fatalf("wanted errSecretDetected, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package run
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+// isRecentlyPinned returns true if the current version of the action was released within --since.
+// The commit date of the current version is regarded as the release date, which takes one API call instead of listing releases and tags.
+// If the date can't be got, the action is updated as usual.
+func (c *Controller) isRecentlyPinned(ctx context.Context, logE *logrus.Entry, cfg *Config, action *Action) bool {
+	if cfg.Since == 0 {
+		return false
+	}
+	// GitHub API resolves not only commit hashes but also tags.
+	date, _, err := c.repositoriesService.GetCommitDate(ctx, action.RepoOwner, action.RepoName, action.Version)
+	if err != nil {
+		logerr.WithError(logE, err).Debug("get the date of the current version")
+		return false
+	}
+	if cfg.Now.Sub(date) >= cfg.Since {
+		return false
+	}
+	cfg.logIgnored(logE.WithField("released_at", date.Format(time.RFC3339)), "skip updating the action as the current version was released within --since")
+	cfg.skipUpdate(skipReasonSince)
+	return true
+}
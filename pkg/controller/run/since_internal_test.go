@@ -0,0 +1,71 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_isRecentlyPinned(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	data := []struct {
+		name    string
+		since   time.Duration
+		version string
+		exp     bool
+	}{
+		{
+			name:    "--since isn't set",
+			version: "v4.2.2",
+		},
+		{
+			name:    "released recently",
+			since:   30 * 24 * time.Hour,
+			version: "v4.2.2",
+			exp:     true,
+		},
+		{
+			name:    "released long ago",
+			since:   30 * 24 * time.Hour,
+			version: "11bd71901bbe5b1630ceea73d27597364c9af683",
+		},
+		{
+			name:    "the date isn't found",
+			since:   30 * 24 * time.Hour,
+			version: "v4",
+		},
+	}
+	ctx := context.Background()
+	logE := logrus.NewEntry(logrus.New())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(&RepositoriesServiceImpl{
+				commitDates: map[string]*GetCommitDateResult{
+					"actions/checkout/v4.2.2": {
+						Date: now.Add(-7 * 24 * time.Hour),
+					},
+					"actions/checkout/11bd71901bbe5b1630ceea73d27597364c9af683": {
+						Date: now.Add(-90 * 24 * time.Hour),
+					},
+					"actions/checkout/v4": {
+						err: errors.New("not found"),
+					},
+				},
+			}, afero.NewMemMapFs())
+			cfg := &Config{Since: d.since, Now: now}
+			action := &Action{RepoOwner: "actions", RepoName: "checkout", Version: d.version}
+			if f := ctrl.isRecentlyPinned(ctx, logE, cfg, action); f != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, f)
+			}
+			if d.exp && cfg.skippedUpdates[skipReasonSince] != 1 {
+				t.Fatal("the skipped update must be counted")
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package run
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+const (
+	// SortFile sorts findings and actions by file paths and line numbers. This is the default.
+	SortFile = "file"
+	// SortAction sorts findings and actions by action names.
+	SortAction = "action"
+	// SortSeverity sorts findings by severities. Failures come first.
+	SortSeverity = "severity"
+)
+
+// validateSortKey returns an error if the key of --sort isn't one of keys.
+// An empty key is valid and means the default order.
+func validateSortKey(key string, keys ...string) error {
+	if key == "" || slices.Contains(keys, key) {
+		return nil
+	}
+	return fmt.Errorf("--sort must be one of %v: %s", keys, key)
+}
+
+// getFindingActionName returns the action name of the finding's line.
+// It's empty if the line doesn't use an action.
+func getFindingActionName(finding *Finding) string {
+	action := parseAction(finding.Before)
+	if action == nil {
+		return ""
+	}
+	return action.Name
+}
+
+// getSeverityRank returns the rank of the finding's severity. Failures are ranked before warnings.
+func getSeverityRank(finding *Finding) int {
+	if getAnnotationLevel(finding) == "failure" {
+		return 0
+	}
+	return 1
+}
+
+// sortFindings sorts findings by the key of --sort.
+// Findings are always sorted so that outputs are stable between runs on an unchanged repository.
+// Ties are broken by file paths, line numbers, kinds, and messages.
+func sortFindings(findings []*Finding, key string) {
+	slices.SortStableFunc(findings, func(a, b *Finding) int {
+		var c int
+		switch key {
+		case SortAction:
+			c = cmp.Compare(getFindingActionName(a), getFindingActionName(b))
+		case SortSeverity:
+			c = cmp.Compare(getSeverityRank(a), getSeverityRank(b))
+		}
+		if c != 0 {
+			return c
+		}
+		return cmp.Or(
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+}
+
+// sortListedActions sorts actions of pinact list by the key of --sort.
+func sortListedActions(actions []*listedAction, key string) {
+	slices.SortStableFunc(actions, func(a, b *listedAction) int {
+		if key == SortAction {
+			if c := cmp.Compare(a.Name, b.Name); c != 0 {
+				return c
+			}
+		}
+		return cmp.Or(
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Line, b.Line),
+		)
+	})
+}
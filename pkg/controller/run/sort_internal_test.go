@@ -0,0 +1,93 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_sortFindings(t *testing.T) {
+	t.Parallel()
+	checkout := &Finding{Kind: FindingKindChanged, File: "b.yaml", Line: 3, Before: "      - uses: actions/checkout@v4", Severity: SeverityWarn}
+	cache := &Finding{Kind: FindingKindChanged, File: "b.yaml", Line: 1, Before: "      - uses: actions/cache@v4", Severity: SeverityWarn}
+	compromised := &Finding{Kind: FindingKindCompromisedAction, File: "c.yaml", Line: 2, Before: "      - uses: tj-actions/changed-files@v45"}
+	duplicate := &Finding{Kind: FindingKindDuplicateVersions, File: "a.yaml", Message: "actions/checkout is used at multiple versions"}
+	data := []struct {
+		name string
+		key  string
+		exp  []*Finding
+	}{
+		{
+			name: "default",
+			exp:  []*Finding{duplicate, cache, checkout, compromised},
+		},
+		{
+			name: "file",
+			key:  SortFile,
+			exp:  []*Finding{duplicate, cache, checkout, compromised},
+		},
+		{
+			name: "action",
+			key:  SortAction,
+			exp:  []*Finding{duplicate, cache, checkout, compromised},
+		},
+		{
+			name: "severity",
+			key:  SortSeverity,
+			exp:  []*Finding{compromised, duplicate, cache, checkout},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			findings := []*Finding{compromised, checkout, duplicate, cache}
+			sortFindings(findings, d.key)
+			if diff := cmp.Diff(d.exp, findings); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_sortListedActions(t *testing.T) {
+	t.Parallel()
+	actions := []*listedAction{
+		{File: "b.yaml", Line: 2, Name: "actions/cache"},
+		{File: "a.yaml", Line: 5, Name: "actions/setup-go"},
+		{File: "b.yaml", Line: 1, Name: "actions/checkout"},
+		{File: "a.yaml", Line: 1, Name: "actions/checkout"},
+	}
+	sortListedActions(actions, SortAction)
+	exp := []*listedAction{
+		{File: "b.yaml", Line: 2, Name: "actions/cache"},
+		{File: "a.yaml", Line: 1, Name: "actions/checkout"},
+		{File: "b.yaml", Line: 1, Name: "actions/checkout"},
+		{File: "a.yaml", Line: 5, Name: "actions/setup-go"},
+	}
+	if diff := cmp.Diff(exp, actions); diff != "" {
+		t.Fatal(diff)
+	}
+	sortListedActions(actions, "")
+	exp = []*listedAction{
+		{File: "a.yaml", Line: 1, Name: "actions/checkout"},
+		{File: "a.yaml", Line: 5, Name: "actions/setup-go"},
+		{File: "b.yaml", Line: 1, Name: "actions/checkout"},
+		{File: "b.yaml", Line: 2, Name: "actions/cache"},
+	}
+	if diff := cmp.Diff(exp, actions); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_validateSortKey(t *testing.T) {
+	t.Parallel()
+	if err := validateSortKey("", SortFile, SortAction); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateSortKey(SortAction, SortFile, SortAction); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateSortKey(SortSeverity, SortFile, SortAction); err == nil {
+		t.Fatal("error must be returned")
+	}
+}
@@ -0,0 +1,102 @@
+package run
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// stateFile is a progress of a run recorded in a state file.
+// Each processed file is appended to the state file as JSON Lines,
+// so that `pinact run --resume` can continue a run which fails midway without calling GitHub API again.
+type stateFile struct {
+	Path string `json:"path"`
+	// Hash is the SHA256 hash of the file content before the change.
+	// If the file is changed after the previous run, the file is processed again.
+	Hash string `json:"hash,omitempty"`
+	// Lines is the content of the file after the change. If the file isn't changed, this is empty.
+	Lines []string `json:"lines,omitempty"`
+}
+
+// getStateFilePath returns a state file path in the temporary directory.
+// The path depends on the working directory so that runs in different repositories don't conflict.
+func getStateFilePath(pwd string) string {
+	h := sha256.Sum256([]byte(pwd))
+	return filepath.Join(os.TempDir(), "pinact", "state-"+hex.EncodeToString(h[:8])+".jsonl")
+}
+
+// hashWorkflowFile returns the SHA256 hash of the file content.
+// If the file can't be read, it returns an empty string, which doesn't match any recorded hash.
+func hashWorkflowFile(workflowFilePath string) string {
+	b, err := os.ReadFile(workflowFilePath)
+	if err != nil {
+		return ""
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// isProcessed returns true if the file was processed by the previous run and isn't changed after that.
+func (f *stateFile) isProcessed(hash string) bool {
+	return f.Hash != "" && f.Hash == hash
+}
+
+// readState reads a state file and returns processed files.
+// If the state file doesn't exist, it returns an empty map.
+func (c *Controller) readState(stateFilePath string) (map[string]*stateFile, error) {
+	files := map[string]*stateFile{}
+	f, err := c.fs.Open(stateFilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return files, nil
+		}
+		return nil, fmt.Errorf("open a state file: %w", err)
+	}
+	defer f.Close()
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		file := &stateFile{}
+		if err := dec.Decode(file); err != nil {
+			if errors.Is(err, io.EOF) {
+				return files, nil
+			}
+			// The last line may be broken if pinact was killed while writing it.
+			return files, nil //nolint:nilerr
+		}
+		files[file.Path] = file
+	}
+}
+
+func (c *Controller) resetState(stateFilePath string) error {
+	if err := c.fs.MkdirAll(filepath.Dir(stateFilePath), 0o755); err != nil { //nolint:mnd
+		return fmt.Errorf("create a directory for a state file: %w", err)
+	}
+	if err := afero.WriteFile(c.fs, stateFilePath, nil, filePermission); err != nil {
+		return fmt.Errorf("create a state file: %w", err)
+	}
+	return nil
+}
+
+func (c *Controller) appendState(stateFilePath string, file *stateFile) error {
+	b, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("encode a state as JSON: %w", err)
+	}
+	f, err := c.fs.OpenFile(stateFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePermission)
+	if err != nil {
+		return fmt.Errorf("open a state file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("write a state to a state file: %w", err)
+	}
+	return nil
+}
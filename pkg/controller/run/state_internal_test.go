@@ -0,0 +1,71 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestController_readState(t *testing.T) {
+	t.Parallel()
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	stateFilePath := getStateFilePath("/home/foo/repo")
+	files, err := ctrl.readState(stateFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("state must be empty: %v", files)
+	}
+	if err := ctrl.resetState(stateFilePath); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]*stateFile{
+		"/home/foo/repo/.github/workflows/test.yaml": {
+			Path:  "/home/foo/repo/.github/workflows/test.yaml",
+			Lines: []string{"  - uses: actions/checkout@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v2.7.0"},
+		},
+		"/home/foo/repo/.github/workflows/release.yaml": {
+			Path: "/home/foo/repo/.github/workflows/release.yaml",
+		},
+	}
+	for _, file := range []string{"/home/foo/repo/.github/workflows/test.yaml", "/home/foo/repo/.github/workflows/release.yaml"} {
+		if err := ctrl.appendState(stateFilePath, exp[file]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err = ctrl.readState(stateFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(exp, files); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_stateFile_isProcessed(t *testing.T) {
+	t.Parallel()
+	workflowFilePath := filepath.Join(t.TempDir(), "test.yaml")
+	if err := os.WriteFile(workflowFilePath, []byte("  - uses: actions/checkout@v2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file := &stateFile{
+		Path: workflowFilePath,
+		Hash: hashWorkflowFile(workflowFilePath),
+	}
+	if !file.isProcessed(hashWorkflowFile(workflowFilePath)) {
+		t.Fatal("the file must be regarded as processed if it isn't changed")
+	}
+	if err := os.WriteFile(workflowFilePath, []byte("  - uses: actions/checkout@v3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if file.isProcessed(hashWorkflowFile(workflowFilePath)) {
+		t.Fatal("the file must be processed again if it's changed")
+	}
+	if (&stateFile{Path: workflowFilePath}).isProcessed("") {
+		t.Fatal("the file must be processed again if the hash isn't recorded")
+	}
+}
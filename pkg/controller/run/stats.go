@@ -0,0 +1,193 @@
+package run
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Stats is a summary of a run.
+// Stats are appended to a stats file as JSON Lines so that the progress of pinning can be tracked over time.
+type Stats struct {
+	Time         time.Time `json:"time"`
+	Files        int       `json:"files"`
+	ChangedFiles int       `json:"changed_files"`
+	Actions      int       `json:"actions"`
+	Pinned       int       `json:"pinned"`
+	Unpinned     int       `json:"unpinned"`
+	ChangedLines int       `json:"changed_lines"`
+	Compromised  int       `json:"compromised,omitempty"`
+	// CheckFailures is the number of findings of checks whose severity is error.
+	CheckFailures int `json:"check_failures,omitempty"`
+	// SkippedUpdates is the number of skipped updates.
+	SkippedUpdates int `json:"skipped_updates,omitempty"`
+	// SkippedUpdatesByReason is the number of skipped updates per reason such as freeze window, cooldown, and --since.
+	SkippedUpdatesByReason map[string]int `json:"skipped_updates_by_reason,omitempty"`
+	// Errors is the number of lines and files which pinact failed to process.
+	Errors int `json:"errors,omitempty"`
+}
+
+// summary returns a single-line summary of the run.
+// Counts which are zero are omitted except for changed files and pinned actions.
+// e.g. 3 files changed, 12 lines changed, 40/42 actions pinned, 2 updates skipped (freeze window), 1 update skipped (cooldown), 1 error
+func (s *Stats) summary() string {
+	items := []string{
+		plural(s.ChangedFiles, "file", "files") + " changed",
+	}
+	if s.ChangedLines != 0 {
+		items = append(items, plural(s.ChangedLines, "line", "lines")+" changed")
+	}
+	items = append(items, fmt.Sprintf("%d/%s pinned", s.Pinned, plural(s.Actions, "action", "actions")))
+	items = append(items, s.skippedUpdatesSummary()...)
+	if s.Compromised != 0 {
+		items = append(items, plural(s.Compromised, "compromised action", "compromised actions"))
+	}
+	if s.CheckFailures != 0 {
+		items = append(items, plural(s.CheckFailures, "check failure", "check failures"))
+	}
+	if s.Errors != 0 {
+		items = append(items, plural(s.Errors, "error", "errors"))
+	}
+	return strings.Join(items, ", ")
+}
+
+// skippedUpdatesSummary returns the number of skipped updates per reason.
+// Stats written by old versions don't have reasons, so only the total is returned.
+func (s *Stats) skippedUpdatesSummary() []string {
+	if len(s.SkippedUpdatesByReason) == 0 {
+		if s.SkippedUpdates == 0 {
+			return nil
+		}
+		return []string{plural(s.SkippedUpdates, "update", "updates") + " skipped"}
+	}
+	items := []string{}
+	for _, reason := range skipReasons {
+		if n := s.SkippedUpdatesByReason[reason]; n != 0 {
+			items = append(items, fmt.Sprintf("%s skipped (%s)", plural(n, "update", "updates"), reason))
+		}
+	}
+	return items
+}
+
+// add adds the other stats to the stats.
+// It's used to output the total of repositories in workspace mode.
+func (s *Stats) add(other *Stats) {
+	s.Files += other.Files
+	s.ChangedFiles += other.ChangedFiles
+	s.Actions += other.Actions
+	s.Pinned += other.Pinned
+	s.Unpinned += other.Unpinned
+	s.ChangedLines += other.ChangedLines
+	s.Compromised += other.Compromised
+	s.CheckFailures += other.CheckFailures
+	s.SkippedUpdates += other.SkippedUpdates
+	s.Errors += other.Errors
+	for reason, n := range other.SkippedUpdatesByReason {
+		if s.SkippedUpdatesByReason == nil {
+			s.SkippedUpdatesByReason = map[string]int{}
+		}
+		s.SkippedUpdatesByReason[reason] += n
+	}
+}
+
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// printSummary outputs the summary of the run to stderr so that CI logs show the outcome at a glance.
+func printSummary(stderr io.Writer, stats *Stats) {
+	if stderr == nil {
+		return
+	}
+	fmt.Fprintln(stderr, "pinact: "+stats.summary())
+}
+
+// addLine counts an action line after it's processed.
+func (s *Stats) addLine(oldLine, newLine string) {
+	action := parseAction(newLine)
+	if action == nil {
+		return
+	}
+	s.Actions++
+	if getVersionType(action.Version) == FullCommitSHA {
+		s.Pinned++
+	} else {
+		s.Unpinned++
+	}
+	if oldLine != newLine {
+		s.ChangedLines++
+	}
+}
+
+func (c *Controller) appendStats(statsFilePath string, stats *Stats) error {
+	b, err := json.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("encode stats as JSON: %w", err)
+	}
+	f, err := c.fs.OpenFile(statsFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePermission)
+	if err != nil {
+		return fmt.Errorf("open a stats file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("write stats to a stats file: %w", err)
+	}
+	return nil
+}
+
+func (c *Controller) readStats(statsFilePath string) ([]*Stats, error) {
+	f, err := c.fs.Open(statsFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("open a stats file: %w", err)
+	}
+	defer f.Close()
+	list := []*Stats{}
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		stats := &Stats{}
+		if err := dec.Decode(stats); err != nil {
+			if errors.Is(err, io.EOF) {
+				return list, nil
+			}
+			return nil, fmt.Errorf("decode a stats file: %w", err)
+		}
+		list = append(list, stats)
+	}
+}
+
+// ShowStats outputs trends of stats recorded by `pinact run --stats-file`.
+func (c *Controller) ShowStats(stdout io.Writer, statsFilePath string) error {
+	if f, err := afero.Exists(c.fs, statsFilePath); err != nil {
+		return fmt.Errorf("check if a stats file exists: %w", err)
+	} else if !f {
+		return fmt.Errorf("a stats file isn't found: %s", statsFilePath)
+	}
+	list, err := c.readStats(statsFilePath)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0) //nolint:mnd
+	fmt.Fprintln(w, "TIME\tFILES\tACTIONS\tPINNED\tUNPINNED\tPINNED RATE\tCHANGED LINES")
+	for _, stats := range list {
+		rate := "-"
+		if stats.Actions > 0 {
+			rate = fmt.Sprintf("%.1f%%", float64(stats.Pinned)*100/float64(stats.Actions)) //nolint:mnd
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\t%d\n", stats.Time.Format(time.RFC3339), stats.Files, stats.Actions, stats.Pinned, stats.Unpinned, rate, stats.ChangedLines)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("output stats: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package run
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+func TestController_appendStats(t *testing.T) {
+	t.Parallel()
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	now, err := time.Parse(time.RFC3339, "2024-06-01T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := &Stats{
+		Time:  now,
+		Files: 1,
+	}
+	stats.addLine("  - uses: actions/checkout@v4", "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2")
+	stats.addLine("  - uses: actions/setup-go@v5", "  - uses: actions/setup-go@v5")
+	stats.addLine("  - run: echo", "  - run: echo")
+	exp := []*Stats{
+		stats,
+		stats,
+	}
+	for range exp {
+		if err := ctrl.appendStats("stats.jsonl", stats); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := ctrl.readStats("stats.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(exp, list); diff != "" {
+		t.Fatal(diff)
+	}
+	if stats.Actions != 2 || stats.Pinned != 1 || stats.Unpinned != 1 || stats.ChangedLines != 1 {
+		t.Fatalf("stats are wrong: %+v", stats)
+	}
+}
+
+func TestStats_summary(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name  string
+		stats *Stats
+		exp   string
+	}{
+		{
+			name:  "no change",
+			stats: &Stats{Actions: 1, Pinned: 1},
+			exp:   "0 files changed, 1/1 action pinned",
+		},
+		{
+			name: "all",
+			stats: &Stats{
+				ChangedFiles:   3,
+				ChangedLines:   12,
+				Actions:        42,
+				Pinned:         40,
+				SkippedUpdates: 4,
+				SkippedUpdatesByReason: map[string]int{
+					skipReasonSince:        1,
+					skipReasonFreezeWindow: 2,
+					skipReasonCooldown:     1,
+				},
+				Compromised:   1,
+				CheckFailures: 2,
+				Errors:        1,
+			},
+			exp: "3 files changed, 12 lines changed, 40/42 actions pinned, 2 updates skipped (freeze window), 1 update skipped (cooldown), 1 update skipped (--since), 1 compromised action, 2 check failures, 1 error",
+		},
+		{
+			name:  "skipped updates without reasons",
+			stats: &Stats{Actions: 1, Pinned: 1, SkippedUpdates: 2},
+			exp:   "0 files changed, 1/1 action pinned, 2 updates skipped",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(d.exp, d.stats.summary()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestStats_add(t *testing.T) {
+	t.Parallel()
+	total := &Stats{}
+	total.add(&Stats{Files: 2, ChangedFiles: 1, Actions: 3, Pinned: 2, Unpinned: 1, SkippedUpdates: 1, SkippedUpdatesByReason: map[string]int{skipReasonCooldown: 1}})
+	total.add(&Stats{Files: 1, Actions: 1, Pinned: 1, Errors: 1, SkippedUpdates: 1, SkippedUpdatesByReason: map[string]int{skipReasonCooldown: 1}})
+	exp := &Stats{Files: 3, ChangedFiles: 1, Actions: 4, Pinned: 3, Unpinned: 1, Errors: 1, SkippedUpdates: 2, SkippedUpdatesByReason: map[string]int{skipReasonCooldown: 2}}
+	if diff := cmp.Diff(exp, total); diff != "" {
+		t.Fatal(diff)
+	}
+}
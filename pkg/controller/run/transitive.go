@@ -0,0 +1,113 @@
+package run
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+// maxReusableWorkflowDepth is the maximum depth of nested reusable workflows.
+// GitHub Actions allows to nest reusable workflows up to four levels.
+const maxReusableWorkflowDepth = 4
+
+// reusableWorkflow is a reference to a reusable workflow in another repository.
+// e.g. suzuki-shunsuke/foo/.github/workflows/bar.yaml@<commit hash>
+type reusableWorkflow struct {
+	Owner string
+	Repo  string
+	Path  string
+	Ref   string
+}
+
+func (w *reusableWorkflow) String() string {
+	return w.Owner + "/" + w.Repo + "/" + w.Path + "@" + w.Ref
+}
+
+func parseReusableWorkflow(action *Action) *reusableWorkflow {
+	owner, rest, ok := strings.Cut(action.Name, "/")
+	if !ok {
+		return nil
+	}
+	repo, path, ok := strings.Cut(rest, "/")
+	if !ok || !strings.HasPrefix(path, ".github/workflows/") {
+		return nil
+	}
+	return &reusableWorkflow{
+		Owner: owner,
+		Repo:  repo,
+		Path:  path,
+		Ref:   action.Version,
+	}
+}
+
+// transitiveActions is a summary of actions used by a reusable workflow transitively.
+type transitiveActions struct {
+	Actions  int
+	Unpinned int
+}
+
+// checkTransitiveActions resolves reusable workflows called in lines and reports actions used by them transitively.
+// Unpinned actions in reusable workflows you don't own can't be fixed by pinact, but they're worth knowing
+// because they run with your secrets.
+func (c *Controller) checkTransitiveActions(ctx context.Context, logE *logrus.Entry, workflowFilePath string, lines []string) {
+	for i, line := range lines {
+		action := parseAction(line)
+		if action == nil {
+			continue
+		}
+		workflow := parseReusableWorkflow(action)
+		if workflow == nil {
+			continue
+		}
+		logE := logE.WithFields(logrus.Fields{
+			"reusable_workflow": workflow.String(),
+			"line_number":       i + 1,
+		})
+		summary := &transitiveActions{}
+		c.walkReusableWorkflow(ctx, logE, workflowFilePath, i+1, workflow, summary, 1)
+		logE.WithFields(logrus.Fields{
+			"actions":          summary.Actions,
+			"unpinned_actions": summary.Unpinned,
+		}).Info("actions used by the reusable workflow transitively")
+	}
+}
+
+func (c *Controller) walkReusableWorkflow(ctx context.Context, logE *logrus.Entry, workflowFilePath string, lineNumber int, workflow *reusableWorkflow, summary *transitiveActions, depth int) {
+	if depth > maxReusableWorkflowDepth {
+		return
+	}
+	content, _, err := c.repositoriesService.GetFileContent(ctx, workflow.Owner, workflow.Repo, workflow.Path, workflow.Ref)
+	if err != nil {
+		logerr.WithError(logE, err).Warn("get the content of the reusable workflow")
+		return
+	}
+	for _, line := range strings.Split(content, "\n") {
+		action := parseAction(line)
+		if action == nil || isDockerAction(action.Name) {
+			continue
+		}
+		summary.Actions++
+		if getVersionType(action.Version) != FullCommitSHA {
+			summary.Unpinned++
+			logE.WithFields(logrus.Fields{
+				"called_workflow": workflow.String(),
+				"action":          action.Name,
+				"version":         action.Version,
+				"finding":         "unpinned-transitive-action",
+			}).Warn("the reusable workflow uses an unpinned action")
+			c.reporter.OnFinding(&Finding{
+				Kind:    FindingKindUnpinnedTransitiveAction,
+				File:    workflowFilePath,
+				Line:    lineNumber,
+				Before:  line,
+				After:   line,
+				Message: workflow.String() + " uses " + action.Name + "@" + action.Version,
+			})
+		}
+		if nested := parseReusableWorkflow(action); nested != nil {
+			c.walkReusableWorkflow(ctx, logE, workflowFilePath, lineNumber, nested, summary, depth+1)
+		}
+	}
+}
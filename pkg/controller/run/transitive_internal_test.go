@@ -0,0 +1,51 @@
+package run
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_checkTransitiveActions(t *testing.T) {
+	t.Parallel()
+	ctrl := NewController(&RepositoriesServiceImpl{
+		contents: map[string]*GetFileContentResult{
+			"suzuki-shunsuke/foo/.github/workflows/test.yaml@ee0669bd1cc54295c223e0bb666b733df41de1c5": {
+				Content: `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2
+      - uses: actions/setup-go@v5
+  nested:
+    uses: suzuki-shunsuke/bar/.github/workflows/test.yaml@main
+`,
+			},
+			"suzuki-shunsuke/bar/.github/workflows/test.yaml@main": {
+				Content: `jobs:
+  test:
+    steps:
+      - uses: actions/cache@v4
+`,
+			},
+		},
+	}, afero.NewMemMapFs())
+	reporter := &testReporter{}
+	ctrl.SetReporter(reporter)
+	lines := []string{
+		"jobs:",
+		"  test:",
+		"    uses: suzuki-shunsuke/foo/.github/workflows/test.yaml@ee0669bd1cc54295c223e0bb666b733df41de1c5 # v1.0.0",
+	}
+	ctrl.checkTransitiveActions(context.Background(), logrus.NewEntry(logrus.New()), "test.yaml", lines)
+	// actions/setup-go@v5, suzuki-shunsuke/bar/.github/workflows/test.yaml@main, and actions/cache@v4
+	if len(reporter.findings) != 3 {
+		t.Fatalf("wanted 3 findings, got %d", len(reporter.findings))
+	}
+	for _, finding := range reporter.findings {
+		if finding.Line != 3 {
+			t.Fatalf("wanted 3, got %d", finding.Line)
+		}
+	}
+}
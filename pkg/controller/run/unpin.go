@@ -0,0 +1,112 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+)
+
+type ParamUnpin struct {
+	WorkflowFilePaths []string
+	ConfigFilePath    string
+	PWD               string
+	Now               time.Time
+	Stderr            io.Writer
+	// Major unpins actions to major versions such as v4 instead of version annotations such as v4.2.1.
+	Major bool
+}
+
+// Unpin converts actions pinned to commit hashes back to versions of their annotations without calling GitHub API.
+// This is the inverse of pinning.
+// e.g. @<commit hash> # v4.2.1 => @v4.2.1
+func (c *Controller) Unpin(logE *logrus.Entry, param *ParamUnpin) error {
+	cfg := &Config{}
+	if err := c.readConfig(logE, param.ConfigFilePath, param.PWD, cfg); err != nil {
+		return err
+	}
+	if err := cfg.Init(); err != nil {
+		return fmt.Errorf("initialize the configuration: %w", err)
+	}
+	cfg.Now = param.Now
+	workflowFilePaths, err := c.searchFiles(logE, param.WorkflowFilePaths, cfg, param.PWD)
+	if err != nil {
+		return fmt.Errorf("search target files: %w", err)
+	}
+	stats := &Stats{
+		Time:  param.Now,
+		Files: len(workflowFilePaths),
+	}
+	defer printSummary(param.Stderr, stats)
+	for _, workflowFilePath := range workflowFilePaths {
+		logE := logE.WithField("workflow_file", workflowFilePath)
+		if !filepath.IsAbs(workflowFilePath) {
+			workflowFilePath = filepath.Join(param.PWD, workflowFilePath)
+		}
+		if err := c.unpinWorkflow(logE, workflowFilePath, cfg, param.Major, stats); err != nil {
+			logerr.WithError(logE, err).Warn("unpin actions of a workflow")
+			stats.Errors++
+		}
+	}
+	return nil
+}
+
+func (c *Controller) unpinWorkflow(logE *logrus.Entry, workflowFilePath string, cfg *Config, major bool, stats *Stats) error {
+	lines, err := c.readWorkflow(workflowFilePath)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i, line := range lines {
+		l := c.unpinLine(logE.WithField("line_number", i+1), line, cfg, major)
+		stats.addLine(line, l)
+		if l != line {
+			changed = true
+			lines[i] = l
+		}
+	}
+	if !changed {
+		return nil
+	}
+	stats.ChangedFiles++
+	return writeWorkflow(&workflowChange{
+		Path:  workflowFilePath,
+		Lines: lines,
+	})
+}
+
+// unpinLine replaces the commit hash of a line with the version annotation and removes the annotation.
+// Markers appended to the annotation such as " (immutable)" and the provenance are also removed.
+// If the action isn't pinned to a commit hash or the annotation is missing, the line isn't changed.
+func (c *Controller) unpinLine(logE *logrus.Entry, line string, cfg *Config, major bool) string {
+	action := c.getTargetAction(logE, line, cfg)
+	if action == nil || getVersionType(action.Version) != FullCommitSHA {
+		return line
+	}
+	if branch := getPinnedBranch(action); branch != "" {
+		// @<commit hash> # main (2024-06-01) => @main
+		suffix := action.Suffix[len(branchAnnotationPattern.FindString(action.Suffix)):]
+		return action.Uses + action.Quote + action.Name + "@" + branch + action.Quote + suffix
+	}
+	if action.Tag == "" {
+		logE.WithField("action", action.Name).Warn("the action can't be unpinned because the version annotation is missing")
+		return line
+	}
+	version := action.Tag
+	if major {
+		version = unpinMajorVersion(version)
+	}
+	suffix := action.Suffix[len(provenancePattern.FindString(action.Suffix)):]
+	return action.Uses + action.Quote + action.Name + "@" + version + action.Quote + suffix
+}
+
+// unpinMajorVersion returns the major version of the version keeping the prefix such as v.
+// e.g. v4.2.1 => v4
+func unpinMajorVersion(version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	return major
+}
@@ -0,0 +1,65 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_unpinLine(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name  string
+		line  string
+		major bool
+		exp   string
+	}{
+		{
+			name: "normal",
+			line: "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+			exp:  "  - uses: actions/checkout@v4.2.2",
+		},
+		{
+			name:  "major",
+			line:  "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2",
+			major: true,
+			exp:   "  - uses: actions/checkout@v4",
+		},
+		{
+			name: "quote and suffix",
+			line: `  - uses: "actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683" # v4.2.2 (immutable) - pinned for reasons`,
+			exp:  `  - uses: "actions/checkout@v4.2.2" - pinned for reasons`,
+		},
+		{
+			name: "branch",
+			line: "  - uses: suzuki-shunsuke/foo@ee0669bd1cc54295c223e0bb666b733df41de1c5 # main (2024-06-01) # comment",
+			exp:  "  - uses: suzuki-shunsuke/foo@main # comment",
+		},
+		{
+			name: "no annotation",
+			line: "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683",
+			exp:  "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683",
+		},
+		{
+			name: "not pinned",
+			line: "  - uses: actions/checkout@v4",
+			exp:  "  - uses: actions/checkout@v4",
+		},
+		{
+			name: "ignored",
+			line: "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2 # pinact:ignore",
+			exp:  "  - uses: actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683 # v4.2.2 # pinact:ignore",
+		},
+	}
+	logE := logrus.NewEntry(logrus.New())
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if l := ctrl.unpinLine(logE, d.line, &Config{}, d.major); l != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, l)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package run
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// useRule records that the rule matched something.
+func (c *Config) useRule(rule string) {
+	if c.usedRules == nil {
+		c.usedRules = map[string]struct{}{}
+	}
+	c.usedRules[rule] = struct{}{}
+}
+
+// unusedRule is a configuration rule which never matched anything in a run.
+type unusedRule struct {
+	Rule  string
+	Value string
+}
+
+// getUnusedRules returns rules which never matched anything.
+// freeze_windows and cooldowns are checked only if update is true because they're evaluated only when actions are updated.
+func (c *Config) getUnusedRules(update bool) []*unusedRule {
+	rules := []*unusedRule{}
+	add := func(rule, value string) {
+		if _, ok := c.usedRules[rule]; !ok {
+			rules = append(rules, &unusedRule{
+				Rule:  rule,
+				Value: value,
+			})
+		}
+	}
+	for i, ignoreAction := range c.IgnoreActions {
+		add(fmt.Sprintf("ignore_actions[%d]", i), ignoreAction.Name)
+	}
+	for i, ignoreNotFound := range c.IgnoreNotFound {
+		add(fmt.Sprintf("ignore_not_found[%d]", i), ignoreNotFound.Owner)
+	}
+	if update {
+		for i, w := range c.FreezeWindows {
+			add(fmt.Sprintf("freeze_windows[%d]", i), w.Name)
+		}
+		for i, cd := range c.Cooldowns {
+			add(fmt.Sprintf("cooldowns[%d]", i), cd.Name)
+		}
+	}
+	for i, owner := range c.ExcludeOwners {
+		add(fmt.Sprintf("exclude_owners[%d]", i), owner)
+	}
+	for i, exclude := range c.Excludes {
+		add(fmt.Sprintf("excludes[%d]", i), exclude)
+	}
+	return rules
+}
+
+// reportUnusedRules outputs warnings of rules which never matched anything.
+// This helps users to prune stale rules of large shared configuration files.
+func (c *Config) reportUnusedRules(logE *logrus.Entry, update bool) {
+	for _, rule := range c.getUnusedRules(update) {
+		logE.WithFields(logrus.Fields{
+			"rule":       rule.Rule,
+			"rule_value": rule.Value,
+		}).Warn("the rule never matched anything. The rule may be stale")
+	}
+}
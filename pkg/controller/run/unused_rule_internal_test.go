@@ -0,0 +1,37 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestConfig_getUnusedRules(t *testing.T) {
+	t.Parallel()
+	cfg := &Config{
+		IgnoreActions: []*IgnoreAction{
+			{Name: "actions/checkout"},
+			{Name: "actions/stale"},
+		},
+		FreezeWindows: []*FreezeWindow{
+			{Name: "^actions/", Cron: "0 0 * * 5"},
+		},
+		ExcludeOwners: []string{"suzuki-shunsuke"},
+	}
+	if err := cfg.Init(); err != nil {
+		t.Fatal(err)
+	}
+	logE := logrus.NewEntry(logrus.New())
+	if action := (&Controller{}).getTargetAction(logE, "      - uses: actions/checkout@v4", cfg); action != nil {
+		t.Fatal("the action must be ignored")
+	}
+	cfg.matchFreezeWindow("actions/setup-go")
+	exp := []*unusedRule{
+		{Rule: "ignore_actions[1]", Value: "actions/stale"},
+		{Rule: "exclude_owners[0]", Value: "suzuki-shunsuke"},
+	}
+	if diff := cmp.Diff(exp, cfg.getUnusedRules(true)); diff != "" {
+		t.Fatal(diff)
+	}
+}
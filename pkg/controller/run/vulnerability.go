@@ -0,0 +1,100 @@
+package run
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/osv"
+)
+
+type VulnerabilityService interface {
+	Query(ctx context.Context, action, version string) ([]*osv.Vulnerability, error)
+}
+
+type queryVulnerabilitiesResult struct {
+	vulns []*osv.Vulnerability
+	err   error
+}
+
+// queryVulnerabilities queries vulnerabilities of the action version.
+// Results are cached because the same action version is often used in many workflows.
+func (c *Controller) queryVulnerabilities(ctx context.Context, action, version string) ([]*osv.Vulnerability, error) {
+	key := action + "@" + version
+	if a, ok := c.vulnerabilities[key]; ok {
+		return a.vulns, a.err
+	}
+	vulns, err := c.vulnerabilityService.Query(ctx, action, version)
+	c.vulnerabilities[key] = &queryVulnerabilitiesResult{
+		vulns: vulns,
+		err:   err,
+	}
+	return vulns, err //nolint:wrapcheck
+}
+
+// checkVulnerabilities outputs findings of known vulnerabilities of actions by OSV.dev.
+// Versions are taken from version annotations, so actions without semver annotations aren't checked.
+func (c *Controller) checkVulnerabilities(ctx context.Context, logE *logrus.Entry, workflowFilePath string, lines []string) {
+	for i, line := range lines {
+		action := parseAction(line)
+		if action == nil {
+			continue
+		}
+		tag := action.Tag
+		if tag == "" {
+			tag = action.Version
+		}
+		if getVersionType(tag) != Semver {
+			continue
+		}
+		logE := logE.WithField("action", action.Name)
+		vulns, err := c.queryVulnerabilities(ctx, action.Name, strings.TrimPrefix(tag, "v"))
+		if err != nil {
+			logerr.WithError(logE, err).Warn("query vulnerabilities")
+			continue
+		}
+		for _, vuln := range vulns {
+			logE.WithFields(logrus.Fields{
+				"version":          tag,
+				"line_number":      i + 1,
+				"finding":          "vulnerable-action",
+				"vulnerability_id": vuln.ID,
+				"aliases":          strings.Join(vuln.Aliases, ", "),
+				"advisory":         "https://osv.dev/vulnerability/" + vuln.ID,
+			}).Warn("the action has a known vulnerability. " + vuln.Summary)
+			c.reporter.OnFinding(&Finding{
+				Kind:    FindingKindVulnerability,
+				File:    workflowFilePath,
+				Line:    i + 1,
+				Before:  line,
+				After:   line,
+				Message: vuln.ID + ": " + vuln.Summary,
+			})
+		}
+	}
+}
+
+// isVulnerable returns true if the current version of the action is compromised or has known vulnerabilities.
+// It's used by --security-only to update only such actions.
+// Versions are taken from version annotations, so actions without semver annotations are regarded as not vulnerable unless they're compromised.
+func (c *Controller) isVulnerable(ctx context.Context, logE *logrus.Entry, cfg *Config, action *Action) bool {
+	for _, advisory := range cfg.advisories {
+		if advisory.match(action) {
+			return true
+		}
+	}
+	tag := action.Tag
+	if tag == "" {
+		tag = action.Version
+	}
+	if getVersionType(tag) != Semver {
+		return false
+	}
+	vulns, err := c.queryVulnerabilities(ctx, action.Name, strings.TrimPrefix(tag, "v"))
+	if err != nil {
+		logerr.WithError(logE, err).Warn("query vulnerabilities")
+		return false
+	}
+	return len(vulns) != 0
+}
@@ -0,0 +1,96 @@
+package run
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/pinact/pkg/osv"
+)
+
+type testVulnerabilityService struct {
+	queries []string
+}
+
+func (s *testVulnerabilityService) Query(_ context.Context, action, version string) ([]*osv.Vulnerability, error) {
+	s.queries = append(s.queries, action+"@"+version)
+	if action == "tj-actions/changed-files" && version == "45.0.7" {
+		return []*osv.Vulnerability{
+			{
+				ID:      "GHSA-mrrh-fwg8-r2c3",
+				Summary: "tj-actions changed-files through 45.0.7 allows remote attackers to discover secrets by reading actions logs",
+			},
+		}, nil
+	}
+	return nil, nil
+}
+
+func TestController_checkVulnerabilities(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"      - uses: tj-actions/changed-files@0e58ed8671d6b60d0890c21b07f8835ace038e67 # v45.0.7",
+		"      - uses: tj-actions/changed-files@0e58ed8671d6b60d0890c21b07f8835ace038e67 # v45.0.7",
+		"      - uses: actions/checkout@v4.2.2",
+		"      - uses: actions/setup-go@v5",
+		"      - uses: actions/cache@0c45773b623bea8c8e75f6c82b208c3cf94ea4f9 # v4",
+	}
+	svc := &testVulnerabilityService{}
+	ctrl := NewController(nil, afero.NewMemMapFs())
+	ctrl.vulnerabilityService = svc
+	reporter := &testReporter{}
+	ctrl.SetReporter(reporter)
+	ctrl.checkVulnerabilities(context.Background(), logrus.NewEntry(logrus.New()), "test.yaml", lines)
+	if diff := cmp.Diff([]string{"tj-actions/changed-files@45.0.7", "actions/checkout@4.2.2"}, svc.queries); diff != "" {
+		t.Fatal(diff)
+	}
+	if len(reporter.findings) != 2 {
+		t.Fatalf("wanted 2 findings, got %d", len(reporter.findings))
+	}
+}
+
+func TestController_isVulnerable(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		action *Action
+		exp    bool
+	}{
+		{
+			name:   "vulnerable",
+			action: &Action{Name: "tj-actions/changed-files", Version: "0e58ed8671d6b60d0890c21b07f8835ace038e67", Tag: "v45.0.7"},
+			exp:    true,
+		},
+		{
+			name:   "not vulnerable",
+			action: &Action{Name: "tj-actions/changed-files", Version: "v46.0.1"},
+		},
+		{
+			name:   "compromised",
+			action: &Action{Name: "foo/bar", Version: "v1"},
+			exp:    true,
+		},
+		{
+			name:   "not semver",
+			action: &Action{Name: "actions/setup-go", Version: "v5"},
+		},
+	}
+	ctx := context.Background()
+	logE := logrus.NewEntry(logrus.New())
+	cfg := &Config{
+		advisories: []*Advisory{
+			{Action: "foo/bar", Tags: []string{"v1"}},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(nil, afero.NewMemMapFs())
+			ctrl.vulnerabilityService = &testVulnerabilityService{}
+			if f := ctrl.isVulnerable(ctx, logE, cfg, d.action); f != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, f)
+			}
+		})
+	}
+}
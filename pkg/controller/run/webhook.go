@@ -0,0 +1,151 @@
+package run
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/github"
+)
+
+const (
+	// reviewTimeout is the timeout of reviewing a pull request.
+	reviewTimeout = 5 * time.Minute
+	// defaultMaxConcurrentReviews is the default number of pull requests reviewed concurrently.
+	defaultMaxConcurrentReviews = 4
+)
+
+// WebhookHandler receives GitHub webhooks and reviews pull requests.
+// It's stateless. A controller is created per event so that API responses aren't cached between events.
+type WebhookHandler struct {
+	input    *InputNew
+	secret   []byte
+	isVerify bool
+	checkRun bool
+	logE     *logrus.Entry
+	// reviews is a semaphore limiting the number of concurrent reviews.
+	reviews chan struct{}
+}
+
+type InputNewWebhookHandler struct {
+	InputNew *InputNew
+	// Secret is a webhook secret. If this is empty, signatures aren't validated.
+	Secret   string
+	IsVerify bool
+	CheckRun bool
+	// MaxConcurrentReviews is the number of pull requests reviewed concurrently.
+	// If it's zero, defaultMaxConcurrentReviews is used.
+	MaxConcurrentReviews int
+}
+
+func NewWebhookHandler(logE *logrus.Entry, input *InputNewWebhookHandler) *WebhookHandler {
+	maxReviews := input.MaxConcurrentReviews
+	if maxReviews <= 0 {
+		maxReviews = defaultMaxConcurrentReviews
+	}
+	return &WebhookHandler{
+		input:    input.InputNew,
+		secret:   []byte(input.Secret),
+		isVerify: input.IsVerify,
+		checkRun: input.CheckRun,
+		logE:     logE,
+		reviews:  make(chan struct{}, maxReviews),
+	}
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := github.ValidatePayload(r, h.secret)
+	if err != nil {
+		logerr.WithError(h.logE, err).Warn("validate a webhook payload")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	eventType := github.WebHookType(r)
+	logE := h.logE.WithField("event_type", eventType)
+	if eventType != "pull_request" {
+		logE.Debug("ignore the event")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		logerr.WithError(logE, err).Warn("parse a webhook payload")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	param := getParamReview(event.(*github.PullRequestEvent)) //nolint:forcetypeassert
+	if param == nil {
+		logE.Debug("ignore the event")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	param.IsVerify = h.isVerify
+	param.CheckRun = h.checkRun
+	param.Now = time.Now()
+	logE = logE.WithFields(logrus.Fields{
+		"repository":   param.Owner + "/" + param.Repo,
+		"pull_request": param.Number,
+	})
+	// If too many pull requests are being reviewed, the event is rejected so that the server isn't overloaded.
+	// The delivery can be redelivered from the webhook settings.
+	select {
+	case h.reviews <- struct{}{}:
+	default:
+		logE.Warn("reject the event because too many pull requests are being reviewed")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	// GitHub times out webhook deliveries in 10 seconds, so pull requests are reviewed asynchronously.
+	go h.review(logE, param)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *WebhookHandler) review(logE *logrus.Entry, param *ParamReview) {
+	defer func() {
+		<-h.reviews
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), reviewTimeout)
+	defer cancel()
+	ctrl, err := New(ctx, h.input)
+	if err != nil {
+		logerr.WithError(logE, err).Error("create a controller")
+		return
+	}
+	if err := ctrl.ReviewPullRequest(ctx, logE, param); err != nil {
+		logerr.WithError(logE, err).Error("review a pull request")
+	}
+}
+
+// getParamReview returns parameters to review a pull request.
+// If the event doesn't need a review, it returns nil.
+func getParamReview(event *github.PullRequestEvent) *ParamReview {
+	switch event.GetAction() {
+	case "opened", "synchronize", "reopened":
+	default:
+		return nil
+	}
+	pr := event.GetPullRequest()
+	if pr.GetState() != "open" {
+		return nil
+	}
+	head := pr.GetHead()
+	headOwner, headRepo, ok := strings.Cut(head.GetRepo().GetFullName(), "/")
+	if !ok {
+		return nil
+	}
+	return &ParamReview{
+		Owner:     event.GetRepo().GetOwner().GetLogin(),
+		Repo:      event.GetRepo().GetName(),
+		Number:    pr.GetNumber(),
+		HeadOwner: headOwner,
+		HeadRepo:  headRepo,
+		HeadSHA:   head.GetSHA(),
+	}
+}
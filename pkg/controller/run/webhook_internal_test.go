@@ -0,0 +1,48 @@
+package run
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestWebhookHandler_ServeHTTP_maxConcurrentReviews(t *testing.T) {
+	t.Parallel()
+	h := NewWebhookHandler(logrus.NewEntry(logrus.New()), &InputNewWebhookHandler{
+		InputNew:             &InputNew{},
+		MaxConcurrentReviews: 1,
+	})
+	// A review is running.
+	h.reviews <- struct{}{}
+	payload := `{
+  "action": "opened",
+  "number": 1,
+  "pull_request": {
+    "number": 1,
+    "state": "open",
+    "head": {"sha": "ee0669bd1cc54295c223e0bb666b733df41de1c5", "repo": {"full_name": "suzuki-shunsuke/pinact"}}
+  },
+  "repository": {"name": "pinact", "owner": {"login": "suzuki-shunsuke"}}
+}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("wanted %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+func TestNewWebhookHandler(t *testing.T) {
+	t.Parallel()
+	h := NewWebhookHandler(logrus.NewEntry(logrus.New()), &InputNewWebhookHandler{
+		InputNew: &InputNew{},
+	})
+	if cap(h.reviews) != defaultMaxConcurrentReviews {
+		t.Fatalf("wanted %d, got %d", defaultMaxConcurrentReviews, cap(h.reviews))
+	}
+}
@@ -0,0 +1,104 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is a list of repositories processed by `pinact workspace run`.
+type Manifest struct {
+	Repositories []*ManifestRepository `json:"repositories"`
+}
+
+type ManifestRepository struct {
+	// Path is a local path to the repository. A relative path is relative to the manifest file.
+	Path string `json:"path"`
+	// Config is a configuration file path relative to the repository. If it's empty, the configuration file is searched in the repository.
+	Config string `json:"config,omitempty"`
+}
+
+type ParamRunWorkspace struct {
+	ManifestFilePath       string
+	PWD                    string
+	IsVerify               bool
+	CheckDuplicateVersions bool
+	Now                    time.Time
+	AssumeYes              bool
+	Stdin                  io.Reader
+	Stderr                 io.Writer
+	TokenFromStdin         bool
+}
+
+// RunWorkspace processes repositories defined in a manifest.
+// Repositories are processed with the same controller, so API responses are cached and shared among repositories.
+// Even if some repositories fail, the remaining repositories are processed and the errors are returned together.
+func (c *Controller) RunWorkspace(ctx context.Context, logE *logrus.Entry, param *ParamRunWorkspace) error {
+	manifestFilePath := param.ManifestFilePath
+	if !filepath.IsAbs(manifestFilePath) {
+		manifestFilePath = filepath.Join(param.PWD, manifestFilePath)
+	}
+	manifest := &Manifest{}
+	if err := c.readManifest(manifestFilePath, manifest); err != nil {
+		return err
+	}
+	manifestDir := filepath.Dir(manifestFilePath)
+	total := &Stats{
+		Time: param.Now,
+	}
+	if param.Stderr != nil {
+		defer func() {
+			fmt.Fprintf(param.Stderr, "pinact: total of %s: %s\n", plural(len(manifest.Repositories), "repository", "repositories"), total.summary())
+		}()
+	}
+	var errs []error
+	for _, repo := range manifest.Repositories {
+		if repo.Path == "" {
+			return fmt.Errorf("repositories[].path is required: %s", param.ManifestFilePath)
+		}
+		pwd := repo.Path
+		if !filepath.IsAbs(pwd) {
+			pwd = filepath.Join(manifestDir, pwd)
+		}
+		configFilePath := repo.Config
+		if configFilePath != "" && !filepath.IsAbs(configFilePath) {
+			configFilePath = filepath.Join(pwd, configFilePath)
+		}
+		logE := logE.WithField("repository", repo.Path)
+		if err := c.Run(ctx, logE, &ParamRun{
+			ConfigFilePath:         configFilePath,
+			PWD:                    pwd,
+			IsVerify:               param.IsVerify,
+			CheckDuplicateVersions: param.CheckDuplicateVersions,
+			Now:                    param.Now,
+			AssumeYes:              param.AssumeYes,
+			Stdin:                  param.Stdin,
+			Stderr:                 param.Stderr,
+			TokenFromStdin:         param.TokenFromStdin,
+			workspaceStats:         total,
+		}); err != nil {
+			logerr.WithError(logE, err).Error("process a repository")
+			errs = append(errs, fmt.Errorf("process a repository %s: %w", repo.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Controller) readManifest(manifestFilePath string, manifest *Manifest) error {
+	f, err := c.fs.Open(manifestFilePath)
+	if err != nil {
+		return fmt.Errorf("open a manifest file: %w", err)
+	}
+	defer f.Close()
+	if err := yaml.NewDecoder(f).Decode(manifest); err != nil {
+		return fmt.Errorf("decode a manifest file as YAML: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package run
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+func TestController_RunWorkspace(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		"/workspace/manifest.yaml": `repositories:
+  - path: foo
+    config: not-found.yaml
+  - path: bar
+  - path: baz
+    config: not-found.yaml
+`,
+		"/workspace/bar/.github/workflows/test.yaml": `name: test
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`,
+	}
+	for path, content := range files {
+		if err := afero.WriteFile(fs, path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctrl := NewController(&RepositoriesServiceImpl{}, fs)
+	err := ctrl.RunWorkspace(context.Background(), logrus.NewEntry(logrus.New()), &ParamRunWorkspace{
+		ManifestFilePath: "manifest.yaml",
+		PWD:              "/workspace",
+	})
+	if err == nil {
+		t.Fatal("error must be returned if a repository fails")
+	}
+	if !strings.Contains(err.Error(), "process a repository foo") {
+		t.Fatalf("error should include the failed repository: %v", err)
+	}
+	if !strings.Contains(err.Error(), "process a repository baz") {
+		t.Fatalf("remaining repositories should be processed after a failure: %v", err)
+	}
+	if strings.Contains(err.Error(), "process a repository bar") {
+		t.Fatalf("error shouldn't include the succeeded repository: %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package run
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// FormatCheckstyle outputs findings in Checkstyle XML instead of modifying files.
+	FormatCheckstyle = "checkstyle"
+	// FormatJUnit outputs findings in JUnit XML instead of modifying files.
+	FormatJUnit = "junit"
+)
+
+type checkstyleResult struct {
+	XMLName xml.Name `xml:"checkstyle"`
+	Version string   `xml:"version,attr"`
+	// Generator is a comment of the version of pinact. Checkstyle XML doesn't have an attribute of the tool version.
+	Generator xml.Comment       `xml:",comment"`
+	Files     []*checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string             `xml:"name,attr"`
+	Errors []*checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// xmlReporter collects processed files and findings and outputs them in XML formats for CI systems such as Jenkins.
+// Files are recorded so that files without findings are reported as passed tests of JUnit.
+type xmlReporter struct {
+	*findingCollector
+	format  string
+	pwd     string
+	version string
+	files   []string
+}
+
+func newXMLReporter(next Reporter, format, pwd, version string) *xmlReporter {
+	return &xmlReporter{
+		findingCollector: newFindingCollector(next),
+		format:           format,
+		pwd:              pwd,
+		version:          version,
+	}
+}
+
+func (r *xmlReporter) OnFileStart(path string) {
+	r.findingCollector.OnFileStart(path)
+	r.files = append(r.files, path)
+}
+
+func newCheckstyleResult(pwd, version string, findings []*Finding) *checkstyleResult {
+	result := &checkstyleResult{
+		Version: "4.3",
+	}
+	if version != "" {
+		result.Generator = xml.Comment(" pinact " + version + " ")
+	}
+	files := map[string]*checkstyleFile{}
+	for _, finding := range findings {
+		path := getRelPath(pwd, finding.File)
+		file, ok := files[path]
+		if !ok {
+			file = &checkstyleFile{
+				Name: path,
+			}
+			files[path] = file
+			result.Files = append(result.Files, file)
+		}
+		severity := "warning"
+		if getAnnotationLevel(finding) == "failure" {
+			severity = "error"
+		}
+		file.Errors = append(file.Errors, &checkstyleError{
+			Line:     finding.Line,
+			Severity: severity,
+			Message:  getFindingMessage(finding),
+			Source:   "pinact." + finding.Kind,
+		})
+	}
+	return result
+}
+
+// newJUnitResult converts files and findings to JUnit XML.
+// Each file is a test case, which fails if the file has findings.
+func newJUnitResult(pwd string, files []string, findings []*Finding) *junitTestSuites {
+	suite := &junitTestSuite{
+		Name: "pinact",
+	}
+	cases := map[string]*junitTestCase{}
+	addCase := func(path string) *junitTestCase {
+		if tc, ok := cases[path]; ok {
+			return tc
+		}
+		tc := &junitTestCase{
+			Name:      path,
+			ClassName: "pinact",
+		}
+		cases[path] = tc
+		suite.TestCases = append(suite.TestCases, tc)
+		return tc
+	}
+	for _, file := range files {
+		addCase(getRelPath(pwd, file))
+	}
+	for _, finding := range findings {
+		path := getRelPath(pwd, finding.File)
+		tc := addCase(path)
+		if tc.Failure == nil {
+			tc.Failure = &junitFailure{
+				Type: finding.Kind,
+			}
+			suite.Failures++
+		}
+		msg := getFindingMessage(finding)
+		if tc.Failure.Message == "" {
+			tc.Failure.Message = msg
+		}
+		tc.Failure.Text += fmt.Sprintf("%s:%d: [%s] %s\n", path, finding.Line, finding.Kind, msg)
+	}
+	for _, tc := range suite.TestCases {
+		if tc.Failure != nil {
+			tc.Failure.Text = strings.TrimSuffix(tc.Failure.Text, "\n")
+		}
+	}
+	suite.Tests = len(suite.TestCases)
+	return &junitTestSuites{
+		Name:     "pinact",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []*junitTestSuite{suite},
+	}
+}
+
+func (r *xmlReporter) write(w io.Writer) error {
+	var v any
+	if r.format == FormatJUnit {
+		v = newJUnitResult(r.pwd, r.files, r.findings)
+	} else {
+		v = newCheckstyleResult(r.pwd, r.version, r.findings)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("output a XML header: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("output a report as XML: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("output a report as XML: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package run
+
+import (
+	"bytes"
+	"testing"
+)
+
+func newTestXMLReporter(format string) *xmlReporter {
+	reporter := newXMLReporter(&nopReporter{}, format, "/home/foo/repo", "v3.0.0")
+	reporter.OnFileStart("/home/foo/repo/.github/workflows/test.yaml")
+	reporter.OnFinding(&Finding{
+		Kind:    FindingKindCompromisedAction,
+		File:    "/home/foo/repo/.github/workflows/test.yaml",
+		Line:    10,
+		Message: "the action is <compromised>",
+	})
+	reporter.OnFileEnd("/home/foo/repo/.github/workflows/test.yaml", false)
+	reporter.OnFileStart("/home/foo/repo/.github/workflows/release.yaml")
+	reporter.OnFileEnd("/home/foo/repo/.github/workflows/release.yaml", false)
+	return reporter
+}
+
+func Test_xmlReporter_write(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		format string
+		exp    string
+	}{
+		{
+			name:   "checkstyle",
+			format: FormatCheckstyle,
+			exp: `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <!-- pinact v3.0.0 -->
+  <file name=".github/workflows/test.yaml">
+    <error line="10" severity="error" message="the action is &lt;compromised&gt;" source="pinact.compromised-action"></error>
+  </file>
+</checkstyle>
+`,
+		},
+		{
+			name:   "junit",
+			format: FormatJUnit,
+			exp: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="pinact" tests="2" failures="1">
+  <testsuite name="pinact" tests="2" failures="1">
+    <testcase name=".github/workflows/test.yaml" classname="pinact">
+      <failure message="the action is &lt;compromised&gt;" type="compromised-action">.github/workflows/test.yaml:10: [compromised-action] the action is &lt;compromised&gt;</failure>
+    </testcase>
+    <testcase name=".github/workflows/release.yaml" classname="pinact"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			buf := &bytes.Buffer{}
+			if err := newTestXMLReporter(d.format).write(buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, buf.String())
+			}
+		})
+	}
+}
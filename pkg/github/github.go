@@ -2,26 +2,107 @@ package github
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/google/go-github/v68/github"
+	"github.com/suzuki-shunsuke/pinact/pkg/offline"
+	"github.com/suzuki-shunsuke/pinact/pkg/trace"
 	"golang.org/x/oauth2"
 )
 
 type (
-	ListOptions       = github.ListOptions
-	Reference         = github.Reference
-	Response          = github.Response
-	RepositoryTag     = github.RepositoryTag
-	RepositoryRelease = github.RepositoryRelease
-	Client            = github.Client
-	GitObject         = github.GitObject
-	Commit            = github.Commit
+	ListOptions                 = github.ListOptions
+	Reference                   = github.Reference
+	Response                    = github.Response
+	RepositoryTag               = github.RepositoryTag
+	RepositoryRelease           = github.RepositoryRelease
+	Timestamp                   = github.Timestamp
+	Client                      = github.Client
+	GitObject                   = github.GitObject
+	Commit                      = github.Commit
+	ErrorResponse               = github.ErrorResponse
+	Repository                  = github.Repository
+	User                        = github.User
+	RepositoriesService         = github.RepositoriesService
+	GitService                  = github.GitService
+	ReferenceListOptions        = github.ReferenceListOptions
+	RepositoryContentGetOptions = github.RepositoryContentGetOptions
+	PullRequestsService         = github.PullRequestsService
+	CommitFile                  = github.CommitFile
+	PullRequestReview           = github.PullRequestReview
+	PullRequestReviewRequest    = github.PullRequestReviewRequest
+	DraftReviewComment          = github.DraftReviewComment
+	PullRequestEvent            = github.PullRequestEvent
+	ChecksService               = github.ChecksService
+	CheckRun                    = github.CheckRun
+	CheckRunOutput              = github.CheckRunOutput
+	CheckRunAnnotation          = github.CheckRunAnnotation
+	CreateCheckRunOptions       = github.CreateCheckRunOptions
+	UpdateCheckRunOptions       = github.UpdateCheckRunOptions
+	Tree                        = github.Tree
+	TreeEntry                   = github.TreeEntry
+	CreateCommitOptions         = github.CreateCommitOptions
+	PullRequest                 = github.PullRequest
+	PullRequestListOptions      = github.PullRequestListOptions
+	NewPullRequest              = github.NewPullRequest
+	AttestationsResponse        = github.AttestationsResponse
+	Attestation                 = github.Attestation
 )
 
-func New(ctx context.Context) *Client {
-	return github.NewClient(getHTTPClientForGitHub(ctx, getGitHubToken()))
+var (
+	ValidatePayload = github.ValidatePayload
+	WebHookType     = github.WebHookType
+	ParseWebHook    = github.ParseWebHook
+)
+
+type InputNew struct {
+	// RecordFilePath is a file path where interactions with GitHub API are recorded.
+	RecordFilePath string
+	// ReplayFilePath is a file path of recorded interactions. If this is set, GitHub API isn't called.
+	ReplayFilePath string
+	// APIURL is a base URL of GitHub API. It's used to access github.com via a proxy. If this is empty, https://api.github.com/ is used.
+	APIURL string
+	// Token is a GitHub access token. If this is empty, the environment variable GITHUB_TOKEN is used.
+	Token string
+}
+
+func New(ctx context.Context, input *InputNew) (*Client, error) {
+	token := input.Token
+	if token == "" {
+		token = getGitHubToken()
+	}
+	client := getHTTPClientForGitHub(ctx, token)
+	switch {
+	case input.ReplayFilePath != "":
+		replayer, err := NewReplayer(input.ReplayFilePath)
+		if err != nil {
+			return nil, err
+		}
+		client = &http.Client{Transport: replayer}
+	case offline.Enabled():
+		// Cached responses and recorded interactions are still available.
+		client = offline.HTTPClient(client)
+	case input.RecordFilePath != "":
+		base := client.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		client = &http.Client{Transport: NewRecorder(base, input.RecordFilePath)}
+	}
+	gh := github.NewClient(trace.HTTPClient(client))
+	if input.APIURL != "" {
+		u, err := url.Parse(strings.TrimSuffix(input.APIURL, "/") + "/")
+		if err != nil {
+			return nil, fmt.Errorf("parse the GitHub API URL: %w", err)
+		}
+		gh.BaseURL = u
+	}
+	return gh, nil
 }
 
 func getGitHubToken() string {
@@ -36,3 +117,13 @@ func getHTTPClientForGitHub(ctx context.Context, token string) *http.Client {
 		&oauth2.Token{AccessToken: token},
 	))
 }
+
+// IsNotFound returns true if the error is caused by 404 Not Found.
+// If a private repository is accessed without the permission, GitHub API also returns 404.
+func IsNotFound(err error) bool {
+	var e *github.ErrorResponse
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.Response != nil && e.Response.StatusCode == http.StatusNotFound
+}
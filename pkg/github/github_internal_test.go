@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNew_apiURL(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		apiURL string
+		exp    string
+	}{
+		{
+			name: "default",
+			exp:  "https://api.github.com/",
+		},
+		{
+			name:   "trailing slash is appended",
+			apiURL: "https://proxy.example.com/github",
+			exp:    "https://proxy.example.com/github/",
+		},
+		{
+			name:   "trailing slash is kept",
+			apiURL: "https://proxy.example.com/github/",
+			exp:    "https://proxy.example.com/github/",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			gh, err := New(context.Background(), &InputNew{
+				APIURL: d.apiURL,
+				Token:  "dummy",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := gh.BaseURL.String(); s != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, s)
+			}
+		})
+	}
+}
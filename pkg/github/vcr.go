@@ -0,0 +1,130 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Cassette is a set of recorded HTTP interactions with GitHub API.
+// It's used to reproduce bugs from user-supplied recordings and to test the controller against realistic API responses.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// recordedHeaders are response headers saved in cassettes.
+// Other headers are dropped to keep cassettes small and free from sensitive data.
+var recordedHeaders = []string{"Content-Type", "Link"} //nolint:gochecknoglobals
+
+// Recorder is a http.RoundTripper which records interactions to a cassette file.
+// Request headers such as Authorization aren't recorded.
+type Recorder struct {
+	base     http.RoundTripper
+	path     string
+	mutex    sync.Mutex
+	cassette *Cassette
+}
+
+func NewRecorder(base http.RoundTripper, path string) *Recorder {
+	return &Recorder{
+		base:     base,
+		path:     path,
+		cassette: &Cassette{},
+	}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read a response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	header := http.Header{}
+	for _, key := range recordedHeaders {
+		if v := resp.Header.Values(key); len(v) != 0 {
+			header[key] = v
+		}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     header,
+		Body:       string(body),
+	})
+	// Write the whole cassette every time so that interactions are saved even if pinact fails midway.
+	if err := writeCassette(r.path, r.cassette); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func writeCassette(path string, cassette *Cassette) error {
+	b, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode a cassette as JSON: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil { //nolint:mnd,gosec
+		return fmt.Errorf("write a cassette: %w", err)
+	}
+	return nil
+}
+
+// Replayer is a http.RoundTripper which returns recorded responses instead of calling GitHub API.
+// Interactions are matched by the HTTP method and URL.
+type Replayer struct {
+	interactions map[string]*Interaction
+}
+
+func NewReplayer(path string) (*Replayer, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read a cassette: %w", err)
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(b, cassette); err != nil {
+		return nil, fmt.Errorf("decode a cassette as JSON: %w", err)
+	}
+	interactions := make(map[string]*Interaction, len(cassette.Interactions))
+	for _, interaction := range cassette.Interactions {
+		interactions[interaction.Method+" "+interaction.URL] = interaction
+	}
+	return &Replayer{
+		interactions: interactions,
+	}, nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	interaction, ok := r.interactions[req.Method+" "+req.URL.String()]
+	if !ok {
+		return nil, errors.New("the request isn't recorded in the cassette: " + req.Method + " " + req.URL.String())
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+		StatusCode:    interaction.StatusCode,
+		Header:        interaction.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(interaction.Body))),
+		ContentLength: int64(len(interaction.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder_Replayer(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Link", `<https://api.github.com/repos/suzuki-shunsuke/pinact/tags?page=2>; rel="next"`)
+		w.Header().Set("X-Secret", "secret")
+		if _, err := w.Write([]byte(`[{"name":"v1.0.0"}]`)); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+
+	// Record
+	recorder := &http.Client{Transport: NewRecorder(http.DefaultTransport, cassettePath)}
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/repos/suzuki-shunsuke/pinact/tags", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := recorder.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `[{"name":"v1.0.0"}]` {
+		t.Fatalf("the response body must be kept: %s", body)
+	}
+
+	b, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "Bearer token") {
+		t.Fatal("request headers must not be recorded")
+	}
+
+	// Replay
+	replayer, err := NewReplayer(cassettePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: replayer}
+	resp, err = client.Get(server.URL + "/repos/suzuki-shunsuke/pinact/tags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("wanted %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if string(body) != `[{"name":"v1.0.0"}]` {
+		t.Fatalf("the recorded body must be replayed: %s", body)
+	}
+	if link := resp.Header.Get("Link"); !strings.Contains(link, `rel="next"`) {
+		t.Fatalf("the Link header must be replayed: %s", link)
+	}
+	if s := resp.Header.Get("X-Secret"); s != "" {
+		t.Fatalf("headers other than recordedHeaders must not be recorded: %s", s)
+	}
+
+	// Requests which aren't recorded fail.
+	if _, err := client.Get(server.URL + "/repos/suzuki-shunsuke/pinact/releases"); err == nil { //nolint:bodyclose
+		t.Fatal("the request isn't recorded, so it must fail")
+	}
+}
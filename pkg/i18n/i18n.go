@@ -0,0 +1,97 @@
+// Package i18n translates user-facing messages.
+// The language is selected by the environment variable PINACT_LANG or LANG.
+// Messages are written in English in the source code and used as keys of translations,
+// so untranslated messages are output in English.
+package i18n
+
+import (
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// catalogs are translations per language.
+var catalogs = map[string]map[string]string{ //nolint:gochecknoglobals
+	"ja": ja,
+}
+
+// Lang returns the language of messages such as ja.
+// PINACT_LANG takes precedence over LANG. e.g. ja_JP.UTF-8 => ja
+func Lang() string {
+	lang := os.Getenv("PINACT_LANG")
+	if lang == "" {
+		lang = os.Getenv("LANG")
+	}
+	lang, _, _ = strings.Cut(lang, ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	return strings.ToLower(lang)
+}
+
+type Translator struct {
+	messages map[string]string
+}
+
+// New returns a translator of the language.
+// If the language isn't supported, messages aren't translated.
+func New(lang string) *Translator {
+	return &Translator{
+		messages: catalogs[lang],
+	}
+}
+
+// Message translates a message.
+func (t *Translator) Message(msg string) string {
+	if s, ok := t.messages[msg]; ok {
+		return s
+	}
+	return msg
+}
+
+// Error translates an error message.
+// Error messages are chains of messages joined with ": " by wrapping errors, so each of them is translated.
+// e.g. "search target files: open a file: permission denied"
+func (t *Translator) Error(msg string) string {
+	if len(t.messages) == 0 {
+		return msg
+	}
+	arr := strings.Split(msg, ": ")
+	for i, s := range arr {
+		arr[i] = t.Message(s)
+	}
+	return strings.Join(arr, ": ")
+}
+
+// Hook is a logrus hook translating log messages and errors.
+type Hook struct {
+	translator *Translator
+}
+
+func NewHook(translator *Translator) *Hook {
+	return &Hook{
+		translator: translator,
+	}
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.translator.Message(entry.Message)
+	if err, ok := entry.Data[logrus.ErrorKey].(error); ok {
+		entry.Data[logrus.ErrorKey] = h.translator.Error(err.Error())
+	}
+	return nil
+}
+
+// SetUp translates logs of the standard logger by the language selected by environment variables.
+// It must be called before other hooks are added so that messages are translated before they're output.
+func SetUp() {
+	translator := New(Lang())
+	if len(translator.messages) == 0 {
+		return
+	}
+	logrus.AddHook(NewHook(translator))
+}
@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readSources returns Go source files of pinact except this package.
+func readSources(t *testing.T) string {
+	t.Helper()
+	var b strings.Builder
+	root := filepath.Join("..", "..")
+	if err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "i18n" || d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) != ".go" || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+		s, err := os.ReadFile(p)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+		b.Write(s)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func Test_ja(t *testing.T) {
+	t.Parallel()
+	// Keys of translations must be messages in the source code. Otherwise, translations are stale.
+	src := readSources(t)
+	// Errors of the standard library aren't in the source code.
+	stdlibErrors := map[string]struct{}{
+		"context canceled":          {},
+		"permission denied":         {},
+		"no such file or directory": {},
+	}
+	for key := range ja {
+		if _, ok := stdlibErrors[key]; ok {
+			continue
+		}
+		// Error messages are wrapped like fmt.Errorf("read a file: %w", err).
+		if !strings.Contains(src, `"`+key+`"`) && !strings.Contains(src, `"`+key+`: `) {
+			t.Errorf("the message isn't found in the source code: %q", key)
+		}
+	}
+}
+
+func TestTranslator_Error(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		lang string
+		msg  string
+		exp  string
+	}{
+		{
+			name: "chain",
+			lang: "ja",
+			msg:  "search target files: permission denied",
+			exp:  "対象ファイルの検索に失敗しました: 権限がありません",
+		},
+		{
+			name: "untranslated messages are kept",
+			lang: "ja",
+			msg:  "read a configuration file: open .pinact.yaml: no such file or directory",
+			exp:  "設定ファイルの読み込みに失敗しました: open .pinact.yaml: ファイルまたはディレクトリが存在しません",
+		},
+		{
+			name: "single message",
+			lang: "ja",
+			msg:  "context canceled",
+			exp:  "キャンセルされました",
+		},
+		{
+			name: "unsupported language",
+			lang: "fr",
+			msg:  "search target files: permission denied",
+			exp:  "search target files: permission denied",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if s := New(d.lang).Error(d.msg); s != d.exp {
+				t.Fatalf("wanted %q, got %q", d.exp, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package i18n
+
+// ja is Japanese translations.
+var ja = map[string]string{ //nolint:gochecknoglobals
+	// Logs
+	"pinact failed":            "pinact が失敗しました",
+	"parse a line":             "行の解析に失敗しました",
+	"update a workflow":        "ワークフローの更新に失敗しました",
+	"check a workflow":         "ワークフローのチェックに失敗しました",
+	"format a workflow":        "ワークフローのフォーマットに失敗しました",
+	"read a workflow":          "ワークフローの読み込みに失敗しました",
+	"record the progress":      "進捗の記録に失敗しました",
+	"resume the previous run":  "前回の実行を再開します",
+	"save a cache file":        "キャッシュファイルの保存に失敗しました",
+	"query vulnerabilities":    "脆弱性の問い合わせに失敗しました",
+	"verify attestations":      "アテステーションの検証に失敗しました",
+	"the log level is invalid": "ログレベルが不正です",
+	"log_color is invalid":     "log_color が不正です",
+	"the action isn't pinned":  "アクションがピン留めされていません",
+	"the action is referenced by a ref which is neither a version nor a commit hash":                     "アクションがバージョンでもコミットハッシュでもない ref で参照されています",
+	"the Docker image isn't pinned by a digest":                                                          "Docker イメージがダイジェストでピン留めされていません",
+	"the action isn't found. The action may be private and the access token may not have the permission": "アクションが見つかりません。アクションがプライベートで、アクセストークンに権限が無い可能性があります",
+	"the action's repository is archived. The action isn't updated":                                      "アクションのリポジトリがアーカイブされています。アクションは更新されません",
+	"the configuration has an unknown field. It's ignored":                                               "設定ファイルに未知のフィールドがあります。このフィールドは無視されます",
+	"the configuration version isn't supported. Known fields take effect":                                "設定ファイルのバージョンはサポートされていません。既知のフィールドのみ有効になります",
+	"the expiry date of pinact:ignore is invalid. The format must be YYYY-MM-DD":                         "pinact:ignore の有効期限が不正です。YYYY-MM-DD 形式で指定してください",
+	"pinact:ignore has expired":                                                                          "pinact:ignore の有効期限が切れています",
+	"the floating tag has moved":                                                                         "フローティングタグが移動しました",
+	"the pinned commit doesn't have SLSA provenance":                                                     "ピン留めされたコミットに SLSA provenance がありません",
+	"the pinned version is older than --max-pin-age":                                                     "ピン留めされたバージョンが --max-pin-age より古いです",
+	"the repository has too many tags. pinact gave up getting a long tag from the commit hash":           "リポジトリのタグが多すぎるため、コミットハッシュから詳細なタグを取得できませんでした",
+	"the reusable workflow uses an unpinned action":                                                      "再利用可能ワークフローがピン留めされていないアクションを使っています",
+	"the rule never matched anything. The rule may be stale":                                             "このルールはどれにもマッチしませんでした。不要なルールかもしれません",
+	"the run is canceled before modifying files":                                                         "ファイルを変更する前に実行がキャンセルされました",
+	"the run is canceled while modifying files":                                                          "ファイルの変更中に実行がキャンセルされました",
+	"the same action is used at multiple versions in a job":                                              "同じジョブで同じアクションが複数のバージョンで使われています",
+	"the same commit hash is annotated with different tags":                                              "同じコミットハッシュに異なるタグのコメントが付いています",
+	"the version annotation is missing, but no tag points to the commit hash":                            "バージョンのコメントがありませんが、コミットハッシュを指すタグがありません",
+	"the version annotation is missing. Please run pinact run to add it":                                 "バージョンのコメントがありません。pinact run を実行して追加してください",
+	"no update":                       "更新はありません",
+	"no suggestion":                   "提案はありません",
+	"a pull request would be created": "プルリクエストが作成されます",
+	// Errors
+	"actions aren't pinned. Please run pinact run to pin them": "アクションがピン留めされていません。pinact run を実行してピン留めしてください",
+	"compromised actions are used":                             "侵害されたアクションが使われています",
+	"checks failed":                                            "チェックに失敗しました",
+	"the run is aborted because too many files would be modified. Pass --assume-yes to modify them without confirmation": "変更されるファイルが多すぎるため実行を中止しました。確認なしで変更するには --assume-yes を指定してください",
+	"the run is canceled. You can continue the run by --resume":                                                          "実行がキャンセルされました。--resume で実行を再開できます",
+	"files are modified by pinact":  "pinact によってファイルが変更されました",
+	"search target files":           "対象ファイルの検索に失敗しました",
+	"read a configuration file":     "設定ファイルの読み込みに失敗しました",
+	"initialize the configuration":  "設定の初期化に失敗しました",
+	"verify the version annotation": "バージョンのコメントの検証に失敗しました",
+	"get the latest version":        "最新バージョンの取得に失敗しました",
+	"get a reference":               "リファレンスの取得に失敗しました",
+	"list tags":                     "タグの一覧取得に失敗しました",
+	"list releases":                 "リリースの一覧取得に失敗しました",
+	"create a GitHub client":        "GitHub クライアントの作成に失敗しました",
+	// Errors of the standard library
+	"context canceled":          "キャンセルされました",
+	"permission denied":         "権限がありません",
+	"no such file or directory": "ファイルまたはディレクトリが存在しません",
+}
@@ -0,0 +1,158 @@
+// Package keyring stores GitHub access tokens in the keyring of the OS.
+// Tokens are stored per GitHub API URL so that tokens of GitHub Enterprise Servers are stored besides the token of github.com.
+// It calls commands of the OS instead of libraries:
+// security on macOS and secret-tool of libsecret on Linux.
+package keyring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+const (
+	service = "pinact"
+	// account is the account of the token of github.com.
+	// Tokens of other hosts are stored in accounts with the suffix of their API URLs.
+	account = "github_token"
+	// defaultAPIURL is the API URL of github.com.
+	defaultAPIURL = "https://api.github.com/"
+	// exitCodeNotFound is the exit code of security when the item isn't found.
+	exitCodeNotFound = 44
+)
+
+var (
+	ErrNotFound    = errors.New("the token isn't found in the keyring")
+	ErrUnsupported = errors.New("the keyring isn't supported on this OS")
+	// tokenPattern restricts characters of tokens so that they're passed to commands safely.
+	tokenPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	// apiURLPattern restricts characters of API URLs for the same reason as tokenPattern.
+	apiURLPattern = regexp.MustCompile(`^[A-Za-z0-9_.~:/-]+$`)
+)
+
+// getAccount returns the account of the token of the GitHub API URL.
+// If the URL is empty or the URL of github.com, the account of github.com is returned for compatibility.
+// e.g. https://ghes.example.com/api/v3/ => github_token:https://ghes.example.com/api/v3
+func getAccount(apiURL string) (string, error) {
+	if apiURL == "" || strings.TrimSuffix(apiURL, "/") == strings.TrimSuffix(defaultAPIURL, "/") {
+		return account, nil
+	}
+	if !apiURLPattern.MatchString(apiURL) {
+		return "", errors.New("the GitHub API URL has invalid characters")
+	}
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return "", fmt.Errorf("parse the GitHub API URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", errors.New("the GitHub API URL must be an absolute URL")
+	}
+	return account + ":" + strings.TrimSuffix(apiURL, "/"), nil
+}
+
+// Get returns the token of the GitHub API URL stored in the keyring.
+// If the token isn't stored, it returns ErrNotFound.
+func Get(ctx context.Context, apiURL string) (string, error) {
+	account, err := getAccount(apiURL)
+	if err != nil {
+		return "", err
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux":
+		cmd = exec.CommandContext(ctx, "secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return "", ErrUnsupported
+	}
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	if err := cmd.Run(); err != nil {
+		if isNotFound(err) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("get a token from the keyring: %w", err)
+	}
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", ErrNotFound
+	}
+	return token, nil
+}
+
+// Set stores the token of the GitHub API URL in the keyring.
+// If a token is already stored, it's overwritten.
+// The token is passed by the standard input so that it doesn't appear in the process list.
+func Set(ctx context.Context, apiURL, token string) error {
+	if !tokenPattern.MatchString(token) {
+		return errors.New("the token has invalid characters")
+	}
+	account, err := getAccount(apiURL)
+	if err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// Commands are read from the standard input in the interactive mode.
+		cmd = exec.CommandContext(ctx, "security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", service, account, token))
+	case "linux":
+		cmd = exec.CommandContext(ctx, "secret-tool", "store", "--label=pinact GitHub access token", "service", service, "account", account)
+		cmd.Stdin = strings.NewReader(token)
+	default:
+		return ErrUnsupported
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("store a token in the keyring: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the token of the GitHub API URL from the keyring.
+// If the token isn't stored, it may return ErrNotFound depending on the OS.
+func Delete(ctx context.Context, apiURL string) error {
+	account, err := getAccount(apiURL)
+	if err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "security", "delete-generic-password", "-s", service, "-a", account)
+	case "linux":
+		cmd = exec.CommandContext(ctx, "secret-tool", "clear", "service", service, "account", account)
+	default:
+		return ErrUnsupported
+	}
+	if err := cmd.Run(); err != nil {
+		if isNotFound(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("remove a token from the keyring: %w", err)
+	}
+	return nil
+}
+
+// isNotFound returns true if the command failed because the item isn't found.
+// secret-tool lookup exits with 1 and outputs nothing.
+func isNotFound(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return exitErr.ExitCode() == exitCodeNotFound
+	case "linux":
+		return exitErr.ExitCode() == 1
+	}
+	return false
+}
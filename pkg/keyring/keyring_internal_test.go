@@ -0,0 +1,56 @@
+package keyring
+
+import "testing"
+
+func Test_getAccount(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name   string
+		apiURL string
+		exp    string
+		isErr  bool
+	}{
+		{
+			name: "default",
+			exp:  "github_token",
+		},
+		{
+			name:   "github.com",
+			apiURL: "https://api.github.com",
+			exp:    "github_token",
+		},
+		{
+			name:   "ghes",
+			apiURL: "https://ghes.example.com/api/v3/",
+			exp:    "github_token:https://ghes.example.com/api/v3",
+		},
+		{
+			name:   "invalid characters",
+			apiURL: "https://ghes.example.com/api/v3/ -w foo",
+			isErr:  true,
+		},
+		{
+			name:   "relative",
+			apiURL: "ghes.example.com/api/v3/",
+			isErr:  true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			account, err := getAccount(d.apiURL)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if account != d.exp {
+				t.Fatalf("wanted %s, got %s", d.exp, account)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package log
 
 import (
+	"fmt"
+	"io"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -23,6 +26,65 @@ func SetLevel(level string, logE *logrus.Entry) {
 	logrus.SetLevel(lvl)
 }
 
+// SetQuiet suppresses informational logs while keeping warnings and errors.
+func SetQuiet() {
+	if logrus.GetLevel() > logrus.WarnLevel {
+		logrus.SetLevel(logrus.WarnLevel)
+	}
+}
+
+// AddFileOutput outputs structured logs as JSON to w at the given level.
+// The level is independent of the level of the standard error output,
+// so detailed logs can be kept in a file without flooding CI logs.
+func AddFileOutput(w io.Writer, level string) error {
+	lvl := logrus.InfoLevel
+	if level != "" {
+		l, err := logrus.ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("parse the log level of the log file: %w", err)
+		}
+		lvl = l
+	}
+	logger := logrus.StandardLogger()
+	// The logger level must be the most verbose one of all outputs, so the standard error output is filtered by a hook.
+	logger.AddHook(&writerHook{
+		writer:    logger.Out,
+		formatter: logger.Formatter,
+		levels:    logrus.AllLevels[:logger.GetLevel()+1],
+	})
+	logger.AddHook(&writerHook{
+		writer:    w,
+		formatter: &logrus.JSONFormatter{},
+		levels:    logrus.AllLevels[:lvl+1],
+	})
+	logger.SetOutput(io.Discard)
+	if lvl > logger.GetLevel() {
+		logger.SetLevel(lvl)
+	}
+	return nil
+}
+
+type writerHook struct {
+	writer    io.Writer
+	formatter logrus.Formatter
+	levels    []logrus.Level
+}
+
+func (h *writerHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *writerHook) Fire(entry *logrus.Entry) error {
+	b, err := h.formatter.Format(entry)
+	if err != nil {
+		return fmt.Errorf("format a log: %w", err)
+	}
+	if _, err := h.writer.Write(b); err != nil {
+		return fmt.Errorf("write a log: %w", err)
+	}
+	return nil
+}
+
 func SetColor(color string, logE *logrus.Entry) {
 	switch color {
 	case "", "auto":
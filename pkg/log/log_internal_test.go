@@ -0,0 +1,55 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestAddFileOutput(t *testing.T) { //nolint:paralleltest
+	// The standard logger is restored because AddFileOutput changes it.
+	logger := logrus.StandardLogger()
+	level := logger.GetLevel()
+	out := logger.Out
+	formatter := logger.Formatter
+	hooks := logger.ReplaceHooks(logrus.LevelHooks{})
+	t.Cleanup(func() {
+		logger.SetLevel(level)
+		logger.SetOutput(out)
+		logger.SetFormatter(formatter)
+		logger.ReplaceHooks(hooks)
+	})
+
+	stderr := &bytes.Buffer{}
+	logger.SetOutput(stderr)
+	logger.SetFormatter(&logrus.TextFormatter{DisableColors: true, DisableTimestamp: true})
+	logger.SetLevel(logrus.WarnLevel)
+	file := &bytes.Buffer{}
+	if err := AddFileOutput(file, "debug"); err != nil {
+		t.Fatal(err)
+	}
+	logrus.Debug("resolve a version")
+	logrus.Warn("the action isn't pinned")
+
+	// The standard error output keeps its level.
+	if s := stderr.String(); strings.Contains(s, "resolve a version") || !strings.Contains(s, "the action isn't pinned") {
+		t.Fatalf("only warnings must be output to the standard error output: %s", s)
+	}
+	// The log file has its own level and the format is JSON.
+	lines := strings.Split(strings.TrimSpace(file.String()), "\n")
+	if len(lines) != 2 { //nolint:mnd
+		t.Fatalf("debug logs must be output to the log file: %s", file.String())
+	}
+	if !strings.Contains(lines[0], `"msg":"resolve a version"`) || !strings.Contains(lines[0], `"level":"debug"`) {
+		t.Fatalf("logs must be output as JSON: %s", lines[0])
+	}
+}
+
+func TestAddFileOutput_invalidLevel(t *testing.T) {
+	t.Parallel()
+	if err := AddFileOutput(&bytes.Buffer{}, "foo"); err == nil {
+		t.Fatal("an invalid log level must be rejected")
+	}
+}
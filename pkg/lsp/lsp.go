@@ -0,0 +1,310 @@
+// Package lsp implements a minimal Language Server Protocol server.
+// It publishes diagnostics of unpinned and outdated actions and provides code actions to fix them.
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/logrus-error/logerr"
+	"github.com/suzuki-shunsuke/pinact/pkg/controller/run"
+)
+
+const (
+	textDocumentSyncFull = 1
+	severityError        = 1
+	severityWarning      = 2
+	codeMethodNotFound   = -32601
+	codeInvalidParams    = -32602
+)
+
+// Linter checks the content of a file.
+type Linter interface {
+	Lint(ctx context.Context, logE *logrus.Entry, path, content string) []*run.Finding
+}
+
+type Server struct {
+	linter   Linter
+	reader   *bufio.Reader
+	writer   io.Writer
+	findings map[string][]*run.Finding
+}
+
+func New(linter Linter, r io.Reader, w io.Writer) *Server {
+	return &Server{
+		linter:   linter,
+		reader:   bufio.NewReader(r),
+		writer:   w,
+		findings: map[string][]*run.Finding{},
+	}
+}
+
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  any              `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve handles messages until the client sends the exit notification or closes the connection.
+func (s *Server) Serve(ctx context.Context, logE *logrus.Entry) error {
+	for {
+		msg, err := s.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		logE := logE.WithField("lsp_method", msg.Method)
+		result, err := s.handle(ctx, logE, msg)
+		if msg.ID == nil {
+			// Notifications don't have responses.
+			if err != nil {
+				logerr.WithError(logE, err).Warn("handle a notification")
+			}
+			continue
+		}
+		res := &message{
+			JSONRPC: "2.0",
+			ID:      msg.ID,
+			Result:  result,
+		}
+		if err != nil {
+			res.Result = nil
+			res.Error = toResponseError(err)
+		} else if result == nil {
+			res.Result = json.RawMessage("null")
+		}
+		if err := s.write(res); err != nil {
+			return err
+		}
+	}
+}
+
+type methodNotFoundError struct {
+	method string
+}
+
+func (e *methodNotFoundError) Error() string {
+	return "method not found: " + e.method
+}
+
+func toResponseError(err error) *responseError {
+	code := codeInvalidParams
+	var e *methodNotFoundError
+	if errors.As(err, &e) {
+		code = codeMethodNotFound
+	}
+	return &responseError{
+		Code:    code,
+		Message: err.Error(),
+	}
+}
+
+func (s *Server) read() (*message, error) {
+	header, err := textproto.NewReader(s.reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, fmt.Errorf("read a header: %w", err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("parse Content-Length: %w", err)
+	}
+	b := make([]byte, length)
+	if _, err := io.ReadFull(s.reader, b); err != nil {
+		return nil, fmt.Errorf("read a message: %w", err)
+	}
+	msg := &message{}
+	if err := json.Unmarshal(b, msg); err != nil {
+		return nil, fmt.Errorf("parse a message as JSON: %w", err)
+	}
+	return msg, nil
+}
+
+func (s *Server) write(msg *message) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encode a message as JSON: %w", err)
+	}
+	if _, err := fmt.Fprintf(s.writer, "Content-Length: %d\r\n\r\n%s", len(b), b); err != nil {
+		return fmt.Errorf("write a message: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) notify(method string, params any) error {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("encode params as JSON: %w", err)
+	}
+	return s.write(&message{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  b,
+	})
+}
+
+func (s *Server) handle(ctx context.Context, logE *logrus.Entry, msg *message) (any, error) {
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync":   textDocumentSyncFull,
+				"codeActionProvider": true,
+			},
+			"serverInfo": map[string]string{
+				"name": "pinact",
+			},
+		}, nil
+	case "initialized", "shutdown", "textDocument/didSave", "$/cancelRequest", "$/setTrace":
+		return nil, nil
+	case "textDocument/didOpen":
+		params := &didOpenParams{}
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, fmt.Errorf("parse params: %w", err)
+		}
+		return nil, s.lint(ctx, logE, params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		params := &didChangeParams{}
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, fmt.Errorf("parse params: %w", err)
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// The full content is sent because the server supports only the full synchronization.
+		return nil, s.lint(ctx, logE, params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+	case "textDocument/didClose":
+		params := &didCloseParams{}
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, fmt.Errorf("parse params: %w", err)
+		}
+		delete(s.findings, params.TextDocument.URI)
+		return nil, s.publishDiagnostics(params.TextDocument.URI, nil)
+	case "textDocument/codeAction":
+		params := &codeActionParams{}
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			return nil, fmt.Errorf("parse params: %w", err)
+		}
+		return s.codeActions(params), nil
+	default:
+		if strings.HasPrefix(msg.Method, "$/") {
+			// Implementation dependent notifications and requests can be ignored.
+			return nil, nil
+		}
+		return nil, &methodNotFoundError{method: msg.Method}
+	}
+}
+
+func (s *Server) lint(ctx context.Context, logE *logrus.Entry, uri, text string) error {
+	path := uriToPath(uri)
+	findings := s.linter.Lint(ctx, logE.WithField("workflow_file", path), path, text)
+	s.findings[uri] = findings
+	return s.publishDiagnostics(uri, findings)
+}
+
+func (s *Server) publishDiagnostics(uri string, findings []*run.Finding) error {
+	diagnostics := make([]*diagnostic, len(findings))
+	for i, finding := range findings {
+		diagnostics[i] = newDiagnostic(finding)
+	}
+	return s.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diagnostics,
+	})
+}
+
+func (s *Server) codeActions(params *codeActionParams) []*codeAction {
+	actions := []*codeAction{}
+	for _, finding := range s.findings[params.TextDocument.URI] {
+		if finding.Kind != run.FindingKindChanged {
+			continue
+		}
+		line := finding.Line - 1
+		if line < params.Range.Start.Line || line > params.Range.End.Line {
+			continue
+		}
+		d := newDiagnostic(finding)
+		actions = append(actions, &codeAction{
+			Title:       "pinact: " + strings.TrimSpace(finding.After),
+			Kind:        "quickfix",
+			Diagnostics: []*diagnostic{d},
+			Edit: &workspaceEdit{
+				Changes: map[string][]*textEdit{
+					params.TextDocument.URI: {
+						{
+							Range:   d.Range,
+							NewText: finding.After,
+						},
+					},
+				},
+			},
+		})
+	}
+	return actions
+}
+
+func newDiagnostic(finding *run.Finding) *diagnostic {
+	line := finding.Line - 1
+	severity := severityWarning
+	switch finding.Severity {
+	case run.SeverityError:
+		severity = severityError
+	case run.SeverityWarn:
+	default:
+		if finding.Kind == run.FindingKindError {
+			severity = severityError
+		}
+	}
+	return &diagnostic{
+		Range: &lspRange{
+			Start: &position{Line: line},
+			End:   &position{Line: line, Character: utf16Len(finding.Before)},
+		},
+		Severity: severity,
+		Source:   "pinact",
+		Message:  finding.Message,
+	}
+}
+
+// utf16Len returns the length of a string in UTF-16 code units, which LSP uses for characters by default.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 { //nolint:mnd
+			n += 2
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return u.Path
+}
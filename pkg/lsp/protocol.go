@@ -0,0 +1,70 @@
+package lsp
+
+type textDocumentItem struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type didOpenParams struct {
+	TextDocument *textDocumentItem `json:"textDocument"`
+}
+
+type didChangeParams struct {
+	TextDocument   *textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []*contentChange        `json:"contentChanges"`
+}
+
+type contentChange struct {
+	Text string `json:"text"`
+}
+
+type didCloseParams struct {
+	TextDocument *textDocumentIdentifier `json:"textDocument"`
+}
+
+type codeActionParams struct {
+	TextDocument *textDocumentIdentifier `json:"textDocument"`
+	Range        *lspRange               `json:"range"`
+}
+
+type position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start *position `json:"start"`
+	End   *position `json:"end"`
+}
+
+type diagnostic struct {
+	Range    *lspRange `json:"range"`
+	Severity int       `json:"severity"`
+	Source   string    `json:"source"`
+	Message  string    `json:"message"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string        `json:"uri"`
+	Diagnostics []*diagnostic `json:"diagnostics"`
+}
+
+type textEdit struct {
+	Range   *lspRange `json:"range"`
+	NewText string    `json:"newText"`
+}
+
+type workspaceEdit struct {
+	Changes map[string][]*textEdit `json:"changes"`
+}
+
+type codeAction struct {
+	Title       string         `json:"title"`
+	Kind        string         `json:"kind"`
+	Diagnostics []*diagnostic  `json:"diagnostics"`
+	Edit        *workspaceEdit `json:"edit"`
+}
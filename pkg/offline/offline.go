@@ -0,0 +1,34 @@
+// Package offline disables network access by the environment variable PINACT_OFFLINE.
+// It makes tests and restricted sandboxes behave deterministically.
+package offline
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// ErrOffline is returned if network access is attempted while it's disabled.
+var ErrOffline = errors.New("network access is disabled by PINACT_OFFLINE=1")
+
+// Enabled returns true if network access is disabled.
+func Enabled() bool {
+	return os.Getenv("PINACT_OFFLINE") == "1"
+}
+
+// Transport is a http.RoundTripper which always fails.
+type Transport struct{}
+
+func (Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("%w: %s %s", ErrOffline, req.Method, req.URL.Redacted())
+}
+
+// HTTPClient returns a client which always fails if network access is disabled.
+// Otherwise, it returns the given client.
+func HTTPClient(client *http.Client) *http.Client {
+	if Enabled() {
+		return &http.Client{Transport: Transport{}}
+	}
+	return client
+}
@@ -0,0 +1,82 @@
+package osv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ecosystem is the OSV ecosystem of GitHub Actions.
+const ecosystem = "GitHub Actions"
+
+// Client queries vulnerabilities by OSV.dev API.
+// https://google.github.io/osv.dev/post-v1-query/
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+func New(httpClient *http.Client, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = "https://api.osv.dev"
+	}
+	return &Client{
+		httpClient: httpClient,
+		baseURL:    baseURL,
+	}
+}
+
+type Vulnerability struct {
+	ID      string   `json:"id"`
+	Summary string   `json:"summary"`
+	Aliases []string `json:"aliases"`
+}
+
+type queryRequest struct {
+	Version string        `json:"version"`
+	Package *queryPackage `json:"package"`
+}
+
+type queryPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type queryResponse struct {
+	Vulns []*Vulnerability `json:"vulns"`
+}
+
+// Query returns vulnerabilities of the action version.
+// The version must not have the prefix "v".
+func (c *Client) Query(ctx context.Context, action, version string) ([]*Vulnerability, error) {
+	b, err := json.Marshal(&queryRequest{
+		Version: version,
+		Package: &queryPackage{
+			Name:      action,
+			Ecosystem: ecosystem,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode a request body as JSON: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/query", bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send a request to OSV: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSV returns an unexpected status code: %d", resp.StatusCode)
+	}
+	res := &queryResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, fmt.Errorf("decode a response body as JSON: %w", err)
+	}
+	return res.Vulns, nil
+}
@@ -0,0 +1,86 @@
+package osv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClient_Query(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name       string
+		statusCode int
+		body       string
+		exp        []*Vulnerability
+		isErr      bool
+	}{
+		{
+			name:       "vulnerable",
+			statusCode: http.StatusOK,
+			body:       `{"vulns": [{"id": "GHSA-mrrh-fwg8-r2c3", "summary": "tj-actions/changed-files has a potential actions output exposure", "aliases": ["CVE-2025-30066"]}]}`,
+			exp: []*Vulnerability{
+				{
+					ID:      "GHSA-mrrh-fwg8-r2c3",
+					Summary: "tj-actions/changed-files has a potential actions output exposure",
+					Aliases: []string{"CVE-2025-30066"},
+				},
+			},
+		},
+		{
+			name:       "no vulnerability",
+			statusCode: http.StatusOK,
+			body:       `{}`,
+		},
+		{
+			name:       "unexpected status code",
+			statusCode: http.StatusInternalServerError,
+			isErr:      true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/v1/query" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				req := &queryRequest{}
+				if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+					t.Error(err)
+				}
+				if diff := cmp.Diff(&queryRequest{
+					Version: "45.0.7",
+					Package: &queryPackage{
+						Name:      "tj-actions/changed-files",
+						Ecosystem: ecosystem,
+					},
+				}, req); diff != "" {
+					t.Error(diff)
+				}
+				w.WriteHeader(d.statusCode)
+				if _, err := w.Write([]byte(d.body)); err != nil {
+					t.Error(err)
+				}
+			}))
+			t.Cleanup(server.Close)
+			vulns, err := New(http.DefaultClient, server.URL).Query(context.Background(), "tj-actions/changed-files", "45.0.7")
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if diff := cmp.Diff(d.exp, vulns); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// manifestMediaTypes are media types of manifests accepted by GetDigest.
+// Manifest lists are preferred so that the digest works on any platform.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Client resolves digests of container images by the OCI Distribution API.
+// https://github.com/opencontainers/distribution-spec/blob/main/spec.md
+// Only public images are supported because tokens are requested anonymously.
+type Client struct {
+	httpClient *http.Client
+}
+
+func New(httpClient *http.Client) *Client {
+	return &Client{
+		httpClient: httpClient,
+	}
+}
+
+// Image is a reference to a container image.
+type Image struct {
+	// Registry is a host of the registry such as registry-1.docker.io.
+	Registry   string
+	Repository string
+	Tag        string
+}
+
+// ParseImage parses an image reference such as alpine:3.21 and ghcr.io/foo/bar:v1.
+// Images without registries are of Docker Hub, and the default tag is latest.
+func ParseImage(image string) (*Image, error) {
+	if image == "" {
+		return nil, errors.New("the image is empty")
+	}
+	if strings.Contains(image, "@") {
+		return nil, errors.New("the image is already pinned by a digest")
+	}
+	img := &Image{
+		Registry:   "registry-1.docker.io",
+		Repository: image,
+		Tag:        "latest",
+	}
+	if host, repo, ok := strings.Cut(image, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		img.Registry = host
+		img.Repository = repo
+	}
+	if i := strings.LastIndex(img.Repository, ":"); i != -1 {
+		img.Tag = img.Repository[i+1:]
+		img.Repository = img.Repository[:i]
+	}
+	if img.Registry == "docker.io" {
+		img.Registry = "registry-1.docker.io"
+	}
+	if img.Registry == "registry-1.docker.io" && !strings.Contains(img.Repository, "/") {
+		img.Repository = "library/" + img.Repository
+	}
+	return img, nil
+}
+
+// GetDigest returns the digest of the image such as sha256:xxx.
+func (c *Client) GetDigest(ctx context.Context, image string) (string, error) {
+	img, err := ParseImage(image)
+	if err != nil {
+		return "", err
+	}
+	u := fmt.Sprintf("https://%s/v2/%s/manifests/%s", img.Registry, img.Repository, img.Tag)
+	resp, err := c.headManifest(ctx, u, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := c.getToken(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", err
+		}
+		resp, err = c.headManifest(ctx, u, token)
+		if err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("the registry returns an unexpected status code: %d", resp.StatusCode)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", errors.New("the registry doesn't return a digest")
+	}
+	return digest, nil
+}
+
+func (c *Client) headManifest(ctx context.Context, u, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create a request: %w", err)
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send a request to the registry: %w", err)
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+// getToken requests an anonymous token by the challenge of the header WWW-Authenticate.
+// e.g. Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull"
+func (c *Client) getToken(ctx context.Context, challenge string) (string, error) {
+	params, ok := parseChallenge(challenge)
+	if !ok || params["realm"] == "" {
+		return "", fmt.Errorf("the authentication scheme isn't supported: %s", challenge)
+	}
+	u, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", fmt.Errorf("parse the realm as a URL: %w", err)
+	}
+	q := u.Query()
+	for _, k := range []string{"service", "scope"} {
+		if v := params[k]; v != "" {
+			q.Set(k, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("create a request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send a request to get a token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("the token server returns an unexpected status code: %d", resp.StatusCode)
+	}
+	res := &tokenResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return "", fmt.Errorf("decode a response body as JSON: %w", err)
+	}
+	if res.Token != "" {
+		return res.Token, nil
+	}
+	return res.AccessToken, nil
+}
+
+// parseChallenge parses parameters of a Bearer challenge.
+func parseChallenge(challenge string) (map[string]string, bool) {
+	s, ok := strings.CutPrefix(challenge, "Bearer ")
+	if !ok {
+		return nil, false
+	}
+	params := map[string]string{}
+	for s != "" {
+		k, rest, ok := strings.Cut(s, "=\"")
+		if !ok {
+			break
+		}
+		v, rest, ok := strings.Cut(rest, "\"")
+		if !ok {
+			break
+		}
+		params[strings.TrimSpace(k)] = v
+		s = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+	return params, true
+}
@@ -0,0 +1,339 @@
+// Package trace records spans of file processing and API calls and exports them by OTLP/HTTP with JSON encoding.
+// Tracing is enabled by the environment variable OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT.
+// A tracer is passed by context, so functions don't have to care whether tracing is enabled.
+package trace
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suzuki-shunsuke/pinact/pkg/offline"
+)
+
+const (
+	spanKindInternal = 1
+	spanKindClient   = 3
+	statusCodeError  = 2
+)
+
+// Tracer records spans in memory until they're exported by Flush.
+// Methods of a nil Tracer do nothing.
+type Tracer struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	version     string
+	traceID     string
+	httpClient  *http.Client
+	mu          sync.Mutex
+	spans       []*Span
+}
+
+// New returns a tracer configured by environment variables.
+// If tracing isn't enabled, it returns nil.
+func New(version string) *Tracer {
+	endpoint := getEndpoint()
+	if endpoint == "" {
+		return nil
+	}
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "pinact"
+	}
+	return &Tracer{
+		endpoint:    endpoint,
+		headers:     parseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")),
+		serviceName: serviceName,
+		version:     version,
+		traceID:     newID(16), //nolint:mnd
+		httpClient:  offline.HTTPClient(http.DefaultClient),
+	}
+}
+
+// Enabled returns true if traces are exported.
+func Enabled() bool {
+	return getEndpoint() != ""
+}
+
+func getEndpoint() string {
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	if e := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); e != "" {
+		return strings.TrimSuffix(e, "/") + "/v1/traces"
+	}
+	return ""
+}
+
+// parseHeaders parses OTEL_EXPORTER_OTLP_HEADERS. e.g. api-key=xxx,foo=bar
+// Values are URL-encoded.
+func parseHeaders(s string) map[string]string {
+	headers := map[string]string{}
+	for _, kv := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		if u, err := url.QueryUnescape(v); err == nil {
+			v = u
+		}
+		headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return headers
+}
+
+func newID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type tracerKey struct{}
+
+type spanKey struct{}
+
+// WithTracer returns a context having the tracer.
+func WithTracer(ctx context.Context, tracer *Tracer) context.Context {
+	if tracer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// Attribute is an attribute of a span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is an operation such as processing a file or calling an API.
+// Methods of a nil Span do nothing.
+type Span struct {
+	tracer     *Tracer
+	name       string
+	kind       int
+	id         string
+	parentID   string
+	start      time.Time
+	end        time.Time
+	attributes []Attribute
+	err        error
+}
+
+// Start starts a span as a child of the span in the context.
+// If the context doesn't have a tracer, it returns a nil span.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return start(ctx, name, spanKindInternal, attrs)
+}
+
+func start(ctx context.Context, name string, kind int, attrs []Attribute) (context.Context, *Span) {
+	tracer, ok := ctx.Value(tracerKey{}).(*Tracer)
+	if !ok {
+		return ctx, nil
+	}
+	span := &Span{
+		tracer:     tracer,
+		name:       name,
+		kind:       kind,
+		id:         newID(8), //nolint:mnd
+		start:      time.Now(),
+		attributes: attrs,
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+		span.parentID = parent.id
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.attributes = append(s.attributes, attrs...)
+}
+
+// SetError marks the span as failed.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.err = err
+}
+
+// End ends the span and records it to the tracer.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.end = time.Now()
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.spans = append(s.tracer.spans, s)
+}
+
+// Flush exports recorded spans.
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(t.newRequest(spans))
+	if err != nil {
+		return fmt.Errorf("encode spans as JSON: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create a request to export spans: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("export spans: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("export spans: status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// The following types are the JSON encoding of OTLP.
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+type exportRequest struct {
+	ResourceSpans []*resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   *resource     `json:"resource"`
+	ScopeSpans []*scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []*keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope *scope      `json:"scope"`
+	Spans []*spanData `json:"spans"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type spanData struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []*keyValue `json:"attributes,omitempty"`
+	Status            *status     `json:"status,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string    `json:"key"`
+	Value *anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+func newKeyValue(attr Attribute) *keyValue {
+	v := &anyValue{}
+	switch val := attr.Value.(type) {
+	case int:
+		// int64 is encoded as a string in JSON.
+		s := strconv.Itoa(val)
+		v.IntValue = &s
+	default:
+		s := fmt.Sprint(val)
+		v.StringValue = &s
+	}
+	return &keyValue{Key: attr.Key, Value: v}
+}
+
+func (t *Tracer) newRequest(spans []*Span) *exportRequest {
+	data := make([]*spanData, len(spans))
+	for i, span := range spans {
+		d := &spanData{
+			TraceID:           t.traceID,
+			SpanID:            span.id,
+			ParentSpanID:      span.parentID,
+			Name:              span.name,
+			Kind:              span.kind,
+			StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.end.UnixNano(), 10),
+		}
+		for _, attr := range span.attributes {
+			d.Attributes = append(d.Attributes, newKeyValue(attr))
+		}
+		if span.err != nil {
+			d.Status = &status{
+				Code:    statusCodeError,
+				Message: span.err.Error(),
+			}
+		}
+		data[i] = d
+	}
+	return &exportRequest{
+		ResourceSpans: []*resourceSpans{
+			{
+				Resource: &resource{
+					Attributes: []*keyValue{
+						newKeyValue(String("service.name", t.serviceName)),
+						newKeyValue(String("service.version", t.version)),
+					},
+				},
+				ScopeSpans: []*scopeSpans{
+					{
+						Scope: &scope{
+							Name:    "github.com/suzuki-shunsuke/pinact",
+							Version: t.version,
+						},
+						Spans: data,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,130 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseHeaders(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		s    string
+		exp  map[string]string
+	}{
+		{
+			name: "empty",
+			exp:  map[string]string{},
+		},
+		{
+			name: "multiple headers",
+			s:    "api-key=xxx, foo = bar,invalid",
+			exp: map[string]string{
+				"api-key": "xxx",
+				"foo":     "bar",
+			},
+		},
+		{
+			name: "url encoded",
+			s:    "Authorization=Basic%20dXNlcjpwYXNz",
+			exp: map[string]string{
+				"Authorization": "Basic dXNlcjpwYXNz",
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(d.exp, parseHeaders(d.s)); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestStart_nilTracer(t *testing.T) {
+	t.Parallel()
+	ctx, span := Start(context.Background(), "process a file")
+	if span != nil {
+		t.Fatal("a span must be nil if tracing isn't enabled")
+	}
+	// Methods of a nil span do nothing.
+	span.SetAttributes(String("file", "test.yaml"))
+	span.SetError(errors.New("error"))
+	span.End()
+	if ctx.Value(spanKey{}) != nil {
+		t.Fatal("the context must not have a span")
+	}
+	var tracer *Tracer
+	if err := tracer.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTracer_Flush(t *testing.T) {
+	t.Parallel()
+	var req *exportRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s := r.Header.Get("Api-Key"); s != "xxx" {
+			t.Errorf("headers must be sent: %s", s)
+		}
+		req = &exportRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	tracer := &Tracer{
+		endpoint:    server.URL,
+		headers:     map[string]string{"api-key": "xxx"},
+		serviceName: "pinact",
+		version:     "v3.0.0",
+		traceID:     newID(16), //nolint:mnd
+		httpClient:  http.DefaultClient,
+	}
+	ctx := WithTracer(context.Background(), tracer)
+	ctx, parent := Start(ctx, "process a file", String("file", "test.yaml"))
+	_, child := Start(ctx, "resolve a version", Int("line", 10))
+	child.SetError(errors.New("not found"))
+	child.End()
+	parent.End()
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if req == nil {
+		t.Fatal("spans must be exported")
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 { //nolint:mnd
+		t.Fatalf("wanted 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "resolve a version" || spans[0].ParentSpanID != spans[1].SpanID {
+		t.Fatalf("the child span must have the parent: %+v", spans[0])
+	}
+	if spans[0].TraceID != spans[1].TraceID {
+		t.Fatal("spans must have the same trace id")
+	}
+	if diff := cmp.Diff(&status{Code: statusCodeError, Message: "not found"}, spans[0].Status); diff != "" {
+		t.Fatal(diff)
+	}
+	if s := spans[0].Attributes[0].Value.IntValue; s == nil || *s != "10" {
+		t.Fatalf("int attributes must be encoded as strings: %v", s)
+	}
+	if spans[1].Status != nil {
+		t.Fatalf("the parent span must succeed: %+v", spans[1].Status)
+	}
+	// Spans are exported only once.
+	req = nil
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if req != nil {
+		t.Fatal("spans must not be exported twice")
+	}
+}
@@ -0,0 +1,47 @@
+package trace
+
+import (
+	"net/http"
+)
+
+// Transport is a http.RoundTripper recording a span per request.
+// Attributes of GitHub API rate limits are recorded so that the consumption of rate limits can be attributed.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx, span := start(req.Context(), req.Method+" "+req.URL.Host, spanKindClient, []Attribute{
+		String("http.request.method", req.Method),
+		String("server.address", req.URL.Host),
+		String("url.path", req.URL.Path),
+	})
+	if span == nil {
+		return base.RoundTrip(req) //nolint:wrapcheck
+	}
+	defer span.End()
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.SetError(err)
+		return resp, err //nolint:wrapcheck
+	}
+	span.SetAttributes(Int("http.response.status_code", resp.StatusCode))
+	if s := resp.Header.Get("X-Ratelimit-Resource"); s != "" {
+		span.SetAttributes(String("github.rate_limit.resource", s))
+	}
+	if s := resp.Header.Get("X-Ratelimit-Remaining"); s != "" {
+		span.SetAttributes(String("github.rate_limit.remaining", s))
+	}
+	return resp, nil
+}
+
+// HTTPClient returns a client recording spans of requests.
+func HTTPClient(client *http.Client) *http.Client {
+	c := *client
+	c.Transport = &Transport{Base: client.Transport}
+	return &c
+}
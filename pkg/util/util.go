@@ -3,3 +3,11 @@ package util
 func StrP(s string) *string {
 	return &s
 }
+
+func BoolP(b bool) *bool {
+	return &b
+}
+
+func IntP(i int) *int {
+	return &i
+}
@@ -12,12 +12,16 @@ command_console() {
 }
 
 commands() {
-  for cmd in init run; do
+  for cmd in init run stats; do
     echo "
 ## pinact $cmd
 
 $(command_console pinact help $cmd)"
   done
+  echo "
+## pinact workspace run
+
+$(command_console pinact workspace help run)"
 }
 
 echo "# Usage